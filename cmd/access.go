@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/alauda/nexus-cli/pkg/access"
+	"github.com/alauda/nexus-cli/pkg/nexus"
+	"github.com/alauda/nexus-cli/pkg/output"
+)
+
+var (
+	accessRepo   string
+	accessAction string
+	accessUser   string
+	accessOutput string
+)
+
+var accessCmd = &cobra.Command{
+	Use:   "access",
+	Short: "Analyze effective repository permissions",
+	Long: `Access resolves users to roles, nested roles and privileges (including wildcard
+privileges such as nx-repository-view-*-*-* and nexus:*) to answer who can do what
+on which repository. Only local users are enumerated by who-can and matrix;
+check also works for LDAP users.`,
+}
+
+var accessWhoCanCmd = &cobra.Command{
+	Use:   "who-can",
+	Short: "List users who can perform an action on a repository",
+	Example: `  # Who can deploy to maven-releases?
+  nexus-cli access who-can --repo maven-releases --action edit`,
+	Args: cobra.NoArgs,
+	RunE: runAccessWhoCan,
+}
+
+var accessCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check whether a user can perform an action on a repository",
+	Long: `Check prints how the user is granted the action, or exits with an error
+when the user has no access.`,
+	Example: `  nexus-cli access check --user dev-user --repo maven-releases --action read`,
+	Args:    cobra.NoArgs,
+	RunE:    runAccessCheck,
+}
+
+var accessMatrixCmd = &cobra.Command{
+	Use:   "matrix",
+	Short: "Report every user × repository × action",
+	Example: `  # Markdown report for all repositories
+  nexus-cli access matrix -o md > access.md
+
+  # CSV report for team1 repositories only
+  nexus-cli access matrix --repo 'team1-*' -o csv`,
+	Args: cobra.NoArgs,
+	RunE: runAccessMatrix,
+}
+
+func init() {
+	rootCmd.AddCommand(accessCmd)
+	accessCmd.AddCommand(accessWhoCanCmd, accessCheckCmd, accessMatrixCmd)
+
+	accessWhoCanCmd.Flags().StringVar(&accessRepo, "repo", "", "Repository name (required)")
+	accessWhoCanCmd.Flags().StringVar(&accessAction, "action", "", "Action: "+strings.Join(access.Actions, "|")+" (required)")
+	accessWhoCanCmd.Flags().StringVarP(&accessOutput, "output", "o", "text", "Output format (text|json|yaml)")
+	_ = accessWhoCanCmd.MarkFlagRequired("repo")
+	_ = accessWhoCanCmd.MarkFlagRequired("action")
+
+	accessCheckCmd.Flags().StringVar(&accessUser, "user", "", "User ID (required)")
+	accessCheckCmd.Flags().StringVar(&accessRepo, "repo", "", "Repository name (required)")
+	accessCheckCmd.Flags().StringVar(&accessAction, "action", "", "Action: "+strings.Join(access.Actions, "|")+" (required)")
+	accessCheckCmd.Flags().StringVarP(&accessOutput, "output", "o", "text", "Output format (text|json|yaml)")
+	_ = accessCheckCmd.MarkFlagRequired("user")
+	_ = accessCheckCmd.MarkFlagRequired("repo")
+	_ = accessCheckCmd.MarkFlagRequired("action")
+
+	accessMatrixCmd.Flags().StringVar(&accessRepo, "repo", "", "Only include repositories matching this pattern (e.g. team1-*)")
+	accessMatrixCmd.Flags().StringVarP(&accessOutput, "output", "o", "md", "Output format (md|csv|json|yaml)")
+}
+
+// accessData 计算有效权限所需的服务器数据
+type accessData struct {
+	model *access.Model
+	repos map[string]access.Repository
+}
+
+// loadAccessData 从服务器加载角色、权限和仓库
+func loadAccessData(client *nexus.Client) (*accessData, error) {
+	roles, err := client.ListRoles()
+	if err != nil {
+		return nil, err
+	}
+	privileges, err := client.ListPrivileges()
+	if err != nil {
+		return nil, err
+	}
+	list, err := client.ListRepositories()
+	if err != nil {
+		return nil, err
+	}
+
+	repos := make(map[string]access.Repository, len(list))
+	for _, r := range list {
+		repos[r.Name] = access.Repository{Name: r.Name, Format: r.Format}
+	}
+	return &accessData{model: access.NewModel(roles, privileges), repos: repos}, nil
+}
+
+// repository 按名称查找仓库
+func (d *accessData) repository(name string) (access.Repository, error) {
+	repo, ok := d.repos[name]
+	if !ok {
+		return access.Repository{}, fmt.Errorf("repository %s not found", name)
+	}
+	return repo, nil
+}
+
+func validateAccessAction() error {
+	if !access.IsAction(accessAction) {
+		return fmt.Errorf("invalid --action %q (must be one of %s)", accessAction, strings.Join(access.Actions, ", "))
+	}
+	accessAction = strings.ToLower(accessAction)
+	return nil
+}
+
+func runAccessWhoCan(_ *cobra.Command, _ []string) error {
+	if err := validateAccessAction(); err != nil {
+		return err
+	}
+	status := output.NewFormatter(output.FormatText, os.Stderr)
+	client, err := connectNexus(status)
+	if err != nil {
+		return err
+	}
+
+	data, err := loadAccessData(client)
+	if err != nil {
+		return err
+	}
+	repo, err := data.repository(accessRepo)
+	if err != nil {
+		return err
+	}
+	users, err := client.ListUsers(nexus.UserFilter{})
+	if err != nil {
+		return err
+	}
+
+	result := data.model.WhoCan(users, repo, accessAction)
+
+	formatter := output.NewFormatter(output.Format(accessOutput), os.Stdout)
+	if accessOutput != string(output.FormatText) {
+		return formatter.Output(result)
+	}
+
+	if len(result) == 0 {
+		formatter.Print(fmt.Sprintf("No users can %s %s", accessAction, accessRepo))
+		return nil
+	}
+	formatter.Print(fmt.Sprintf("Users who can %s %s:", accessAction, accessRepo))
+	for _, ua := range result {
+		for i, g := range ua.Grants {
+			name := ua.UserID
+			if i > 0 {
+				name = strings.Repeat(" ", len(ua.UserID))
+			}
+			formatter.Print(fmt.Sprintf("  %s  via %s", name, describeGrant(g)))
+		}
+	}
+	return nil
+}
+
+func runAccessCheck(_ *cobra.Command, _ []string) error {
+	if err := validateAccessAction(); err != nil {
+		return err
+	}
+	status := output.NewFormatter(output.FormatText, os.Stderr)
+	client, err := connectNexus(status)
+	if err != nil {
+		return err
+	}
+
+	data, err := loadAccessData(client)
+	if err != nil {
+		return err
+	}
+	repo, err := data.repository(accessRepo)
+	if err != nil {
+		return err
+	}
+	user, err := client.GetUser(accessUser)
+	if err != nil {
+		return err
+	}
+
+	grants := data.model.Check(access.UserRoles(*user), repo, accessAction)
+
+	formatter := output.NewFormatter(output.Format(accessOutput), os.Stdout)
+	if accessOutput != string(output.FormatText) {
+		if err := formatter.Output(access.UserAccess{UserID: user.UserID, Source: user.Source, Grants: grants}); err != nil {
+			return err
+		}
+	} else if len(grants) > 0 {
+		formatter.Print(fmt.Sprintf("ALLOWED: %s can %s %s", accessUser, accessAction, accessRepo))
+		for _, g := range grants {
+			formatter.Print(fmt.Sprintf("  via %s", describeGrant(g)))
+		}
+	}
+
+	if len(grants) == 0 {
+		return fmt.Errorf("DENIED: %s cannot %s %s", accessUser, accessAction, accessRepo)
+	}
+	return nil
+}
+
+func runAccessMatrix(_ *cobra.Command, _ []string) error {
+	status := output.NewFormatter(output.FormatText, os.Stderr)
+	client, err := connectNexus(status)
+	if err != nil {
+		return err
+	}
+
+	data, err := loadAccessData(client)
+	if err != nil {
+		return err
+	}
+	users, err := client.ListUsers(nexus.UserFilter{})
+	if err != nil {
+		return err
+	}
+
+	var repos []access.Repository
+	for name, repo := range data.repos {
+		if accessRepo != "" {
+			ok, err := path.Match(accessRepo, name)
+			if err != nil {
+				return fmt.Errorf("invalid --repo pattern: %w", err)
+			}
+			if !ok {
+				continue
+			}
+		}
+		repos = append(repos, repo)
+	}
+	sort.Slice(repos, func(i, j int) bool { return repos[i].Name < repos[j].Name })
+
+	rows := data.model.Matrix(users, repos)
+
+	switch accessOutput {
+	case "csv":
+		return access.WriteCSV(os.Stdout, rows)
+	case "md", "markdown":
+		return access.WriteMarkdown(os.Stdout, rows)
+	case string(output.FormatJSON), string(output.FormatYAML):
+		return output.NewFormatter(output.Format(accessOutput), os.Stdout).Output(rows)
+	default:
+		return fmt.Errorf("invalid output format %q (must be md, csv, json or yaml)", accessOutput)
+	}
+}
+
+// describeGrant 返回授权路径的文本描述
+func describeGrant(g access.Grant) string {
+	desc := fmt.Sprintf("%s (%s)", strings.Join(g.Roles, " > "), g.Privilege)
+	if g.ContentSelector != "" {
+		desc += fmt.Sprintf(" limited to content selector %s", g.ContentSelector)
+	}
+	return desc
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/alauda/nexus-cli/pkg/config"
+	"github.com/alauda/nexus-cli/pkg/nexus"
+	"github.com/alauda/nexus-cli/pkg/output"
+	"github.com/alauda/nexus-cli/pkg/service"
+)
+
+var (
+	cleanupRepository string
+	cleanupLimit      int
+	cleanupOutput     string
+)
+
+var cleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Inspect cleanup policies",
+}
+
+var cleanupPreviewCmd = &cobra.Command{
+	Use:   "preview <policy>",
+	Short: "Preview which components a cleanup policy would delete (experimental)",
+	Long: `Preview reports the components in a repository that match a cleanup policy.
+The policy is taken from the config file when given (so it can be checked before
+it is applied), otherwise it is read from the Nexus server.
+
+This command is experimental: Nexus has no public API for cleanup previews, so it
+uses the internal API behind the preview in the UI (Nexus 3.29.0 or later), which
+may change between Nexus releases.`,
+	Example: `  # Preview a policy defined on the server
+  nexus-cli cleanup preview snapshots-30d --repository maven-snapshots
+
+  # Preview a policy from the config file before applying it
+  nexus-cli cleanup preview snapshots-30d --repository maven-snapshots -c config.yaml -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCleanupPreview,
+}
+
+func init() {
+	rootCmd.AddCommand(cleanupCmd)
+	cleanupCmd.AddCommand(cleanupPreviewCmd)
+	cleanupPreviewCmd.Flags().StringVarP(&cleanupRepository, "repository", "r", "", "Repository to preview (required)")
+	cleanupPreviewCmd.Flags().IntVar(&cleanupLimit, "limit", 100, "Maximum number of components to list")
+	cleanupPreviewCmd.Flags().StringVarP(&cleanupOutput, "output", "o", "text", "Output format (text|json|yaml)")
+	_ = cleanupPreviewCmd.MarkFlagRequired("repository")
+}
+
+func runCleanupPreview(_ *cobra.Command, args []string) error {
+	policyName := args[0]
+	status := output.NewFormatter(output.FormatText, os.Stderr)
+
+	client, err := connectNexus(status)
+	if err != nil {
+		return err
+	}
+
+	policy, err := findCleanupPolicy(client, policyName)
+	if err != nil {
+		return err
+	}
+
+	status.Warning("cleanup preview is experimental and uses an internal Nexus API")
+	result, err := client.PreviewCleanupPolicy(cleanupRepository, *policy, cleanupLimit)
+	if err != nil {
+		return err
+	}
+
+	formatter := output.NewFormatter(output.Format(cleanupOutput), os.Stdout)
+	if cleanupOutput != string(output.FormatText) {
+		return formatter.Output(result)
+	}
+
+	formatter.Print(fmt.Sprintf("Cleanup policy %s would delete %d component(s) from %s:", policyName, result.Total, cleanupRepository))
+	for _, c := range result.Results {
+		if c.Group != "" {
+			formatter.Print(fmt.Sprintf("  - %s:%s:%s", c.Group, c.Name, c.Version))
+		} else {
+			formatter.Print(fmt.Sprintf("  - %s@%s", c.Name, c.Version))
+		}
+	}
+	if result.Total > len(result.Results) {
+		formatter.Print(fmt.Sprintf("  ... and %d more (use --limit to show more)", result.Total-len(result.Results)))
+	}
+	return nil
+}
+
+// findCleanupPolicy 优先从配置文件查找清理策略，否则从服务器获取
+func findCleanupPolicy(client *nexus.Client, name string) (*nexus.CleanupPolicyRequest, error) {
+	if cfgFile != "" {
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+		for _, p := range cfg.CleanupPolicies {
+			if p.Name == name {
+				req := service.BuildCleanupPolicyRequest(p)
+				return &req, nil
+			}
+		}
+	}
+
+	return client.GetCleanupPolicy(name)
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/alauda/nexus-cli/pkg/config"
+	"github.com/alauda/nexus-cli/pkg/nexus"
+	"github.com/alauda/nexus-cli/pkg/output"
+)
+
+// connectNexus 根据环境变量创建 Nexus 客户端并检查连接
+func connectNexus(formatter *output.Formatter) (*nexus.Client, error) {
+	return connectNexusProfile(formatter, config.DefaultProfile)
+}
+
+// connectNexusProfile 根据 <profile>_URL 等环境变量创建 Nexus 客户端并检查连接
+func connectNexusProfile(formatter *output.Formatter, profile string) (*nexus.Client, error) {
+	url, username, password, err := config.GetNexusProfileCredentials(profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Nexus credentials: %w", err)
+	}
+
+	formatter.Info(fmt.Sprintf("Connecting to Nexus at %s...", url))
+
+	client := nexus.NewClient(url, username, password)
+	if err := client.CheckConnection(); err != nil {
+		return nil, fmt.Errorf("failed to connect to Nexus: %w", err)
+	}
+
+	formatter.Success("Successfully connected to Nexus")
+	return client, nil
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/alauda/nexus-cli/pkg/nexus"
+	"github.com/alauda/nexus-cli/pkg/output"
+	"github.com/alauda/nexus-cli/pkg/purge"
+)
+
+var (
+	componentsDeleteValues    map[string]*string
+	componentsDeleteConfirm   int
+	componentsDeleteBatchSize int
+	componentsDeleteParallel  int
+	componentsDeleteJournal   string
+	componentsDeleteResume    string
+	componentsDeleteOutput    string
+	componentsDeleteColumns   string
+)
+
+var componentsCmd = &cobra.Command{
+	Use:   "components",
+	Short: "Manage repository components",
+}
+
+var componentsDeleteCmd = &cobra.Command{
+	Use:   "delete [keyword]",
+	Short: "Delete the components matching search criteria",
+	Long: `Delete removes every component matching the search criteria, for one-off purges that
+cleanup policies do not cover. The criteria are the same as for "search".
+
+Without --confirm the command only prints the matching components and their total size.
+To delete them, run it again with --confirm set to the number of matching components; if
+the number changed in the meantime nothing is deleted.
+
+Components are deleted in batches (--batch-size) with at most --parallel concurrent requests.
+Every deleted component is recorded in a journal file; an interrupted or partly failed purge
+continues with --resume <journal> and deletes only what is left.`,
+	Example: `  # Preview all 1.0.0-SNAPSHOT builds of com.example.*
+  nexus-cli components delete --repository maven-snapshots --maven.groupId 'com.example*' --version 1.0.0-SNAPSHOT
+
+  # Delete them after checking the preview (it reported 42 components)
+  nexus-cli components delete --repository maven-snapshots --maven.groupId 'com.example*' --version 1.0.0-SNAPSHOT --confirm 42
+
+  # Continue an interrupted purge
+  nexus-cli components delete --resume components-delete-20240101-120000.journal`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runComponentsDelete,
+}
+
+func init() {
+	rootCmd.AddCommand(componentsCmd)
+	componentsCmd.AddCommand(componentsDeleteCmd)
+	componentsDeleteValues = addSearchFlags(componentsDeleteCmd)
+	componentsDeleteCmd.Flags().IntVar(&componentsDeleteConfirm, "confirm", 0, "Delete the components; must equal the number of matching components")
+	componentsDeleteCmd.Flags().IntVar(&componentsDeleteBatchSize, "batch-size", 100, "Number of components deleted before the journal is updated")
+	componentsDeleteCmd.Flags().IntVar(&componentsDeleteParallel, "parallel", 4, "Number of concurrent delete requests")
+	componentsDeleteCmd.Flags().StringVar(&componentsDeleteJournal, "journal", "", "Journal file (default: components-delete-<time>.journal)")
+	componentsDeleteCmd.Flags().StringVar(&componentsDeleteResume, "resume", "", "Continue the purge recorded in a journal file")
+	componentsDeleteCmd.Flags().StringVarP(&componentsDeleteOutput, "output", "o", "table", "Output format of the preview (text|json|yaml|table)")
+	componentsDeleteCmd.Flags().StringVar(&componentsDeleteColumns, "columns", "", "Comma separated columns of the preview")
+}
+
+func runComponentsDelete(cmd *cobra.Command, args []string) error {
+	if componentsDeleteBatchSize < 1 || componentsDeleteParallel < 1 {
+		return fmt.Errorf("--batch-size and --parallel must be at least 1")
+	}
+	status := output.NewFormatter(output.FormatText, os.Stderr)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if componentsDeleteResume != "" {
+		for _, name := range []string{"confirm", "journal"} {
+			if cmd.Flags().Changed(name) {
+				return fmt.Errorf("--resume cannot be combined with --%s", name)
+			}
+		}
+		if _, err := searchCriteria(args, componentsDeleteValues); err == nil {
+			return fmt.Errorf("--resume cannot be combined with search criteria, the journal already lists the components")
+		}
+		journal, err := purge.Open(componentsDeleteResume)
+		if err != nil {
+			return err
+		}
+		defer journal.Close()
+		client, err := connectNexus(status)
+		if err != nil {
+			return err
+		}
+		status.Info(fmt.Sprintf("Resuming purge of %s (%d of %d components left)", journal.Query, len(journal.Remaining()), len(journal.Entries)))
+		return runPurge(ctx, status, client, journal)
+	}
+
+	query, err := searchCriteria(args, componentsDeleteValues)
+	if err != nil {
+		return err
+	}
+	preview, err := newResourceFormatter(componentsDeleteOutput, "")
+	if err != nil {
+		return err
+	}
+	preview.SetColumns([]string{"repository", "group", "name", "version", "size"})
+	if componentsDeleteColumns != "" {
+		preview.SetColumns(strings.Split(componentsDeleteColumns, ","))
+	}
+
+	client, err := connectNexus(status)
+	if err != nil {
+		return err
+	}
+	components, _, err := collect(client.SearchComponents(ctx, query), 0)
+	if err != nil {
+		return err
+	}
+	if len(components) == 0 {
+		status.Info("No components match")
+		return nil
+	}
+
+	entries := make([]purge.Entry, 0, len(components))
+	var size int64
+	for _, c := range components {
+		entry := purge.Entry{ID: c.ID, Repository: c.Repository, Group: c.Group, Name: c.Name, Version: c.Version}
+		for _, asset := range c.Assets {
+			entry.Size += asset.FileSize
+		}
+		size += entry.Size
+		entries = append(entries, entry)
+	}
+	rows, err := output.ToRows(entries)
+	if err != nil {
+		return err
+	}
+	if err := preview.Output(rows); err != nil {
+		return err
+	}
+	status.Info(fmt.Sprintf("%d components (%s) match", len(entries), formatByteSize(size)))
+
+	if !cmd.Flags().Changed("confirm") {
+		status.Warning(fmt.Sprintf("Nothing was deleted, run again with --confirm %d to delete these components", len(entries)))
+		return nil
+	}
+	if componentsDeleteConfirm != len(entries) {
+		return fmt.Errorf("--confirm %d does not match the %d matching components, check the preview again", componentsDeleteConfirm, len(entries))
+	}
+
+	journalFile := componentsDeleteJournal
+	if journalFile == "" {
+		journalFile = fmt.Sprintf("components-delete-%s.journal", time.Now().Format("20060102-150405"))
+	}
+	journal, err := purge.Create(journalFile, query.Encode(), entries)
+	if err != nil {
+		return err
+	}
+	defer journal.Close()
+	status.Info(fmt.Sprintf("Recording progress in %s", journalFile))
+	return runPurge(ctx, status, client, journal)
+}
+
+// runPurge 删除日志中剩余的组件，已不存在的组件视为已删除
+func runPurge(ctx context.Context, status *output.Formatter, client *nexus.Client, journal *purge.Journal) error {
+	del := func(ctx context.Context, entry purge.Entry) error {
+		err := client.DeleteComponent(ctx, entry.ID)
+		if err != nil && strings.Contains(err.Error(), "status 404") {
+			return nil
+		}
+		return err
+	}
+	progress := func(done, total int) {
+		status.Info(fmt.Sprintf("Deleted %d/%d components", done, total))
+	}
+
+	failures, err := purge.Run(ctx, journal, componentsDeleteBatchSize, componentsDeleteParallel, del, progress)
+	for _, f := range failures {
+		if ctx.Err() == nil {
+			status.Error(fmt.Sprintf("%s %s:%s:%s: %v", f.Entry.Repository, f.Entry.Group, f.Entry.Name, f.Entry.Version, f.Err))
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("purge interrupted, continue with --resume %s: %w", journal.Path, err)
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%d components could not be deleted, retry with --resume %s", len(failures), journal.Path)
+	}
+	status.Success(fmt.Sprintf("Deleted %d components", len(journal.Entries)))
+	return nil
+}
@@ -16,7 +16,9 @@ import (
 	"github.com/alauda/nexus-cli/pkg/config"
 	"github.com/alauda/nexus-cli/pkg/nexus"
 	"github.com/alauda/nexus-cli/pkg/output"
+	"github.com/alauda/nexus-cli/pkg/password"
 	"github.com/alauda/nexus-cli/pkg/service"
+	"github.com/alauda/nexus-cli/pkg/state"
 )
 
 var (
@@ -24,6 +26,7 @@ var (
 	outputTemplate string
 	outputFile     string
 	quiet          bool
+	forceRealms    bool
 )
 
 var createCmd = &cobra.Command{
@@ -63,6 +66,7 @@ func init() {
 	createCmd.Flags().StringVar(&outputTemplate, "output-template", "", "Template file to format resource output")
 	createCmd.Flags().StringVar(&outputFile, "output-file", "", "File to write resource output (stdout if not specified)")
 	createCmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "Quiet mode - only show errors")
+	createCmd.Flags().BoolVar(&forceRealms, "force-realms", false, "Apply security.realms even if it does not contain NexusAuthenticatingRealm (locks out local users)")
 }
 
 func runCreate(_ *cobra.Command, _ []string) error {
@@ -117,8 +121,16 @@ func runCreate(_ *cobra.Command, _ []string) error {
 
 	// 创建服务并执行
 	svc := service.NewApplyService(client, cfg, formatter)
+	svc.SetConfigName(config.NameFromPath(cfgFile))
+	svc.SetAllowLocalRealmRemoval(forceRealms)
 	result, err := svc.Apply()
 	if err != nil {
+		// 失败前已创建的用户的密码不会再生成，先写入 sink 再返回错误；不会输出资源，template sink 退回到标准输出
+		if result != nil {
+			if storeErr := storeGeneratedPasswords(formatter, cfg, result.GeneratedPasswords, url, false); storeErr != nil {
+				formatter.Error(storeErr.Error())
+			}
+		}
 		return fmt.Errorf("failed to create resources: %w", err)
 	}
 
@@ -142,18 +154,87 @@ func runCreate(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
+	// 将生成的密码写入配置的 sink
+	resourceOutput := outputTemplate != "" || outputFile != ""
+	if err := storeGeneratedPasswords(formatter, cfg, result.GeneratedPasswords, url, resourceOutput); err != nil {
+		return err
+	}
+
 	// 如果指定了输出文件或输出模板，则输出资源列表
-	if outputTemplate != "" || outputFile != "" {
+	if resourceOutput {
+		passwords := userPasswords(cfg, result.GeneratedPasswords)
 		// 如果没有指定模板，使用默认的 YAML 格式输出（与配置文件格式一致）
 		if outputTemplate == "" {
-			return outputResourcesDefault(client, cfg, outputFile)
+			return outputResourcesDefault(client, cfg, passwords, outputFile)
 		}
-		return outputResources(client, cfg, outputTemplate, outputFile)
+		return outputResources(client, cfg, passwords, outputTemplate, outputFile)
 	}
 
 	return nil
 }
 
+// userPasswords 返回资源输出中使用的用户密码，生成的密码只在 sink 为 template 时输出
+func userPasswords(cfg *config.Config, generated map[string]string) map[string]string {
+	passwords := make(map[string]string)
+	for _, u := range cfg.Users {
+		if gen := u.Password.Generate; gen != nil {
+			if pass, ok := generated[u.ID]; ok && gen.SinkName() == config.PasswordSinkTemplate {
+				passwords[u.ID] = pass
+			}
+			continue
+		}
+		if pass, err := u.Password.Resolve(); err == nil {
+			passwords[u.ID] = pass
+		}
+	}
+	return passwords
+}
+
+// storeGeneratedPasswords 将创建用户时生成的密码写入各自的 sink，并记录轮换时间
+func storeGeneratedPasswords(formatter *output.Formatter, cfg *config.Config, generated map[string]string, instance string, resourceOutput bool) error {
+	if len(generated) == 0 {
+		return nil
+	}
+
+	st, err := state.Load(state.DefaultPath())
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, u := range cfg.Users {
+		pass, ok := generated[u.ID]
+		if !ok {
+			continue
+		}
+		cred := password.Credential{UserID: u.ID, Password: pass}
+
+		switch u.Password.Generate.SinkName() {
+		case config.PasswordSinkFile:
+			if err := password.WriteFile(u.Password.Generate.File, cred); err != nil {
+				return fmt.Errorf("failed to store generated password for user %s: %w", u.ID, err)
+			}
+			formatter.Success(fmt.Sprintf("Generated password for user %s written to %s", u.ID, u.Password.Generate.File))
+		case config.PasswordSinkTemplate:
+			if resourceOutput {
+				break
+			}
+			// 没有资源输出时密码会丢失，退回到标准输出
+			formatter.Warning(fmt.Sprintf("User %s uses the template password sink but no --output-template or --output-file was given; printing it once", u.ID))
+			if err := password.Print(os.Stdout, cred); err != nil {
+				return err
+			}
+		default:
+			if err := password.Print(os.Stdout, cred); err != nil {
+				return err
+			}
+		}
+		st.RecordPasswordRotation(instance, u.ID, now)
+	}
+
+	return st.Save()
+}
+
 // RepositoryOutput 仓库输出结构
 type RepositoryOutput struct {
 	Name                        string `json:"name"`
@@ -164,6 +245,66 @@ type RepositoryOutput struct {
 	BlobStoreName               string `json:"blobStoreName"`
 	WritePolicy                 string `json:"writePolicy,omitempty"`
 	StrictContentTypeValidation bool   `json:"strictContentTypeValidation"`
+	// RemoteURL proxy 仓库的上游地址
+	RemoteURL string `json:"remoteUrl,omitempty" yaml:"remoteurl,omitempty"`
+	// MemberNames group 仓库的成员
+	MemberNames []string `json:"memberNames,omitempty" yaml:"membernames,omitempty"`
+	RoutingRule string   `json:"routingRule,omitempty" yaml:"routingrule,omitempty"`
+	// 格式相关设置，不适用的为 nil
+	Maven       *nexus.MavenSettings       `json:"maven,omitempty" yaml:"maven,omitempty"`
+	Docker      *nexus.DockerSettings      `json:"docker,omitempty" yaml:"docker,omitempty"`
+	DockerProxy *nexus.DockerProxySettings `json:"dockerProxy,omitempty" yaml:"dockerproxy,omitempty"`
+	Apt         *nexus.AptSettings         `json:"apt,omitempty" yaml:"apt,omitempty"`
+	Npm         *nexus.NpmSettings         `json:"npm,omitempty" yaml:"npm,omitempty"`
+	Raw         *nexus.RawSettings         `json:"raw,omitempty" yaml:"raw,omitempty"`
+	Yum         *nexus.YumSettings         `json:"yum,omitempty" yaml:"yum,omitempty"`
+	// Repository 完整的仓库配置，模板中可以通过 .Repository 访问其他设置
+	Repository *nexus.Repository `json:"-" yaml:"-"`
+}
+
+// newRepositoryOutput 从仓库配置构造输出结构
+func newRepositoryOutput(repo *nexus.Repository) RepositoryOutput {
+	out := RepositoryOutput{
+		Name:        repo.Name,
+		Format:      repo.Format,
+		Type:        repo.Type,
+		Online:      repo.Online,
+		URL:         repo.URL,
+		RoutingRule: repo.RoutingRule,
+		Maven:       repo.Maven,
+		Docker:      repo.Docker,
+		DockerProxy: repo.DockerProxy,
+		Apt:         repo.Apt,
+		Npm:         repo.Npm,
+		Raw:         repo.Raw,
+		Yum:         repo.Yum,
+		Repository:  repo,
+	}
+	if repo.Storage != nil {
+		out.BlobStoreName = repo.Storage.BlobStoreName
+		out.StrictContentTypeValidation = repo.Storage.StrictContentTypeValidation
+		out.WritePolicy = repo.Storage.WritePolicy
+	}
+	if repo.Proxy != nil {
+		out.RemoteURL = repo.Proxy.RemoteURL
+	}
+	if repo.Group != nil {
+		out.MemberNames = repo.Group.MemberNames
+	}
+	return out
+}
+
+// repositoryOutputs 获取配置中声明的仓库，跳过获取失败的仓库
+func repositoryOutputs(client *nexus.Client, cfg *config.Config) []RepositoryOutput {
+	repos := []RepositoryOutput{}
+	for _, r := range cfg.Repositories {
+		repo, err := client.GetRepository(r.Name)
+		if err != nil {
+			continue
+		}
+		repos = append(repos, newRepositoryOutput(repo))
+	}
+	return repos
 }
 
 // UserWithPassword 带密码的用户信息（用于模板输出）
@@ -185,7 +326,7 @@ type OutputConfig struct {
 }
 
 // outputResourcesDefault 使用默认 YAML 格式输出资源（与配置文件格式一致）
-func outputResourcesDefault(client *nexus.Client, cfg *config.Config, outputFile string) error {
+func outputResourcesDefault(client *nexus.Client, cfg *config.Config, userPasswordMap map[string]string, outputFile string) error {
 	// 创建默认输出结构（与 config.Config 格式一致）
 	defaultOutput := struct {
 		Users        []*UserWithPassword               `yaml:"users,omitempty"`
@@ -195,12 +336,6 @@ func outputResourcesDefault(client *nexus.Client, cfg *config.Config, outputFile
 		Permissions  []config.UserRepositoryPermission `yaml:"userRepositoryPermissions,omitempty"`
 	}{}
 
-	// 创建用户ID到密码的映射
-	userPasswordMap := make(map[string]string)
-	for _, u := range cfg.Users {
-		userPasswordMap[u.ID] = u.Password
-	}
-
 	// 获取用户列表
 	if len(cfg.Users) > 0 {
 		for _, u := range cfg.Users {
@@ -218,25 +353,7 @@ func outputResourcesDefault(client *nexus.Client, cfg *config.Config, outputFile
 
 	// 获取仓库列表
 	if len(cfg.Repositories) > 0 {
-		for _, r := range cfg.Repositories {
-			repo, err := client.GetRepository(r.Name)
-			if err != nil {
-				continue
-			}
-			repoOut := RepositoryOutput{
-				Name:   getString(repo, "name"),
-				Format: getString(repo, "format"),
-				Type:   getString(repo, "type"),
-				Online: getBool(repo, "online"),
-				URL:    getString(repo, "url"),
-			}
-			if storage, ok := repo["storage"].(map[string]interface{}); ok {
-				repoOut.BlobStoreName = getString(storage, "blobStoreName")
-				repoOut.StrictContentTypeValidation = getBool(storage, "strictContentTypeValidation")
-				repoOut.WritePolicy = getString(storage, "writePolicy")
-			}
-			defaultOutput.Repositories = append(defaultOutput.Repositories, repoOut)
-		}
+		defaultOutput.Repositories = repositoryOutputs(client, cfg)
 	}
 
 	// 获取角色列表
@@ -298,7 +415,7 @@ func outputResourcesDefault(client *nexus.Client, cfg *config.Config, outputFile
 }
 
 // outputResources 输出资源列表
-func outputResources(client *nexus.Client, cfg *config.Config, templateFile, outputFile string) error {
+func outputResources(client *nexus.Client, cfg *config.Config, userPasswordMap map[string]string, templateFile, outputFile string) error {
 	// 加载模板文件
 	templateData, err := os.ReadFile(templateFile)
 	if err != nil {
@@ -338,7 +455,7 @@ func outputResources(client *nexus.Client, cfg *config.Config, templateFile, out
 	}
 
 	// 否则使用新的整体模板格式
-	return outputResourcesToolchain(client, cfg, string(templateData), outputFile)
+	return outputResourcesToolchain(client, cfg, userPasswordMap, string(templateData), outputFile)
 }
 
 // outputResourcesLegacy 使用旧的分段模板格式输出资源
@@ -368,27 +485,7 @@ func outputResourcesLegacy(client *nexus.Client, cfg *config.Config, templates s
 
 	// 获取仓库列表
 	if templates.Repositories != "" && len(cfg.Repositories) > 0 {
-		var repos []RepositoryOutput
-		for _, r := range cfg.Repositories {
-			repo, err := client.GetRepository(r.Name)
-			if err != nil {
-				continue
-			}
-			// 转换 map 到结构体
-			repoOut := RepositoryOutput{
-				Name:   getString(repo, "name"),
-				Format: getString(repo, "format"),
-				Type:   getString(repo, "type"),
-				Online: getBool(repo, "online"),
-				URL:    getString(repo, "url"),
-			}
-			if storage, ok := repo["storage"].(map[string]interface{}); ok {
-				repoOut.BlobStoreName = getString(storage, "blobStoreName")
-				repoOut.StrictContentTypeValidation = getBool(storage, "strictContentTypeValidation")
-				repoOut.WritePolicy = getString(storage, "writePolicy")
-			}
-			repos = append(repos, repoOut)
-		}
+		repos := repositoryOutputs(client, cfg)
 		if rendered, err := renderTemplate("repositories", templates.Repositories, repos); err == nil {
 			resources["repositories"] = rendered
 		}
@@ -455,17 +552,16 @@ func outputResourcesLegacy(client *nexus.Client, cfg *config.Config, templates s
 	return nil
 }
 
-// outputResourcesToolchain 使用整体模板格式输出资源（类似 gitlab-cli）
-func outputResourcesToolchain(client *nexus.Client, cfg *config.Config, templateContent, outputFile string) error {
-	// 解析 NEXUS_URL 环境变量以获取 endpoint, host, port, scheme
+// newOutputConfig 解析 NEXUS_URL 环境变量，返回包含 endpoint、host、port、scheme 的空输出配置
+func newOutputConfig() (OutputConfig, error) {
 	nexusURL := os.Getenv("NEXUS_URL")
 	if nexusURL == "" {
-		return fmt.Errorf("NEXUS_URL environment variable is not set")
+		return OutputConfig{}, fmt.Errorf("NEXUS_URL environment variable is not set")
 	}
 
 	parsedURL, err := url.Parse(nexusURL)
 	if err != nil {
-		return fmt.Errorf("failed to parse NEXUS_URL: %w", err)
+		return OutputConfig{}, fmt.Errorf("failed to parse NEXUS_URL: %w", err)
 	}
 
 	// 提取 host 和 port
@@ -477,8 +573,7 @@ func outputResourcesToolchain(client *nexus.Client, cfg *config.Config, template
 		port = 80
 	}
 
-	// 准备输出配置
-	outputCfg := OutputConfig{
+	return OutputConfig{
 		Endpoint:     nexusURL,
 		Host:         host,
 		Port:         port,
@@ -487,12 +582,15 @@ func outputResourcesToolchain(client *nexus.Client, cfg *config.Config, template
 		Repositories: []RepositoryOutput{},
 		Roles:        []*nexus.RoleResponse{},
 		Privileges:   []*nexus.PrivilegeResponse{},
-	}
+	}, nil
+}
 
-	// 创建用户ID到密码的映射
-	userPasswordMap := make(map[string]string)
-	for _, u := range cfg.Users {
-		userPasswordMap[u.ID] = u.Password
+// outputResourcesToolchain 使用整体模板格式输出资源（类似 gitlab-cli）
+func outputResourcesToolchain(client *nexus.Client, cfg *config.Config, userPasswordMap map[string]string, templateContent, outputFile string) error {
+	// 准备输出配置
+	outputCfg, err := newOutputConfig()
+	if err != nil {
+		return err
 	}
 
 	// 获取用户列表
@@ -513,26 +611,7 @@ func outputResourcesToolchain(client *nexus.Client, cfg *config.Config, template
 
 	// 获取仓库列表
 	if len(cfg.Repositories) > 0 {
-		for _, r := range cfg.Repositories {
-			repo, err := client.GetRepository(r.Name)
-			if err != nil {
-				continue
-			}
-			// 转换 map 到结构体
-			repoOut := RepositoryOutput{
-				Name:   getString(repo, "name"),
-				Format: getString(repo, "format"),
-				Type:   getString(repo, "type"),
-				Online: getBool(repo, "online"),
-				URL:    getString(repo, "url"),
-			}
-			if storage, ok := repo["storage"].(map[string]interface{}); ok {
-				repoOut.BlobStoreName = getString(storage, "blobStoreName")
-				repoOut.StrictContentTypeValidation = getBool(storage, "strictContentTypeValidation")
-				repoOut.WritePolicy = getString(storage, "writePolicy")
-			}
-			outputCfg.Repositories = append(outputCfg.Repositories, repoOut)
-		}
+		outputCfg.Repositories = repositoryOutputs(client, cfg)
 	}
 
 	// 获取角色列表
@@ -613,23 +692,3 @@ func (w *writer) Write(p []byte) (n int, err error) {
 	*w.buf = append(*w.buf, p...)
 	return len(p), nil
 }
-
-// getString 从 map 中获取字符串值
-func getString(m map[string]interface{}, key string) string {
-	if v, ok := m[key]; ok {
-		if s, ok := v.(string); ok {
-			return s
-		}
-	}
-	return ""
-}
-
-// getBool 从 map 中获取布尔值
-func getBool(m map[string]interface{}, key string) bool {
-	if v, ok := m[key]; ok {
-		if b, ok := v.(bool); ok {
-			return b
-		}
-	}
-	return false
-}
@@ -21,7 +21,7 @@ var (
 var deleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete resources defined in YAML configuration",
-	Long: `Delete removes users, repositories, roles, and privileges defined in the YAML configuration file.
+	Long: `Delete removes users, repositories, roles, privileges, and blob stores defined in the YAML configuration file.
 This is useful for cleaning up resources created by the apply command.`,
 	Example: `  # Delete resources from file
   nexus-cli delete -c config.yaml
@@ -108,6 +108,7 @@ func runDelete(_ *cobra.Command, _ []string) error {
 
 	// 创建删除服务并执行
 	svc := service.NewDeleteService(client, cfg, formatter)
+	svc.SetConfigName(config.NameFromPath(cfgFile))
 	result, err := svc.Delete()
 	if err != nil {
 		return fmt.Errorf("failed to delete resources: %w", err)
@@ -136,6 +137,9 @@ func showDeletePlan(cfg *config.Config, _ *output.Formatter) error {
 	if len(cfg.Users) > 0 {
 		fmt.Println("\nUsers:")
 		for _, user := range cfg.Users {
+			if user.IsExternal() {
+				continue
+			}
 			fmt.Printf("  - %s\n", user.ID)
 		}
 	}
@@ -161,5 +165,47 @@ func showDeletePlan(cfg *config.Config, _ *output.Formatter) error {
 		}
 	}
 
+	if len(cfg.Tasks) > 0 {
+		fmt.Println("\nTasks:")
+		for _, task := range cfg.Tasks {
+			fmt.Printf("  - %s (%s)\n", task.Name, task.Type)
+		}
+	}
+
+	if cfg.LDAP != nil && len(cfg.LDAP.Servers) > 0 {
+		fmt.Println("\nLDAP Servers:")
+		for _, server := range cfg.LDAP.Servers {
+			fmt.Printf("  - %s\n", server.Name)
+		}
+	}
+
+	if len(cfg.RoutingRules) > 0 {
+		fmt.Println("\nRouting Rules:")
+		for _, rule := range cfg.RoutingRules {
+			fmt.Printf("  - %s\n", rule.Name)
+		}
+	}
+
+	if len(cfg.ContentSelectors) > 0 {
+		fmt.Println("\nContent Selectors:")
+		for _, sel := range cfg.ContentSelectors {
+			fmt.Printf("  - %s\n", sel.Name)
+		}
+	}
+
+	if len(cfg.CleanupPolicies) > 0 {
+		fmt.Println("\nCleanup Policies:")
+		for _, policy := range cfg.CleanupPolicies {
+			fmt.Printf("  - %s\n", policy.Name)
+		}
+	}
+
+	if len(cfg.BlobStores) > 0 {
+		fmt.Println("\nBlob Stores (skipped while still in use):")
+		for _, store := range cfg.BlobStores {
+			fmt.Printf("  - %s (%s)\n", store.Name, store.Type)
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/alauda/nexus-cli/pkg/nexus"
+	"github.com/alauda/nexus-cli/pkg/output"
+)
+
+var describeOutput string
+
+var describeCmd = &cobra.Command{
+	Use:   "describe <type> <name>",
+	Short: "Show the details of a repository, user, role, privilege or blob store",
+	Long: `Describe prints all settings of a single resource.
+
+Resource types: repositories, users, roles, privileges, blobstores`,
+	Example: `  nexus-cli describe repository maven-releases
+  nexus-cli describe user dev-user -o yaml
+  nexus-cli describe blobstore default`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDescribe,
+}
+
+func init() {
+	rootCmd.AddCommand(describeCmd)
+	describeCmd.Flags().StringVarP(&describeOutput, "output", "o", "text", "Output format (text|json|yaml)")
+}
+
+func runDescribe(_ *cobra.Command, args []string) error {
+	kind, err := findResourceKind(args[0])
+	if err != nil {
+		return err
+	}
+	switch output.Format(describeOutput) {
+	case output.FormatText, output.FormatJSON, output.FormatYAML:
+	default:
+		return fmt.Errorf("invalid output format %q (must be text, json or yaml)", describeOutput)
+	}
+
+	client, err := connectNexus(output.NewFormatter(output.FormatText, os.Stderr))
+	if err != nil {
+		return err
+	}
+
+	get := kind.get
+	if kind.describe != nil {
+		get = kind.describe
+	}
+	data, err := get(client, args[1])
+	if err != nil {
+		return err
+	}
+
+	// 通过 JSON 字段名输出，与 get 的列名一致
+	rows, err := output.ToRows(data)
+	if err != nil {
+		return err
+	}
+	if describeOutput != string(output.FormatText) {
+		return output.NewFormatter(output.Format(describeOutput), os.Stdout).Output(rows[0])
+	}
+	writeDescription(os.Stdout, rows[0], "")
+	return nil
+}
+
+// describeBlobStore 返回 blob store 的用量和类型相关的配置
+func describeBlobStore(client *nexus.Client, name string) (interface{}, error) {
+	store, err := client.GetBlobStore(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var settings interface{}
+	switch strings.ToLower(store.Type) {
+	case "file":
+		settings, err = client.GetFileBlobStore(name)
+	case "s3":
+		settings, err = client.GetS3BlobStore(name)
+	default:
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		*nexus.BlobStoreResponse
+		Settings interface{} `json:"settings"`
+	}{store, settings}, nil
+}
+
+// writeDescription 以缩进的 key: value 格式输出资源，嵌套对象和列表逐层缩进
+func writeDescription(w io.Writer, obj map[string]interface{}, indent string) {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		switch value := obj[key].(type) {
+		case map[string]interface{}:
+			_, _ = fmt.Fprintf(w, "%s%s:\n", indent, key)
+			writeDescription(w, value, indent+"  ")
+		case []interface{}:
+			if len(value) == 0 {
+				_, _ = fmt.Fprintf(w, "%s%s: <none>\n", indent, key)
+				continue
+			}
+			_, _ = fmt.Fprintf(w, "%s%s:\n", indent, key)
+			for _, item := range value {
+				if m, ok := item.(map[string]interface{}); ok {
+					_, _ = fmt.Fprintf(w, "%s  -\n", indent)
+					writeDescription(w, m, indent+"    ")
+				} else {
+					_, _ = fmt.Fprintf(w, "%s  - %s\n", indent, output.FormatValue(item))
+				}
+			}
+		default:
+			_, _ = fmt.Fprintf(w, "%s%s: %s\n", indent, key, output.FormatValue(value))
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/alauda/nexus-cli/pkg/download"
+	"github.com/alauda/nexus-cli/pkg/nexus"
+	"github.com/alauda/nexus-cli/pkg/output"
+)
+
+var (
+	downloadRepository string
+	downloadPathPrefix string
+	downloadDest       string
+	downloadParallel   int
+	downloadManifest   string
+)
+
+// defaultManifestName 清单在目标目录中的默认文件名
+const defaultManifestName = "nexus-manifest.json"
+
+var downloadCmd = &cobra.Command{
+	Use:   "download",
+	Short: "Download the assets of a repository to a local directory",
+	Long: `Download mirrors the assets of a repository (optionally only those below --path-prefix)
+into --dest, keeping the repository layout. It is meant for moving repository content
+into air-gapped environments.
+
+Every file is checked against the sha256 (or sha1) checksum reported by Nexus. Files that
+already exist with the right checksum are skipped, and interrupted downloads continue
+from their .part file on the next run, so the command can simply be run again.
+
+A JSON manifest listing every asset with its size, checksums and status is written to
+the destination (nexus-manifest.json by default).`,
+	Example: `  # Mirror a hosted repository
+  nexus-cli download --repository maven-releases --dest ./mirror/maven-releases
+
+  # Only one group
+  nexus-cli download --repository maven-releases --path-prefix com/example/ --dest ./mirror
+
+  # Write the manifest next to the mirror
+  nexus-cli download -r raw-hosted --dest ./raw --manifest ./raw-manifest.json`,
+	Args: cobra.NoArgs,
+	RunE: runDownload,
+}
+
+func init() {
+	rootCmd.AddCommand(downloadCmd)
+	downloadCmd.Flags().StringVarP(&downloadRepository, "repository", "r", "", "Repository to download from (required)")
+	downloadCmd.Flags().StringVar(&downloadPathPrefix, "path-prefix", "", "Only download assets whose path starts with this prefix")
+	downloadCmd.Flags().StringVar(&downloadDest, "dest", "", "Destination directory (required)")
+	downloadCmd.Flags().IntVar(&downloadParallel, "parallel", 4, "Number of concurrent downloads")
+	downloadCmd.Flags().StringVar(&downloadManifest, "manifest", "", "Manifest file (default: <dest>/"+defaultManifestName+")")
+	_ = downloadCmd.MarkFlagRequired("repository")
+	_ = downloadCmd.MarkFlagRequired("dest")
+}
+
+func runDownload(_ *cobra.Command, _ []string) error {
+	if downloadParallel < 1 {
+		return fmt.Errorf("--parallel must be at least 1")
+	}
+	manifestFile := downloadManifest
+	if manifestFile == "" {
+		manifestFile = filepath.Join(downloadDest, defaultManifestName)
+	}
+
+	formatter := output.NewFormatter(output.FormatText, os.Stderr)
+	client, err := connectNexus(formatter)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	prefix := strings.TrimPrefix(downloadPathPrefix, "/")
+	var assets []nexus.Asset
+	it := client.Assets(ctx, downloadRepository)
+	for it.Next() {
+		asset := it.Item()
+		if strings.HasPrefix(strings.TrimPrefix(asset.Path, "/"), prefix) {
+			assets = append(assets, asset)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return fmt.Errorf("failed to list assets of %s: %w", downloadRepository, err)
+	}
+	if len(assets) == 0 {
+		formatter.Warning("No assets found")
+		return nil
+	}
+	if err := os.MkdirAll(downloadDest, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", downloadDest, err)
+	}
+
+	formatter.Info(fmt.Sprintf("Downloading %d assets from %s to %s...", len(assets), downloadRepository, downloadDest))
+	open := func(ctx context.Context, assetPath string, offset int64) (io.ReadCloser, bool, error) {
+		return client.OpenAsset(ctx, downloadRepository, assetPath, offset)
+	}
+	entries := download.Run(ctx, assets, downloadParallel, func(ctx context.Context, asset nexus.Asset) (download.Entry, error) {
+		return download.Fetch(ctx, open, asset, downloadDest)
+	})
+
+	counts := make(map[string]int)
+	var unverified int
+	for _, entry := range entries {
+		counts[entry.Status]++
+		if entry.Status == download.StatusFailed {
+			formatter.Error(fmt.Sprintf("%s: %s", entry.Path, entry.Error))
+		} else if !entry.Verified {
+			unverified++
+		}
+	}
+
+	manifest := download.Manifest{
+		Repository: downloadRepository,
+		PathPrefix: downloadPathPrefix,
+		Created:    time.Now().UTC(),
+		Assets:     entries,
+	}
+	if err := download.WriteManifest(manifestFile, manifest); err != nil {
+		return err
+	}
+
+	formatter.Success(fmt.Sprintf("Downloaded %d, resumed %d, unchanged %d, failed %d (manifest: %s)",
+		counts[download.StatusDownloaded], counts[download.StatusResumed], counts[download.StatusUnchanged],
+		counts[download.StatusFailed], manifestFile))
+	if unverified > 0 {
+		formatter.Warning(fmt.Sprintf("%d assets have no sha1 or sha256 checksum on the server and were not verified", unverified))
+	}
+	if counts[download.StatusFailed] > 0 {
+		return fmt.Errorf("%d of %d downloads failed, run the command again to retry", counts[download.StatusFailed], len(entries))
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/alauda/nexus-cli/pkg/nexus"
+	"github.com/alauda/nexus-cli/pkg/output"
+)
+
+var (
+	getOutput   string
+	getTemplate string
+	getSelector string
+	getFormat   string
+	getType     string
+	getSortBy   string
+	getColumns  string
+)
+
+// resourceKind 可以通过 get 和 describe 查看的资源类型
+type resourceKind struct {
+	// name 资源类型的复数名称，aliases 为可接受的其他写法
+	name    string
+	aliases []string
+	// columns table 和 text 格式的默认列
+	columns []string
+	// filters 支持 --format 和 --type 过滤的字段
+	filters []string
+	list    func(client *nexus.Client) (interface{}, error)
+	get     func(client *nexus.Client, name string) (interface{}, error)
+	// describe 返回 describe 输出的详细信息，未设置时使用 get
+	describe func(client *nexus.Client, name string) (interface{}, error)
+}
+
+// resourceKinds 支持的资源类型
+var resourceKinds = []*resourceKind{
+	{
+		name:    "repositories",
+		aliases: []string{"repository", "repo", "repos"},
+		columns: []string{"name", "format", "type", "url"},
+		filters: []string{"format", "type"},
+		list: func(c *nexus.Client) (interface{}, error) {
+			return c.ListRepositories()
+		},
+		get: func(c *nexus.Client, name string) (interface{}, error) {
+			return c.GetRepository(name)
+		},
+	},
+	{
+		name:    "users",
+		aliases: []string{"user"},
+		columns: []string{"userId", "firstName", "lastName", "emailAddress", "source", "status", "roles"},
+		list: func(c *nexus.Client) (interface{}, error) {
+			return c.ListUsers(nexus.UserFilter{})
+		},
+		get: func(c *nexus.Client, name string) (interface{}, error) {
+			return c.GetUser(name)
+		},
+	},
+	{
+		name:    "roles",
+		aliases: []string{"role"},
+		columns: []string{"id", "name", "source", "readOnly", "roles"},
+		list: func(c *nexus.Client) (interface{}, error) {
+			return c.ListRoles()
+		},
+		get: func(c *nexus.Client, name string) (interface{}, error) {
+			return c.GetRole(name)
+		},
+	},
+	{
+		name:    "privileges",
+		aliases: []string{"privilege", "priv", "privs"},
+		columns: []string{"name", "type", "format", "repository", "actions", "readOnly"},
+		filters: []string{"format", "type"},
+		list: func(c *nexus.Client) (interface{}, error) {
+			return c.ListPrivileges()
+		},
+		get: func(c *nexus.Client, name string) (interface{}, error) {
+			return c.GetPrivilege(name)
+		},
+	},
+	{
+		name:    "blobstores",
+		aliases: []string{"blobstore", "bs"},
+		columns: []string{"name", "type", "blobCount", "totalSizeInBytes", "availableSpaceInBytes", "unavailable"},
+		filters: []string{"type"},
+		list: func(c *nexus.Client) (interface{}, error) {
+			return c.ListBlobStores()
+		},
+		get: func(c *nexus.Client, name string) (interface{}, error) {
+			return c.GetBlobStore(name)
+		},
+		describe: describeBlobStore,
+	},
+}
+
+// findResourceKind 按名称或别名查找资源类型
+func findResourceKind(name string) (*resourceKind, error) {
+	name = strings.ToLower(name)
+	for _, kind := range resourceKinds {
+		if kind.name == name || containsString(kind.aliases, name) {
+			return kind, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown resource type %q (must be one of %s)", name, strings.Join(resourceKindNames(), ", "))
+}
+
+// resourceKindNames 返回所有资源类型的名称
+func resourceKindNames() []string {
+	names := make([]string, len(resourceKinds))
+	for i, kind := range resourceKinds {
+		names[i] = kind.name
+	}
+	return names
+}
+
+var getCmd = &cobra.Command{
+	Use:   "get <type> [name]",
+	Short: "Display repositories, users, roles, privileges or blob stores",
+	Long: `Get lists the resources of a type on the Nexus server, or a single resource by name.
+
+Resource types: repositories, users, roles, privileges, blobstores
+
+Columns, selectors and sorting use the field names of the Nexus REST API.
+Nested fields are addressed with dots, e.g. storage.blobStoreName.`,
+	Example: `  # List all maven2 hosted repositories
+  nexus-cli get repositories --format maven2 --type hosted
+
+  # Select repositories by any field and choose the columns
+  nexus-cli get repos -l format=docker --columns name,url
+
+  # Users that have a role, sorted by e-mail address
+  nexus-cli get users -l roles=nx-admin --sort-by emailAddress
+
+  # Largest blob stores first
+  nexus-cli get blobstores --sort-by -totalSizeInBytes
+
+  # Print repository names with a template
+  nexus-cli get repositories -o template --template '{{range .}}{{.name}}{{"\n"}}{{end}}'`,
+	Args:      cobra.RangeArgs(1, 2),
+	ValidArgs: resourceKindNames(),
+	RunE:      runGet,
+}
+
+func init() {
+	rootCmd.AddCommand(getCmd)
+	getCmd.Flags().StringVarP(&getOutput, "output", "o", "table", "Output format (text|json|yaml|table|template)")
+	getCmd.Flags().StringVar(&getTemplate, "template", "", "Go template for -o template (the data is a list of resources)")
+	getCmd.Flags().StringVarP(&getSelector, "selector", "l", "", "Filter by fields, e.g. format=maven2,type!=group")
+	getCmd.Flags().StringVar(&getFormat, "format", "", "Only show resources of this format (repositories, privileges)")
+	getCmd.Flags().StringVar(&getType, "type", "", "Only show resources of this type (repositories, privileges, blobstores)")
+	getCmd.Flags().StringVar(&getSortBy, "sort-by", "", "Sort by field, prefix with - for descending order")
+	getCmd.Flags().StringVar(&getColumns, "columns", "", "Comma separated columns for table and text output")
+}
+
+func runGet(_ *cobra.Command, args []string) error {
+	kind, err := findResourceKind(args[0])
+	if err != nil {
+		return err
+	}
+
+	selector, err := getFieldSelector(kind)
+	if err != nil {
+		return err
+	}
+
+	formatter, err := newResourceFormatter(getOutput, getTemplate)
+	if err != nil {
+		return err
+	}
+	formatter.SetSortBy(getSortBy)
+	formatter.SetColumns(kind.columns)
+	if getColumns != "" {
+		formatter.SetColumns(strings.Split(getColumns, ","))
+	}
+
+	client, err := connectNexus(output.NewFormatter(output.FormatText, os.Stderr))
+	if err != nil {
+		return err
+	}
+
+	var data interface{}
+	if len(args) == 2 {
+		data, err = kind.get(client, args[1])
+	} else {
+		data, err = kind.list(client)
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, err := output.ToRows(data)
+	if err != nil {
+		return err
+	}
+	rows, err = rows.Filter(selector)
+	if err != nil {
+		return err
+	}
+	return formatter.Output(rows)
+}
+
+// getFieldSelector 将 --format 和 --type 合并到 --selector
+func getFieldSelector(kind *resourceKind) (string, error) {
+	conditions := []string{}
+	if getSelector != "" {
+		conditions = append(conditions, getSelector)
+	}
+	for field, value := range map[string]string{"format": getFormat, "type": getType} {
+		if value == "" {
+			continue
+		}
+		if !containsString(kind.filters, field) {
+			return "", fmt.Errorf("--%s is not supported for %s", field, kind.name)
+		}
+		conditions = append(conditions, field+"="+value)
+	}
+	return strings.Join(conditions, ","), nil
+}
+
+// newResourceFormatter 创建资源输出的格式化器
+func newResourceFormatter(format, tmpl string) (*output.Formatter, error) {
+	switch output.Format(format) {
+	case output.FormatText, output.FormatJSON, output.FormatYAML, output.FormatTable:
+	case output.FormatTemplate:
+		if tmpl == "" {
+			return nil, fmt.Errorf("--template is required with -o template")
+		}
+	default:
+		return nil, fmt.Errorf("invalid output format %q (must be text, json, yaml, table or template)", format)
+	}
+	formatter := output.NewFormatter(output.Format(format), os.Stdout)
+	formatter.SetTemplate(tmpl)
+	return formatter, nil
+}
+
+// containsString 检查字符串是否在列表中
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
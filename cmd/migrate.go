@@ -0,0 +1,291 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+
+	"github.com/alauda/nexus-cli/pkg/config"
+	"github.com/alauda/nexus-cli/pkg/migrate"
+	"github.com/alauda/nexus-cli/pkg/nexus"
+	"github.com/alauda/nexus-cli/pkg/output"
+	"github.com/alauda/nexus-cli/pkg/service"
+)
+
+var (
+	migrateFrom         string
+	migrateTo           string
+	migrateRepositories []string
+	migratePhases       []string
+	migrateDryRun       bool
+	migrateParallel     int
+	migrateOutput       string
+)
+
+// 迁移阶段
+const (
+	migratePhaseRepositories = "repositories"
+	migratePhaseSecurity     = "security"
+	migratePhaseContent      = "content"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Copy repositories, security objects and content between Nexus instances",
+	Long: `Migrate copies from a source to a target Nexus in three phases:
+
+  repositories  repository definitions missing on the target are exported from the source
+                and created the same way as "create" does
+  security      content selectors, privileges and roles missing on the target are created
+                (built-in read-only objects and users are not copied); with --repository
+                only privileges of the given repositories, renamed for source:target, and
+                the selectors and roles that use them are copied
+  content       components of hosted repositories are streamed from the source and uploaded
+                to the target repository
+
+Connections are read from environment variables: --from NEXUS_SOURCE uses NEXUS_SOURCE_URL,
+NEXUS_SOURCE_USERNAME and NEXUS_SOURCE_PASSWORD, --to defaults to the usual NEXUS_* variables.
+Use the same profile for both to copy between repositories of one instance.
+
+Existing objects on the target are never modified. Assets are compared by path and checksum,
+so running the command again only copies what is new or changed. --dry-run reports what
+would be created and how many components, assets and bytes would be copied.`,
+	Example: `  export NEXUS_SOURCE_URL=http://old-nexus:8081 NEXUS_SOURCE_USERNAME=admin NEXUS_SOURCE_PASSWORD=...
+  export NEXUS_URL=http://new-nexus:8081 NEXUS_USERNAME=admin NEXUS_PASSWORD=...
+
+  # What would be copied?
+  nexus-cli migrate --from NEXUS_SOURCE --dry-run
+
+  # Migrate everything
+  nexus-cli migrate --from NEXUS_SOURCE
+
+  # Only the content of two repositories, one of them renamed
+  nexus-cli migrate --from NEXUS_SOURCE --phases content -r maven-releases -r npm-hosted:npm-internal
+
+  # Copy between repositories of the same instance
+  nexus-cli migrate --from NEXUS --repository raw-old:raw-new`,
+	Args: cobra.NoArgs,
+	RunE: runMigrate,
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.Flags().StringVar(&migrateFrom, "from", "NEXUS_SOURCE", "Environment variable prefix of the source connection")
+	migrateCmd.Flags().StringVar(&migrateTo, "to", config.DefaultProfile, "Environment variable prefix of the target connection")
+	migrateCmd.Flags().StringSliceVarP(&migrateRepositories, "repository", "r", nil, "Repositories to migrate as source or source:target (default: all)")
+	migrateCmd.Flags().StringSliceVar(&migratePhases, "phases",
+		[]string{migratePhaseRepositories, migratePhaseSecurity, migratePhaseContent}, "Phases to run")
+	migrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Report what would be copied without changing the target")
+	migrateCmd.Flags().IntVar(&migrateParallel, "parallel", 4, "Number of concurrent uploads")
+	migrateCmd.Flags().StringVarP(&migrateOutput, "output", "o", "table", "Output format of the content report (text|json|yaml|table)")
+}
+
+func runMigrate(_ *cobra.Command, _ []string) error {
+	phases := make(map[string]bool)
+	for _, phase := range migratePhases {
+		switch phase {
+		case migratePhaseRepositories, migratePhaseSecurity, migratePhaseContent:
+			phases[phase] = true
+		default:
+			return fmt.Errorf("invalid phase %q (must be repositories, security or content)", phase)
+		}
+	}
+	if migrateParallel < 1 {
+		return fmt.Errorf("--parallel must be at least 1")
+	}
+	mappings, err := migrate.ParseMappings(migrateRepositories)
+	if err != nil {
+		return err
+	}
+	if migrateFrom == migrateTo {
+		if len(mappings) == 0 {
+			return fmt.Errorf("source and target are the same instance, use --repository source:target")
+		}
+		for _, m := range mappings {
+			if m.Source == m.Target {
+				return fmt.Errorf("repository %s would be copied onto itself", m.Source)
+			}
+		}
+	}
+
+	formatter := output.NewFormatter(output.FormatText, os.Stderr)
+	source, err := connectNexusProfile(formatter, migrateFrom)
+	if err != nil {
+		return err
+	}
+	target, err := connectNexusProfile(formatter, migrateTo)
+	if err != nil {
+		return err
+	}
+	if len(mappings) == 0 {
+		if mappings, err = migrate.AllRepositories(source); err != nil {
+			return err
+		}
+	}
+
+	if phases[migratePhaseRepositories] || phases[migratePhaseSecurity] {
+		if err := migrateDefinitions(formatter, source, target, mappings, phases); err != nil {
+			return err
+		}
+	}
+	if phases[migratePhaseContent] {
+		return migrateContent(formatter, source, target, mappings)
+	}
+	return nil
+}
+
+// migrateDefinitions 在目标实例上创建缺少的仓库和安全对象
+func migrateDefinitions(formatter *output.Formatter, source, target *nexus.Client, mappings []migrate.Mapping, phases map[string]bool) error {
+	cfg := &config.Config{}
+	if phases[migratePhaseRepositories] {
+		repos, warnings, err := migrate.Definitions(source, target, mappings)
+		if err != nil {
+			return err
+		}
+		for _, warning := range warnings {
+			formatter.Warning(warning)
+		}
+		cfg.Repositories = repos
+	}
+	if phases[migratePhaseSecurity] {
+		// 指定 -r 时只复制与这些仓库相关的安全对象
+		var scope []migrate.Mapping
+		if len(migrateRepositories) > 0 {
+			scope = mappings
+		}
+		security, warnings, err := migrate.Security(source, target, scope)
+		if err != nil {
+			return err
+		}
+		for _, warning := range warnings {
+			formatter.Warning(warning)
+		}
+		cfg.ContentSelectors = security.ContentSelectors
+		cfg.Privileges = security.Privileges
+		cfg.Roles = security.Roles
+	}
+
+	if len(cfg.Repositories)+len(cfg.ContentSelectors)+len(cfg.Privileges)+len(cfg.Roles) == 0 {
+		formatter.Info("Target already has all repository and security definitions")
+		return nil
+	}
+	if migrateDryRun {
+		for _, repo := range cfg.Repositories {
+			formatter.Info(fmt.Sprintf("Would create repository %s (%s %s)", repo.Name, repo.Format, repo.Type))
+		}
+		for _, selector := range cfg.ContentSelectors {
+			formatter.Info(fmt.Sprintf("Would create content selector %s", selector.Name))
+		}
+		for _, priv := range cfg.Privileges {
+			formatter.Info(fmt.Sprintf("Would create privilege %s", priv.Name))
+		}
+		for _, role := range cfg.Roles {
+			formatter.Info(fmt.Sprintf("Would create role %s", role.ID))
+		}
+		return nil
+	}
+
+	// 只创建目标实例上缺少的对象，不能清理目标实例上由其他配置管理的权限角色
+	svc := service.NewApplyService(target, cfg, formatter)
+	svc.SetSyncPermissionRoles(false)
+	if _, err := svc.Apply(); err != nil {
+		return fmt.Errorf("failed to create definitions on the target: %w", err)
+	}
+	return nil
+}
+
+// migrateContent 比较并复制 hosted 仓库的内容，dry-run 时只输出报告
+func migrateContent(formatter *output.Formatter, source, target *nexus.Client, mappings []migrate.Mapping) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var reports []*migrate.Report
+	var components, assets int
+	var bytes int64
+	for _, m := range mappings {
+		formatter.Info(fmt.Sprintf("Comparing %s with %s...", m.Source, m.Target))
+		report, err := migrate.Compare(ctx, source, target, m)
+		if err != nil {
+			return err
+		}
+		reports = append(reports, report)
+		components += report.Components
+		assets += report.Assets
+		bytes += report.Bytes
+	}
+
+	if migrateDryRun {
+		reportFormatter, err := newResourceFormatter(migrateOutput, "")
+		if err != nil {
+			return err
+		}
+		reportFormatter.SetColumns([]string{"source", "target", "format", "components", "assets", "bytes", "upToDate", "unsupported"})
+		rows, err := output.ToRows(reports)
+		if err != nil {
+			return err
+		}
+		if err := reportFormatter.Output(rows); err != nil {
+			return err
+		}
+		formatter.Info(fmt.Sprintf("Would copy %d components (%d assets, %s)", components, assets, formatByteSize(bytes)))
+		return nil
+	}
+
+	var failed, total int
+	for _, report := range reports {
+		if report.Unsupported != "" {
+			formatter.Info(fmt.Sprintf("Skipping %s: %s", report.Source, report.Unsupported))
+			continue
+		}
+		if len(report.Transfers) == 0 {
+			formatter.Info(fmt.Sprintf("%s is up to date (%d assets)", report.Target, report.UpToDate))
+			continue
+		}
+		exists, err := target.RepositoryExists(report.Target)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			formatter.Warning(fmt.Sprintf("Skipping %s: repository %s does not exist on the target", report.Source, report.Target))
+			continue
+		}
+
+		formatter.Info(fmt.Sprintf("Copying %d components (%d assets, %s) from %s to %s...",
+			report.Components, report.Assets, formatByteSize(report.Bytes), report.Source, report.Target))
+		results, err := migrate.Copy(ctx, source, target, report, migrateParallel)
+		if err != nil {
+			return err
+		}
+		var repoFailed int
+		for _, result := range results {
+			if result.Err != nil {
+				repoFailed++
+				formatter.Error(fmt.Sprintf("%s/%s: %v", report.Target, result.Item.Label, result.Err))
+			}
+		}
+		formatter.Success(fmt.Sprintf("Copied %d of %d uploads to %s", len(results)-repoFailed, len(results), report.Target))
+		failed += repoFailed
+		total += len(results)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d uploads failed, run the command again to retry", failed, total)
+	}
+	return nil
+}
+
+// formatByteSize 以 KiB、MiB 等单位显示字节数
+func formatByteSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/alauda/nexus-cli/pkg/nexus"
+	"github.com/alauda/nexus-cli/pkg/output"
+)
+
+var repoDryRun bool
+
+var repoCmd = &cobra.Command{
+	Use:   "repo",
+	Short: "Repository maintenance operations",
+	Long: `Run maintenance operations on existing repositories.
+
+Every command accepts repository names and glob patterns (e.g. "npm-*"); patterns are matched
+against all repositories on the server. Use --dry-run to list the matching repositories first.`,
+}
+
+var repoInvalidateCacheCmd = &cobra.Command{
+	Use:   "invalidate-cache <name>...",
+	Short: "Invalidate the cache of proxy and group repositories",
+	Long: `Invalidate-cache marks the cached content and metadata of proxy repositories (and the
+merged metadata of group repositories) as stale, so the next request fetches it again from
+the upstream. Hosted repositories matched by a pattern are skipped.`,
+	Example: `  # An upstream republished packages
+  nexus-cli repo invalidate-cache npmjs-proxy
+
+  # All npm proxies and groups
+  nexus-cli repo invalidate-cache 'npm-*'`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runRepoMaintenance(args, []string{"proxy", "group"}, "Invalidated cache", func(client *nexus.Client, name string) error {
+			return client.InvalidateRepositoryCache(name)
+		})
+	},
+}
+
+var repoRebuildIndexCmd = &cobra.Command{
+	Use:     "rebuild-index <name>...",
+	Short:   "Rebuild the search index of repositories",
+	Example: `  nexus-cli repo rebuild-index maven-releases maven-snapshots`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runRepoMaintenance(args, nil, "Started index rebuild", func(client *nexus.Client, name string) error {
+			return client.RebuildRepositoryIndex(name)
+		})
+	},
+}
+
+var repoOfflineCmd = &cobra.Command{
+	Use:   "offline <name>...",
+	Short: "Take repositories offline",
+	Long: `Offline stops a repository from serving requests without deleting it.
+Proxy repositories with upstream authentication cannot be changed here because Nexus does
+not return the password; set "online: false" in the config file and run "create" instead.`,
+	Example: `  nexus-cli repo offline 'team1-*'`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runRepoMaintenance(args, nil, "Took offline", func(client *nexus.Client, name string) error {
+			return setRepositoryOnline(client, name, false)
+		})
+	},
+}
+
+var repoOnlineCmd = &cobra.Command{
+	Use:     "online <name>...",
+	Short:   "Bring repositories online",
+	Example: `  nexus-cli repo online 'team1-*'`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runRepoMaintenance(args, nil, "Brought online", func(client *nexus.Client, name string) error {
+			return setRepositoryOnline(client, name, true)
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(repoCmd)
+	repoCmd.AddCommand(repoInvalidateCacheCmd, repoRebuildIndexCmd, repoOfflineCmd, repoOnlineCmd)
+	repoCmd.PersistentFlags().BoolVar(&repoDryRun, "dry-run", false, "Only list the matching repositories")
+}
+
+// errRepositoryUnchanged 仓库已处于目标状态
+var errRepositoryUnchanged = errors.New("already in the requested state")
+
+// setRepositoryOnline 设置仓库的在线状态，已处于该状态时返回 errRepositoryUnchanged
+func setRepositoryOnline(client *nexus.Client, name string, online bool) error {
+	changed, err := client.SetRepositoryOnline(name, online, "")
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return errRepositoryUnchanged
+	}
+	return nil
+}
+
+// runRepoMaintenance 对匹配的仓库逐个执行操作，types 不为空时只处理这些类型的仓库
+func runRepoMaintenance(patterns, types []string, done string, action func(*nexus.Client, string) error) error {
+	status := output.NewFormatter(output.FormatText, os.Stderr)
+	client, err := connectNexus(status)
+	if err != nil {
+		return err
+	}
+	repos, err := client.ListRepositories()
+	if err != nil {
+		return err
+	}
+	matched, err := matchRepositoryPatterns(repos, patterns, types)
+	if err != nil {
+		return err
+	}
+	for _, repo := range matched.skipped {
+		status.Info(fmt.Sprintf("Skipping %s (%s %s)", repo.Name, repo.Format, repo.Type))
+	}
+	if len(matched.repos) == 0 {
+		return fmt.Errorf("none of the matching repositories is a %s repository", strings.Join(types, " or "))
+	}
+
+	if repoDryRun {
+		for _, repo := range matched.repos {
+			fmt.Printf("%s (%s/%s)\n", repo.Name, repo.Format, repo.Type)
+		}
+		return nil
+	}
+
+	failed := 0
+	for _, repo := range matched.repos {
+		err := action(client, repo.Name)
+		switch {
+		case errors.Is(err, errRepositoryUnchanged):
+			status.Info(fmt.Sprintf("%s: %s", repo.Name, err))
+		case err != nil:
+			status.Error(err.Error())
+			failed++
+		default:
+			status.Success(fmt.Sprintf("%s: %s", done, repo.Name))
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d repositories failed", failed, len(matched.repos))
+	}
+	return nil
+}
+
+// repositoryMatch 仓库名称和模式的匹配结果
+type repositoryMatch struct {
+	repos []nexus.RepositorySummary
+	// skipped 被模式匹配但类型不适用的仓库
+	skipped []nexus.RepositorySummary
+}
+
+// matchRepositoryPatterns 按名称或通配符模式选择仓库，结果按名称排序
+//
+// 不含通配符的名称必须存在，且类型必须适用；模式至少要匹配一个仓库，匹配到的不适用类型的仓库会被跳过。
+func matchRepositoryPatterns(repos []nexus.RepositorySummary, patterns, types []string) (*repositoryMatch, error) {
+	selected := make(map[string]bool)
+	result := &repositoryMatch{}
+	for _, pattern := range patterns {
+		wildcard := strings.ContainsAny(pattern, "*?[")
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid repository pattern %q: %w", pattern, err)
+		}
+
+		found := false
+		for _, repo := range repos {
+			if ok, _ := path.Match(pattern, repo.Name); !ok {
+				continue
+			}
+			found = true
+			if selected[repo.Name] {
+				continue
+			}
+			selected[repo.Name] = true
+			if len(types) > 0 && !containsString(types, repo.Type) {
+				if !wildcard {
+					return nil, fmt.Errorf("repository %s is a %s repository (expected %s)", repo.Name, repo.Type, strings.Join(types, " or "))
+				}
+				result.skipped = append(result.skipped, repo)
+				continue
+			}
+			result.repos = append(result.repos, repo)
+		}
+		if !found {
+			if wildcard {
+				return nil, fmt.Errorf("no repository matches %q", pattern)
+			}
+			return nil, fmt.Errorf("repository %s not found", pattern)
+		}
+	}
+	sort.Slice(result.repos, func(i, j int) bool { return result.repos[i].Name < result.repos[j].Name })
+	return result, nil
+}
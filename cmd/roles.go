@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/alauda/nexus-cli/pkg/config"
+	"github.com/alauda/nexus-cli/pkg/nexus"
+	"github.com/alauda/nexus-cli/pkg/output"
+	"github.com/alauda/nexus-cli/pkg/rolegraph"
+	"github.com/alauda/nexus-cli/pkg/service"
+)
+
+var (
+	rolesGraphOutput  string
+	rolesGraphOffline bool
+)
+
+var rolesCmd = &cobra.Command{
+	Use:   "roles",
+	Short: "Inspect Nexus roles",
+}
+
+var rolesGraphCmd = &cobra.Command{
+	Use:   "graph [role-id...]",
+	Short: "Show the role hierarchy and check it for cycles and missing roles",
+	Long: `Graph merges the roles on the Nexus server with the roles declared in the config
+file (-c) and renders which roles contain which. Declared roles replace the
+nested roles of the server role with the same ID, so the graph shows the
+hierarchy as it will be after apply, including the repository permission roles
+generated for roles and LDAP groups in userRepositoryPermissions.
+
+Cycles and references to roles that exist neither in the config nor on the
+server are reported on stderr and make the command fail. With --offline only
+the config file is used, so references to roles that are not declared cannot
+be verified and are only reported.
+
+Output formats:
+  tree     indented text (default)
+  dot      Graphviz DOT, e.g. nexus-cli roles graph -o dot | dot -Tsvg > roles.svg
+  mermaid  Mermaid flowchart for Markdown documents
+  json, yaml`,
+	Example: `  # Show the hierarchy below team1-developers
+  nexus-cli roles graph team1-developers
+
+  # Check a config file before applying it
+  nexus-cli roles graph -c config.yaml
+
+  # Render the declared roles without connecting to Nexus
+  nexus-cli roles graph -c config.yaml --offline -o mermaid`,
+	RunE: runRolesGraph,
+}
+
+func init() {
+	rootCmd.AddCommand(rolesCmd)
+	rolesCmd.AddCommand(rolesGraphCmd)
+	rolesGraphCmd.Flags().StringVarP(&rolesGraphOutput, "output", "o", "tree", "Output format (tree|dot|mermaid|json|yaml)")
+	rolesGraphCmd.Flags().BoolVar(&rolesGraphOffline, "offline", false, "Only use roles from the config file")
+}
+
+// roleGraphReport 角色层级的 json/yaml 输出
+type roleGraphReport struct {
+	Roles   []*rolegraph.Node     `json:"roles" yaml:"roles"`
+	Cycles  [][]string            `json:"cycles,omitempty" yaml:"cycles,omitempty"`
+	Orphans []rolegraph.Reference `json:"orphans,omitempty" yaml:"orphans,omitempty"`
+}
+
+func runRolesGraph(_ *cobra.Command, args []string) error {
+	status := output.NewFormatter(output.FormatText, os.Stderr)
+
+	if rolesGraphOffline && cfgFile == "" {
+		return fmt.Errorf("--offline requires a config file (-c)")
+	}
+
+	graph := rolegraph.New()
+	var client *nexus.Client
+	if !rolesGraphOffline {
+		var err error
+		client, err = connectNexus(status)
+		if err != nil {
+			return err
+		}
+		roles, err := client.ListRoles()
+		if err != nil {
+			return err
+		}
+		for _, role := range roles {
+			graph.AddServerRole(role.ID, role.Roles)
+		}
+	}
+	if cfgFile != "" {
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		// 与 apply 一致，角色和 LDAP 组的子角色包括生成的仓库权限角色
+		svc := service.NewApplyService(client, cfg, status)
+		svc.SetConfigName(config.NameFromPath(cfgFile))
+		members, err := svc.DesiredRoleMembers(rolesGraphOffline)
+		if err != nil {
+			return err
+		}
+		ids := make([]string, 0, len(members))
+		for id := range members {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			graph.AddConfigRole(id, members[id])
+		}
+	}
+
+	if len(args) > 0 {
+		sub, err := graph.Subgraph(args)
+		if err != nil {
+			return err
+		}
+		graph = sub
+	}
+
+	cycles := graph.Cycles()
+	orphans := graph.Orphans()
+
+	var err error
+	switch rolesGraphOutput {
+	case "tree":
+		err = rolegraph.WriteTree(os.Stdout, graph)
+	case "dot":
+		err = rolegraph.WriteDOT(os.Stdout, graph)
+	case "mermaid":
+		err = rolegraph.WriteMermaid(os.Stdout, graph)
+	case string(output.FormatJSON), string(output.FormatYAML):
+		report := roleGraphReport{Cycles: cycles, Orphans: orphans}
+		for _, id := range graph.IDs() {
+			node, _ := graph.Node(id)
+			report.Roles = append(report.Roles, node)
+		}
+		err = output.NewFormatter(output.Format(rolesGraphOutput), os.Stdout).Output(report)
+	default:
+		return fmt.Errorf("invalid output format %q (must be tree, dot, mermaid, json or yaml)", rolesGraphOutput)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, cycle := range cycles {
+		status.Error(fmt.Sprintf("Role cycle: %s", rolegraph.FormatCycle(cycle)))
+	}
+	for _, ref := range orphans {
+		if rolesGraphOffline {
+			status.Info(fmt.Sprintf("Role %s contains %s which is not declared in the config and must exist on the server", ref.Role, ref.Missing))
+		} else {
+			status.Error(fmt.Sprintf("Role %s contains %s which does not exist", ref.Role, ref.Missing))
+		}
+	}
+
+	if len(cycles) > 0 || (len(orphans) > 0 && !rolesGraphOffline) {
+		return fmt.Errorf("role hierarchy has %d cycle(s) and %d missing role reference(s)", len(cycles), len(orphans))
+	}
+	return nil
+}
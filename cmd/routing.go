@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/alauda/nexus-cli/pkg/config"
+	"github.com/alauda/nexus-cli/pkg/output"
+	"github.com/alauda/nexus-cli/pkg/routing"
+)
+
+var routingOutput string
+
+var routingCmd = &cobra.Command{
+	Use:   "routing",
+	Short: "Inspect routing rules",
+}
+
+var routingTestCmd = &cobra.Command{
+	Use:   "test <rule> <path>...",
+	Short: "Check whether request paths would be blocked by a routing rule",
+	Long: `Test evaluates the matchers of a routing rule locally and reports whether each
+path would be allowed or blocked. The rule is taken from the config file when
+given, otherwise it is read from the Nexus server.`,
+	Example: `  # Test a rule from the config file (no Nexus connection needed)
+  nexus-cli routing test block-internal /@example/internal-lib/-/internal-lib-1.0.0.tgz -c config.yaml
+
+  # Test a rule defined on the server
+  nexus-cli routing test block-internal /com/example/lib/1.0/lib-1.0.jar`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runRoutingTest,
+}
+
+func init() {
+	rootCmd.AddCommand(routingCmd)
+	routingCmd.AddCommand(routingTestCmd)
+	routingTestCmd.Flags().StringVarP(&routingOutput, "output", "o", "text", "Output format (text|json|yaml)")
+}
+
+func runRoutingTest(_ *cobra.Command, args []string) error {
+	ruleName, paths := args[0], args[1:]
+
+	mode, matchers, err := findRoutingRule(ruleName)
+	if err != nil {
+		return err
+	}
+
+	var results []*routing.Result
+	for _, path := range paths {
+		result, err := routing.Evaluate(mode, matchers, path)
+		if err != nil {
+			return fmt.Errorf("failed to evaluate routing rule %s: %w", ruleName, err)
+		}
+		results = append(results, result)
+	}
+
+	formatter := output.NewFormatter(output.Format(routingOutput), os.Stdout)
+	if routingOutput != string(output.FormatText) {
+		return formatter.Output(results)
+	}
+
+	formatter.Print(fmt.Sprintf("Routing rule %s (%s):", ruleName, mode))
+	for _, r := range results {
+		verdict := "ALLOWED"
+		if !r.Allowed {
+			verdict = "BLOCKED"
+		}
+		if r.Matcher != "" {
+			formatter.Print(fmt.Sprintf("  %s  %s (matched %q)", verdict, r.Path, r.Matcher))
+		} else {
+			formatter.Print(fmt.Sprintf("  %s  %s (no matcher matched)", verdict, r.Path))
+		}
+	}
+	return nil
+}
+
+// findRoutingRule 优先从配置文件查找路由规则，否则从服务器获取
+func findRoutingRule(name string) (string, []string, error) {
+	if cfgFile != "" {
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to load config: %w", err)
+		}
+		for _, rule := range cfg.RoutingRules {
+			if rule.Name == name {
+				return rule.Mode, rule.Matchers, nil
+			}
+		}
+	}
+
+	client, err := connectNexus(output.NewFormatter(output.FormatText, os.Stderr))
+	if err != nil {
+		return "", nil, err
+	}
+	rule, err := client.GetRoutingRule(name)
+	if err != nil {
+		return "", nil, err
+	}
+	return rule.Mode, rule.Matchers, nil
+}
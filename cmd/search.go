@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/alauda/nexus-cli/pkg/nexus"
+	"github.com/alauda/nexus-cli/pkg/output"
+)
+
+var (
+	searchAssets    bool
+	searchSort      string
+	searchDirection string
+	searchLimit     int
+	searchOutput    string
+	searchTemplate  string
+	searchColumns   string
+)
+
+// searchParams 直接传给 /v1/search 的查询参数，flag 名与参数名相同
+var searchParams = []struct {
+	name  string
+	usage string
+}{
+	{"repository", "Repository name"},
+	{"format", "Format, e.g. maven2, npm, docker, pypi"},
+	{"group", "Component group (maven groupId, npm scope)"},
+	{"name", "Component name"},
+	{"version", "Component version"},
+	{"sha1", "SHA-1 checksum of an asset"},
+	{"sha256", "SHA-256 checksum of an asset"},
+	{"maven.groupId", "Maven groupId"},
+	{"maven.artifactId", "Maven artifactId"},
+	{"maven.baseVersion", "Maven base version (e.g. 1.0-SNAPSHOT)"},
+	{"maven.extension", "Maven extension (e.g. jar, pom)"},
+	{"maven.classifier", "Maven classifier (e.g. sources)"},
+	{"npm.scope", "npm scope without @"},
+	{"docker.imageName", "Docker image name"},
+	{"docker.imageTag", "Docker image tag"},
+	{"pypi.classifiers", "PyPI classifiers"},
+}
+
+// searchValues search 命令各查询参数 flag 的值
+var searchValues map[string]*string
+
+// searchSortFields Nexus 搜索接口支持的排序字段
+var searchSortFields = []string{"group", "name", "version", "repository"}
+
+var searchCmd = &cobra.Command{
+	Use:   "search [keyword]",
+	Short: "Search components and assets",
+	Long: `Search finds components (or assets with --assets) across repositories using the
+Nexus search API. The optional keyword is a free-text query; the flags filter by
+format-specific coordinates. At least one criterion is required.
+
+Results are sorted by the server (--sort, --direction) and limited with --limit.
+Columns use the field names of the Nexus REST API, e.g. checksum.sha256.`,
+	Example: `  # Which repository holds com.example:lib:1.2.3?
+  nexus-cli search --maven.groupId com.example --maven.artifactId lib --version 1.2.3
+
+  # Which versions of @example/ui exist, newest first?
+  nexus-cli search --format npm --npm.scope example --name ui --sort version --direction desc
+
+  # Tags of a docker image in one repository
+  nexus-cli search --repository docker-hosted --docker.imageName team1/app
+
+  # Find an asset by checksum
+  nexus-cli search --assets --sha256 3a7bd3e2360a3d29eea436fcfb7e44c735d117c42d1c1835420b6b9942dd4f1b`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSearch,
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchValues = addSearchFlags(searchCmd)
+	searchCmd.Flags().BoolVar(&searchAssets, "assets", false, "Search assets instead of components")
+	searchCmd.Flags().StringVar(&searchSort, "sort", "", "Sort by "+strings.Join(searchSortFields, "|"))
+	searchCmd.Flags().StringVar(&searchDirection, "direction", "", "Sort direction (asc|desc)")
+	searchCmd.Flags().IntVar(&searchLimit, "limit", 100, "Maximum number of results (0 for no limit)")
+	searchCmd.Flags().StringVarP(&searchOutput, "output", "o", "table", "Output format (text|json|yaml|table|template)")
+	searchCmd.Flags().StringVar(&searchTemplate, "template", "", "Go template for -o template (the data is a list of results)")
+	searchCmd.Flags().StringVar(&searchColumns, "columns", "", "Comma separated columns for table and text output")
+}
+
+func runSearch(_ *cobra.Command, args []string) error {
+	query, err := searchQuery(args)
+	if err != nil {
+		return err
+	}
+
+	formatter, err := newResourceFormatter(searchOutput, searchTemplate)
+	if err != nil {
+		return err
+	}
+	if searchAssets {
+		formatter.SetColumns([]string{"repository", "format", "path", "fileSize"})
+	} else {
+		formatter.SetColumns([]string{"repository", "format", "group", "name", "version"})
+	}
+	if searchColumns != "" {
+		formatter.SetColumns(strings.Split(searchColumns, ","))
+	}
+
+	status := output.NewFormatter(output.FormatText, os.Stderr)
+	client, err := connectNexus(status)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var results interface{}
+	var count int
+	var truncated bool
+	if searchAssets {
+		var assets []nexus.Asset
+		assets, truncated, err = collect(client.SearchAssets(ctx, query), searchLimit)
+		results, count = assets, len(assets)
+	} else {
+		var components []nexus.Component
+		components, truncated, err = collect(client.SearchComponents(ctx, query), searchLimit)
+		results, count = components, len(components)
+	}
+	if err != nil {
+		return err
+	}
+	if truncated {
+		status.Warning(fmt.Sprintf("Showing the first %d results, use --limit to see more", searchLimit))
+	} else if count == 0 {
+		status.Info("No results found")
+	}
+
+	rows, err := output.ToRows(results)
+	if err != nil {
+		return err
+	}
+	return formatter.Output(rows)
+}
+
+// addSearchFlags 为命令注册 searchParams 中的查询参数 flag，返回 flag 名到值的映射
+func addSearchFlags(cmd *cobra.Command) map[string]*string {
+	values := make(map[string]*string)
+	for _, p := range searchParams {
+		values[p.name] = cmd.Flags().String(p.name, "", p.usage)
+	}
+	return values
+}
+
+// searchCriteria 根据关键字和查询参数 flag 构造查询，至少需要一个条件
+func searchCriteria(args []string, values map[string]*string) (url.Values, error) {
+	query := url.Values{}
+	if len(args) > 0 {
+		query.Set("q", args[0])
+	}
+	for name, value := range values {
+		if *value != "" {
+			query.Set(name, *value)
+		}
+	}
+	if len(query) == 0 {
+		return nil, fmt.Errorf("specify a keyword or at least one search flag")
+	}
+	return query, nil
+}
+
+// searchQuery 根据参数和 flag 构造搜索查询
+func searchQuery(args []string) (url.Values, error) {
+	query, err := searchCriteria(args, searchValues)
+	if err != nil {
+		return nil, err
+	}
+
+	if searchSort != "" {
+		if !containsString(searchSortFields, searchSort) {
+			return nil, fmt.Errorf("invalid --sort %q (must be one of %s)", searchSort, strings.Join(searchSortFields, ", "))
+		}
+		query.Set("sort", searchSort)
+	}
+	switch searchDirection {
+	case "":
+	case "asc", "desc":
+		if searchSort == "" {
+			return nil, fmt.Errorf("--direction requires --sort")
+		}
+		query.Set("direction", searchDirection)
+	default:
+		return nil, fmt.Errorf("invalid --direction %q (must be asc or desc)", searchDirection)
+	}
+	if searchLimit < 0 {
+		return nil, fmt.Errorf("--limit must not be negative")
+	}
+	return query, nil
+}
+
+// collect 读取迭代器的结果，limit 大于 0 时最多读取 limit 项，并返回是否还有更多结果
+func collect[T any](it *nexus.Iterator[T], limit int) ([]T, bool, error) {
+	items := []T{}
+	for it.Next() {
+		if limit > 0 && len(items) == limit {
+			return items, true, nil
+		}
+		items = append(items, it.Item())
+	}
+	return items, false, it.Err()
+}
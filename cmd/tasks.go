@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/alauda/nexus-cli/pkg/nexus"
+	"github.com/alauda/nexus-cli/pkg/output"
+	"github.com/alauda/nexus-cli/pkg/task"
+)
+
+var (
+	tasksType     string
+	tasksOutput   string
+	tasksColumns  string
+	tasksWait     bool
+	tasksTimeout  time.Duration
+	tasksInterval time.Duration
+)
+
+var tasksCmd = &cobra.Command{
+	Use:   "tasks",
+	Short: "List, run and wait for scheduled tasks",
+	Long: `Manage Nexus scheduled tasks such as "Compact blob store" or "Rebuild repository index".
+
+Tasks are declared in the config file under "tasks:" and created by "create"; these commands
+operate on existing tasks. A task is referenced by its ID or its name.`,
+}
+
+var tasksListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List tasks and their state",
+	Example: `  nexus-cli tasks list
+  nexus-cli tasks list --type blobstore.compact -o json`,
+	Args: cobra.NoArgs,
+	RunE: runTasksList,
+}
+
+var tasksRunCmd = &cobra.Command{
+	Use:   "run <task>",
+	Short: "Run a task now",
+	Long: `Run starts a task immediately. With --wait it waits for this run to finish and exits
+with the same codes as "tasks wait".`,
+	Example: `  # Compact the blob store after a bulk delete and wait for it
+  nexus-cli tasks run compact-default --wait --timeout 2h`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTasksRun,
+}
+
+var tasksStopCmd = &cobra.Command{
+	Use:   "stop <task>",
+	Short: "Stop a running task",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTasksStop,
+}
+
+var tasksWaitCmd = &cobra.Command{
+	Use:   "wait <task>",
+	Short: "Wait until a running task finishes",
+	Long: `Wait polls a task until its current run finishes and reports the result of the last run.
+If the task is not running it reports the last result immediately.
+
+Exit codes:
+  0  the task finished successfully
+  1  the task could not be checked (or has never run)
+  2  the task failed
+  3  the task was canceled or interrupted
+  4  the task is still running when --timeout expires`,
+	Example: `  nexus-cli tasks wait rebuild-maven-index --timeout 30m`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runTasksWait,
+}
+
+func init() {
+	rootCmd.AddCommand(tasksCmd)
+	tasksCmd.AddCommand(tasksListCmd, tasksRunCmd, tasksStopCmd, tasksWaitCmd)
+
+	tasksListCmd.Flags().StringVar(&tasksType, "type", "", "Only list tasks of this type (e.g. blobstore.compact)")
+	tasksListCmd.Flags().StringVarP(&tasksOutput, "output", "o", "table", "Output format (text|json|yaml|table)")
+	tasksListCmd.Flags().StringVar(&tasksColumns, "columns", "", "Comma separated columns to show")
+
+	for _, c := range []*cobra.Command{tasksRunCmd, tasksWaitCmd} {
+		c.Flags().DurationVar(&tasksTimeout, "timeout", 0, "Maximum time to wait (0 means no limit)")
+		c.Flags().DurationVar(&tasksInterval, "interval", 5*time.Second, "Polling interval")
+	}
+	tasksRunCmd.Flags().BoolVar(&tasksWait, "wait", false, "Wait for the run to finish")
+}
+
+func runTasksList(_ *cobra.Command, _ []string) error {
+	formatter, err := newResourceFormatter(tasksOutput, "")
+	if err != nil {
+		return err
+	}
+	formatter.SetColumns([]string{"id", "name", "type", "currentState", "lastRunResult", "lastRun", "nextRun"})
+	if tasksColumns != "" {
+		formatter.SetColumns(strings.Split(tasksColumns, ","))
+	}
+
+	client, err := connectNexus(output.NewFormatter(output.FormatText, os.Stderr))
+	if err != nil {
+		return err
+	}
+	tasks, err := client.Tasks(context.Background(), tasksType).All()
+	if err != nil {
+		return err
+	}
+	rows, err := output.ToRows(tasks)
+	if err != nil {
+		return err
+	}
+	return formatter.Output(rows)
+}
+
+func runTasksRun(_ *cobra.Command, args []string) error {
+	status := output.NewFormatter(output.FormatText, os.Stderr)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	client, err := connectNexus(status)
+	if err != nil {
+		return err
+	}
+	previous, err := findTask(ctx, client, args[0])
+	if err != nil {
+		return err
+	}
+	if err := client.RunTask(ctx, previous.ID); err != nil {
+		return err
+	}
+	status.Success(fmt.Sprintf("Started task %s (%s)", previous.Name, previous.ID))
+	if !tasksWait {
+		return nil
+	}
+	return waitForTask(ctx, status, client, previous.ID, previous)
+}
+
+func runTasksStop(_ *cobra.Command, args []string) error {
+	status := output.NewFormatter(output.FormatText, os.Stderr)
+	ctx := context.Background()
+
+	client, err := connectNexus(status)
+	if err != nil {
+		return err
+	}
+	t, err := findTask(ctx, client, args[0])
+	if err != nil {
+		return err
+	}
+	if err := client.StopTask(ctx, t.ID); err != nil {
+		return err
+	}
+	status.Success(fmt.Sprintf("Requested task %s (%s) to stop", t.Name, t.ID))
+	return nil
+}
+
+func runTasksWait(_ *cobra.Command, args []string) error {
+	status := output.NewFormatter(output.FormatText, os.Stderr)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	client, err := connectNexus(status)
+	if err != nil {
+		return err
+	}
+	t, err := findTask(ctx, client, args[0])
+	if err != nil {
+		return err
+	}
+	return waitForTask(ctx, status, client, t.ID, nil)
+}
+
+// findTask 按 ID 或名称查找任务
+func findTask(ctx context.Context, client *nexus.Client, ref string) (*nexus.Task, error) {
+	tasks, err := client.Tasks(ctx, "").All()
+	if err != nil {
+		return nil, err
+	}
+	var byName []nexus.Task
+	for _, t := range tasks {
+		if t.ID == ref {
+			return &t, nil
+		}
+		if t.Name == ref {
+			byName = append(byName, t)
+		}
+	}
+	switch len(byName) {
+	case 0:
+		return nil, fmt.Errorf("task %s not found", ref)
+	case 1:
+		return &byName[0], nil
+	default:
+		return nil, fmt.Errorf("%d tasks are named %s, use the task ID instead", len(byName), ref)
+	}
+}
+
+// waitForTask 等待任务运行结束，根据运行结果返回带退出码的错误
+func waitForTask(ctx context.Context, status *output.Formatter, client *nexus.Client, id string, previous *nexus.Task) error {
+	if tasksTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, tasksTimeout)
+		defer cancel()
+	}
+
+	state := ""
+	progress := func(t *nexus.Task) {
+		if t.CurrentState != state {
+			state = t.CurrentState
+			status.Info(fmt.Sprintf("Task %s is %s", t.Name, strings.ToLower(state)))
+		}
+	}
+	t, err := task.Wait(ctx, client.GetTask, id, task.WaitOptions{Interval: tasksInterval, Previous: previous, Progress: progress})
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &exitError{code: task.ExitTimeout, err: fmt.Errorf("task %s is still running after %s", id, tasksTimeout)}
+	}
+	if err != nil {
+		return err
+	}
+
+	code := task.ExitCode(t.LastRunResult)
+	switch code {
+	case task.ExitOK:
+		status.Success(fmt.Sprintf("Task %s finished: %s", t.Name, t.LastRunResult))
+		return nil
+	case task.ExitError:
+		if t.LastRunResult == "" {
+			return fmt.Errorf("task %s has not run yet", t.Name)
+		}
+		return fmt.Errorf("task %s finished with unknown result %q", t.Name, t.LastRunResult)
+	default:
+		return &exitError{code: code, err: fmt.Errorf("task %s finished: %s", t.Name, t.LastRunResult)}
+	}
+}
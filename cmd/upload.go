@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/alauda/nexus-cli/pkg/nexus"
+	"github.com/alauda/nexus-cli/pkg/output"
+	"github.com/alauda/nexus-cli/pkg/upload"
+)
+
+var (
+	uploadRepository string
+	uploadInclude    []string
+	uploadExclude    []string
+	uploadDirectory  string
+	uploadParallel   int
+	uploadVerify     bool
+	uploadMaven      upload.MavenOptions
+)
+
+// uploadVerifyAttempts 校验时查询搜索接口的次数，上传后搜索索引可能有短暂延迟
+const uploadVerifyAttempts = 5
+
+var uploadCmd = &cobra.Command{
+	Use:   "upload <file|directory>...",
+	Short: "Upload components to a hosted repository",
+	Long: `Upload sends files to a hosted repository through the Nexus components API.
+The repository format decides how files are grouped into components:
+
+  maven2   files are grouped by the .pom next to them (artifactId-version[-classifier].ext),
+           or all files form one component with --maven.groupId/artifactId/version
+  raw      each file is uploaded to --directory, keeping its path below the argument directory
+  yum      like raw, for .rpm files
+  npm, pypi, helm, nuget, rubygems, apt
+           each file is one component
+
+Directories are searched recursively; --include and --exclude filter the files by their
+path relative to the directory (** matches any number of directories, a pattern without /
+matches the file name). Checksum files (.md5, .sha1, ...) are not uploaded for maven2.`,
+	Example: `  # Upload a maven artifact with its pom
+  nexus-cli upload --repository maven-releases lib-1.0.pom lib-1.0.jar lib-1.0-sources.jar
+
+  # Upload a jar without pom
+  nexus-cli upload --repository maven-releases --maven.groupId com.example \
+    --maven.artifactId lib --maven.version 1.0 --maven.generatePom lib-1.0.jar
+
+  # Upload a local maven repository
+  nexus-cli upload --repository maven-releases ~/.m2/repository/com/example
+
+  # Upload a directory to a raw repository and verify the checksums
+  nexus-cli upload --repository raw-hosted --directory /docs --exclude '*.tmp' --verify ./site
+
+  # Upload all npm packages in a directory
+  nexus-cli upload --repository npm-hosted --include '*.tgz' ./dist`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runUpload,
+}
+
+func init() {
+	rootCmd.AddCommand(uploadCmd)
+	uploadCmd.Flags().StringVarP(&uploadRepository, "repository", "r", "", "Hosted repository to upload to (required)")
+	uploadCmd.Flags().StringSliceVar(&uploadInclude, "include", nil, "Only upload files in directories matching these glob patterns")
+	uploadCmd.Flags().StringSliceVar(&uploadExclude, "exclude", nil, "Skip files in directories matching these glob patterns")
+	uploadCmd.Flags().StringVar(&uploadDirectory, "directory", "/", "Target directory for raw and yum repositories")
+	uploadCmd.Flags().IntVar(&uploadParallel, "parallel", 4, "Number of concurrent uploads")
+	uploadCmd.Flags().BoolVar(&uploadVerify, "verify", false, "Verify the SHA-256 checksum of each uploaded file on the server")
+	uploadCmd.Flags().StringVar(&uploadMaven.GroupID, "maven.groupId", "", "Maven groupId (default: read from the pom)")
+	uploadCmd.Flags().StringVar(&uploadMaven.ArtifactID, "maven.artifactId", "", "Maven artifactId")
+	uploadCmd.Flags().StringVar(&uploadMaven.Version, "maven.version", "", "Maven version")
+	uploadCmd.Flags().StringVar(&uploadMaven.Packaging, "maven.packaging", "", "Maven packaging when no pom is uploaded")
+	uploadCmd.Flags().StringVar(&uploadMaven.Classifier, "maven.classifier", "", "Maven classifier for files not named artifactId-version[-classifier].ext")
+	uploadCmd.Flags().BoolVar(&uploadMaven.GeneratePom, "maven.generatePom", false, "Let Nexus generate a pom when no pom is uploaded")
+	_ = uploadCmd.MarkFlagRequired("repository")
+}
+
+func runUpload(_ *cobra.Command, args []string) error {
+	if uploadParallel < 1 {
+		return fmt.Errorf("--parallel must be at least 1")
+	}
+
+	formatter := output.NewFormatter(output.FormatText, os.Stderr)
+	files, err := upload.Collect(args, uploadInclude, uploadExclude)
+	if err != nil {
+		return fmt.Errorf("failed to collect files: %w", err)
+	}
+
+	client, err := connectNexus(formatter)
+	if err != nil {
+		return err
+	}
+
+	repo, err := client.GetRepository(uploadRepository)
+	if err != nil {
+		return err
+	}
+	if repo.Type != "hosted" {
+		return fmt.Errorf("repository %s is a %s repository, only hosted repositories accept uploads", repo.Name, repo.Type)
+	}
+
+	items, err := upload.Plan(repo.Format, files, upload.Options{Directory: uploadDirectory, Maven: uploadMaven})
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var count int
+	for _, item := range items {
+		count += len(item.Files)
+	}
+	formatter.Info(fmt.Sprintf("Uploading %d files (%d requests) to %s...", count, len(items), repo.Name))
+	results := upload.Run(ctx, items, uploadParallel, func(ctx context.Context, item upload.Item) error {
+		if err := client.UploadComponent(ctx, repo.Name, item.Fields, item.Files); err != nil {
+			return err
+		}
+		if uploadVerify {
+			return verifyUpload(ctx, client, repo.Name, item)
+		}
+		return nil
+	})
+
+	var failed int
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+			formatter.Error(fmt.Sprintf("%s: %v", result.Item.Label, result.Err))
+			continue
+		}
+		formatter.Success(fmt.Sprintf("Uploaded %s", result.Item.Label))
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d uploads failed", failed, len(results))
+	}
+	return nil
+}
+
+// verifyUpload 通过搜索接口确认上传的每个文件在仓库中存在相同 SHA-256 的资产
+func verifyUpload(ctx context.Context, client *nexus.Client, repository string, item upload.Item) error {
+	for _, file := range item.Files {
+		sum, err := upload.SHA256(file)
+		if err != nil {
+			return err
+		}
+		query := url.Values{"repository": {repository}, "sha256": {sum}}
+
+		var found bool
+		for attempt := 0; attempt < uploadVerifyAttempts && !found; attempt++ {
+			if attempt > 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(time.Duration(attempt) * time.Second):
+				}
+			}
+			assets, _, err := collect(client.SearchAssets(ctx, query), 1)
+			if err != nil {
+				return fmt.Errorf("failed to verify %s: %w", file, err)
+			}
+			found = len(assets) > 0
+		}
+		if !found {
+			return fmt.Errorf("verification failed: no asset with the SHA-256 of %s (%s) found in %s", file, sum, repository)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/alauda/nexus-cli/pkg/config"
+	"github.com/alauda/nexus-cli/pkg/nexus"
+	"github.com/alauda/nexus-cli/pkg/output"
+	"github.com/alauda/nexus-cli/pkg/password"
+	"github.com/alauda/nexus-cli/pkg/state"
+)
+
+var (
+	rotateLength     int
+	rotateSink       string
+	rotateSinkFile   string
+	rotateTemplate   string
+	rotateOutputFile string
+	rotateMaxAge     string
+	rotateStateFile  string
+)
+
+var usersCmd = &cobra.Command{
+	Use:   "users",
+	Short: "Manage Nexus users",
+}
+
+var usersRotatePasswordCmd = &cobra.Command{
+	Use:   "rotate-password [user-id...]",
+	Short: "Rotate user passwords to strong random values",
+	Long: `Rotate-password replaces the passwords of local Nexus users with randomly generated ones.
+The new passwords are written only to the chosen sink:
+
+  stdout    print each password once
+  file      merge into a YAML file (userId: password) with 0600 permissions
+  template  render the toolchain output template (.Users with .Password)
+
+Without user IDs, the local users from the config file (-c) that use password.generate are
+rotated. Users with a static password in the config are refused, because the next apply
+would change the password back.
+Rotation times are recorded in a local state file so that --max-age only
+rotates accounts whose password is older than the given age.`,
+	Example: `  # Rotate two users and print the new passwords once
+  nexus-cli users rotate-password dev-user ci-user
+
+  # Rotate users from the config whose password is older than 90 days
+  nexus-cli users rotate-password -c config.yaml --max-age 90d --sink file --sink-file passwords.yaml
+
+  # Render the new credentials through a toolchain template
+  nexus-cli users rotate-password ci-user --sink template --output-template templates/toolchain.yaml --output-file ci.yaml`,
+	RunE: runRotatePassword,
+}
+
+func init() {
+	rootCmd.AddCommand(usersCmd)
+	usersCmd.AddCommand(usersRotatePasswordCmd)
+	usersRotatePasswordCmd.Flags().IntVar(&rotateLength, "length", password.DefaultLength, "Length of generated passwords")
+	usersRotatePasswordCmd.Flags().StringVar(&rotateSink, "sink", config.PasswordSinkStdout, "Where to write new passwords (stdout|file|template)")
+	usersRotatePasswordCmd.Flags().StringVar(&rotateSinkFile, "sink-file", "", "Password file for the file sink")
+	usersRotatePasswordCmd.Flags().StringVar(&rotateTemplate, "output-template", "", "Template file for the template sink")
+	usersRotatePasswordCmd.Flags().StringVar(&rotateOutputFile, "output-file", "", "File to write the rendered template to (stdout if not specified)")
+	usersRotatePasswordCmd.Flags().StringVar(&rotateMaxAge, "max-age", "", "Only rotate passwords older than this age (e.g. 90d, 720h)")
+	usersRotatePasswordCmd.Flags().StringVar(&rotateStateFile, "state-file", "", "Local state file (default $NEXUS_CLI_STATE or ~/.nexus-cli/state.yaml)")
+}
+
+func runRotatePassword(_ *cobra.Command, args []string) error {
+	status := output.NewFormatter(output.FormatText, os.Stderr)
+
+	if rotateLength < password.MinLength || rotateLength > password.MaxLength {
+		return fmt.Errorf("--length must be between %d and %d", password.MinLength, password.MaxLength)
+	}
+
+	var tmpl *template.Template
+	switch rotateSink {
+	case config.PasswordSinkStdout:
+	case config.PasswordSinkFile:
+		if rotateSinkFile == "" {
+			return fmt.Errorf("--sink-file is required with --sink file")
+		}
+	case config.PasswordSinkTemplate:
+		if rotateTemplate == "" {
+			return fmt.Errorf("--output-template is required with --sink template")
+		}
+		// 在修改任何密码之前解析模板，避免密码已修改但无法输出
+		data, err := os.ReadFile(rotateTemplate)
+		if err != nil {
+			return fmt.Errorf("failed to read template file: %w", err)
+		}
+		tmpl, err = template.New("toolchain").Parse(string(data))
+		if err != nil {
+			return fmt.Errorf("failed to parse template: %w", err)
+		}
+	default:
+		return fmt.Errorf("invalid --sink %q (must be stdout, file or template)", rotateSink)
+	}
+
+	maxAge, err := parseAge(rotateMaxAge)
+	if err != nil {
+		return err
+	}
+
+	userIDs, err := rotationTargets(args)
+	if err != nil {
+		return err
+	}
+
+	instance, adminUser, _, err := config.GetNexusCredentials()
+	if err != nil {
+		return fmt.Errorf("failed to get Nexus credentials: %w", err)
+	}
+
+	client, err := connectNexus(status)
+	if err != nil {
+		return err
+	}
+
+	stateFile := rotateStateFile
+	if stateFile == "" {
+		stateFile = state.DefaultPath()
+	}
+	st, err := state.Load(stateFile)
+	if err != nil {
+		return err
+	}
+
+	var rotated []*UserWithPassword
+	skipped := 0
+	for _, id := range userIDs {
+		if id == adminUser {
+			status.Warning(fmt.Sprintf("Skipping %s: cannot rotate the password of the user nexus-cli is connected as", id))
+			skipped++
+			continue
+		}
+		if maxAge > 0 {
+			if at, ok := st.PasswordRotatedAt(instance, id); ok && time.Since(at) < maxAge {
+				status.Info(fmt.Sprintf("Skipping %s: password rotated %s ago", id, time.Since(at).Round(time.Hour)))
+				skipped++
+				continue
+			}
+		}
+
+		user, err := client.GetUser(id)
+		if err != nil {
+			return err
+		}
+		if user.Source != nexus.UserSourceDefault {
+			status.Warning(fmt.Sprintf("Skipping %s: passwords of %s users are managed externally", id, user.Source))
+			skipped++
+			continue
+		}
+
+		pass, err := password.Generate(rotateLength)
+		if err != nil {
+			return err
+		}
+		if err := client.ChangePassword(id, pass); err != nil {
+			return err
+		}
+
+		// 密码已修改，立即记录状态并写入 sink，避免后续失败导致密码丢失
+		st.RecordPasswordRotation(instance, id, time.Now())
+		if err := st.Save(); err != nil {
+			return err
+		}
+		cred := password.Credential{UserID: id, Password: pass}
+		switch rotateSink {
+		case config.PasswordSinkFile:
+			if err := password.WriteFile(rotateSinkFile, cred); err != nil {
+				return err
+			}
+		case config.PasswordSinkStdout:
+			if err := password.Print(os.Stdout, cred); err != nil {
+				return err
+			}
+		}
+		rotated = append(rotated, &UserWithPassword{UserResponse: user, Password: pass})
+		status.Success(fmt.Sprintf("Rotated password for user %s", id))
+	}
+
+	if tmpl != nil && len(rotated) > 0 {
+		if err := renderRotatedPasswords(tmpl, rotated); err != nil {
+			return err
+		}
+	}
+
+	status.Info(fmt.Sprintf("Rotated %d password(s), skipped %d", len(rotated), skipped))
+	return nil
+}
+
+// rotationTargets 返回需要轮换密码的用户，未指定时使用配置文件中配置了 password.generate 的本地用户
+//
+// 配置中写了固定密码（值、环境变量或文件）的用户不能轮换：下次 apply 验证配置的密码失败后会把密码改回去。
+func rotationTargets(args []string) ([]string, error) {
+	if len(args) == 0 && cfgFile == "" {
+		return nil, fmt.Errorf("specify user IDs or a config file with -c")
+	}
+	if cfgFile == "" {
+		return args, nil
+	}
+
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if len(args) > 0 {
+		for _, id := range args {
+			for _, u := range cfg.Users {
+				if u.ID == id && u.Password.SecretRef.IsSet() {
+					return nil, fmt.Errorf("user %s has a static password in %s that apply would restore; change it there or use password.generate", id, cfgFile)
+				}
+			}
+		}
+		return args, nil
+	}
+
+	var ids []string
+	for _, u := range cfg.Users {
+		if !u.IsExternal() && u.Password.Generate != nil {
+			ids = append(ids, u.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no local users with password.generate found in %s", cfgFile)
+	}
+	return ids, nil
+}
+
+// renderRotatedPasswords 使用 toolchain 模板输出轮换后的用户凭证
+func renderRotatedPasswords(tmpl *template.Template, users []*UserWithPassword) error {
+	outputCfg, err := newOutputConfig()
+	if err != nil {
+		return err
+	}
+	outputCfg.Users = users
+
+	w := os.Stdout
+	if rotateOutputFile != "" {
+		f, err := os.OpenFile(rotateOutputFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := tmpl.Execute(w, outputCfg); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	return nil
+}
+
+// parseAge 解析时长，除 time.ParseDuration 的格式外还支持以 d 结尾的天数
+func parseAge(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid age %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid age %q: %w", value, err)
+	}
+	return d, nil
+}
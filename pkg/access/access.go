@@ -0,0 +1,229 @@
+// Package access resolves effective repository permissions from users, roles and privileges.
+package access
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/alauda/nexus-cli/pkg/nexus"
+)
+
+// Actions repository-view 权限支持的操作
+var Actions = []string{"browse", "read", "edit", "add", "delete"}
+
+// IsAction 检查操作是否为 repository-view 支持的操作（不区分大小写）
+func IsAction(action string) bool {
+	for _, a := range Actions {
+		if strings.EqualFold(a, action) {
+			return true
+		}
+	}
+	return false
+}
+
+// Repository 仓库名称和 format
+type Repository struct {
+	Name   string `json:"name" yaml:"name"`
+	Format string `json:"format" yaml:"format"`
+}
+
+// Grant 授予访问权限的路径
+type Grant struct {
+	// Roles 从用户直接拥有的角色到包含权限的角色的链路
+	Roles     []string `json:"roles" yaml:"roles"`
+	Privilege string   `json:"privilege" yaml:"privilege"`
+	// ContentSelector 非空时访问权限仅限于内容选择器匹配的内容
+	ContentSelector string `json:"contentSelector,omitempty" yaml:"contentSelector,omitempty"`
+}
+
+// Model 用于计算有效权限的角色和权限集合
+type Model struct {
+	roles      map[string]nexus.RoleResponse
+	privileges map[string]nexus.PrivilegeResponse
+}
+
+// NewModel 创建权限模型
+func NewModel(roles []nexus.RoleResponse, privileges []nexus.PrivilegeResponse) *Model {
+	m := &Model{
+		roles:      make(map[string]nexus.RoleResponse, len(roles)),
+		privileges: make(map[string]nexus.PrivilegeResponse, len(privileges)),
+	}
+	for _, r := range roles {
+		m.roles[r.ID] = r
+	}
+	for _, p := range privileges {
+		m.privileges[p.Name] = p
+	}
+	return m
+}
+
+// UserRoles 返回用户直接拥有的角色，包括 LDAP 组映射的外部角色
+func UserRoles(user nexus.UserResponse) []string {
+	roles := append([]string{}, user.Roles...)
+	for _, r := range user.ExternalRoles {
+		if !contains(roles, r) {
+			roles = append(roles, r)
+		}
+	}
+	return roles
+}
+
+// Check 返回角色列表对仓库执行操作的所有授权路径，为空表示没有权限
+func (m *Model) Check(roles []string, repo Repository, action string) []Grant {
+	action = strings.ToLower(action)
+	var grants []Grant
+	for _, role := range roles {
+		m.walk([]string{role}, repo, action, &grants)
+	}
+	return grants
+}
+
+// walk 深度优先遍历嵌套角色，path 中已出现的角色不会重复访问以避免循环
+func (m *Model) walk(path []string, repo Repository, action string, grants *[]Grant) {
+	role, ok := m.roles[path[len(path)-1]]
+	if !ok {
+		return
+	}
+
+	for _, name := range role.Privileges {
+		priv, ok := m.privileges[name]
+		if !ok {
+			continue
+		}
+		if granted, selector := privilegeGrants(priv, repo, action); granted {
+			*grants = append(*grants, Grant{
+				Roles:           append([]string{}, path...),
+				Privilege:       name,
+				ContentSelector: selector,
+			})
+		}
+	}
+
+	for _, child := range role.Roles {
+		if contains(path, child) {
+			continue
+		}
+		m.walk(append(path, child), repo, action, grants)
+	}
+}
+
+// privilegeGrants 检查单个权限是否允许对仓库执行操作，返回是否允许以及限制访问的内容选择器
+func privilegeGrants(priv nexus.PrivilegeResponse, repo Repository, action string) (bool, string) {
+	switch priv.Type {
+	case "repository-view":
+		return matchValue(priv.Format, repo.Format) && matchValue(priv.Repository, repo.Name) && matchActions(priv.Actions, action), ""
+	case "repository-content-selector":
+		if matchValue(priv.Format, repo.Format) && matchValue(priv.Repository, repo.Name) && matchActions(priv.Actions, action) {
+			return true, priv.ContentSelector
+		}
+		return false, ""
+	case "wildcard":
+		return Implies(priv.Pattern, strings.Join([]string{"nexus", "repository-view", repo.Format, repo.Name, action}, ":")), ""
+	default:
+		return false, ""
+	}
+}
+
+func matchValue(pattern, value string) bool {
+	return pattern == "*" || strings.EqualFold(pattern, value)
+}
+
+func matchActions(actions []string, action string) bool {
+	for _, a := range actions {
+		if strings.EqualFold(a, "ALL") || a == "*" || strings.EqualFold(a, action) {
+			return true
+		}
+	}
+	return false
+}
+
+// Implies 按 Shiro WildcardPermission 的规则检查 pattern 是否包含 permission
+//
+// 两者都以 : 分隔层级，每层可以是逗号分隔的列表；* 匹配任意值，pattern 层级较少时其余层级视为 *。
+func Implies(pattern, permission string) bool {
+	patternParts := strings.Split(strings.ToLower(pattern), ":")
+	permParts := strings.Split(strings.ToLower(permission), ":")
+
+	for i, part := range permParts {
+		if i >= len(patternParts) {
+			return true
+		}
+		allowed := strings.Split(patternParts[i], ",")
+		if contains(allowed, "*") {
+			continue
+		}
+		for _, value := range strings.Split(part, ",") {
+			if !contains(allowed, value) {
+				return false
+			}
+		}
+	}
+	for _, part := range patternParts[len(permParts):] {
+		if !contains(strings.Split(part, ","), "*") {
+			return false
+		}
+	}
+	return true
+}
+
+// UserAccess 用户及其授权路径
+type UserAccess struct {
+	UserID string  `json:"userId" yaml:"userId"`
+	Source string  `json:"source" yaml:"source"`
+	Grants []Grant `json:"grants" yaml:"grants"`
+}
+
+// WhoCan 返回可以对仓库执行操作的用户
+func (m *Model) WhoCan(users []nexus.UserResponse, repo Repository, action string) []UserAccess {
+	var result []UserAccess
+	for _, user := range users {
+		if grants := m.Check(UserRoles(user), repo, action); len(grants) > 0 {
+			result = append(result, UserAccess{UserID: user.UserID, Source: user.Source, Grants: grants})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].UserID < result[j].UserID })
+	return result
+}
+
+// MatrixRow 访问矩阵中的一行：用户对仓库允许的操作
+type MatrixRow struct {
+	UserID     string          `json:"userId" yaml:"userId"`
+	Repository string          `json:"repository" yaml:"repository"`
+	Actions    map[string]bool `json:"actions" yaml:"actions"`
+}
+
+// Matrix 计算用户 × 仓库 × 操作的访问矩阵，只包含至少允许一个操作的组合
+func (m *Model) Matrix(users []nexus.UserResponse, repos []Repository) []MatrixRow {
+	var rows []MatrixRow
+	for _, user := range users {
+		roles := UserRoles(user)
+		for _, repo := range repos {
+			row := MatrixRow{UserID: user.UserID, Repository: repo.Name, Actions: make(map[string]bool)}
+			granted := false
+			for _, action := range Actions {
+				allowed := len(m.Check(roles, repo, action)) > 0
+				row.Actions[action] = allowed
+				granted = granted || allowed
+			}
+			if granted {
+				rows = append(rows, row)
+			}
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].UserID != rows[j].UserID {
+			return rows[i].UserID < rows[j].UserID
+		}
+		return rows[i].Repository < rows[j].Repository
+	})
+	return rows
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
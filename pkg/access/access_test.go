@@ -0,0 +1,103 @@
+package access
+
+import (
+	"testing"
+
+	"github.com/alauda/nexus-cli/pkg/nexus"
+)
+
+func testModel() *Model {
+	roles := []nexus.RoleResponse{
+		{ID: "nx-admin", Privileges: []string{"nx-all"}},
+		{ID: "developer", Roles: []string{"maven-deployer", "cycle-a"}},
+		{ID: "maven-deployer", Privileges: []string{"maven-releases-write"}},
+		{ID: "reader", Privileges: []string{"nx-repository-view-*-*-read"}},
+		{ID: "selector-role", Privileges: []string{"team1-content"}},
+		{ID: "cycle-a", Roles: []string{"cycle-b"}},
+		{ID: "cycle-b", Roles: []string{"cycle-a"}},
+	}
+	privileges := []nexus.PrivilegeResponse{
+		{Name: "nx-all", Type: "wildcard", Pattern: "nexus:*"},
+		{Name: "maven-releases-write", Type: "repository-view", Format: "maven2", Repository: "maven-releases", Actions: []string{"ADD", "EDIT"}},
+		{Name: "nx-repository-view-*-*-read", Type: "repository-view", Format: "*", Repository: "*", Actions: []string{"READ"}},
+		{Name: "team1-content", Type: "repository-content-selector", Format: "maven2", Repository: "*", ContentSelector: "team1", Actions: []string{"ALL"}},
+	}
+	return NewModel(roles, privileges)
+}
+
+func TestCheck(t *testing.T) {
+	m := testModel()
+	releases := Repository{Name: "maven-releases", Format: "maven2"}
+	npm := Repository{Name: "npm-hosted", Format: "npm"}
+
+	tests := []struct {
+		name         string
+		roles        []string
+		repo         Repository
+		action       string
+		wantGrants   int
+		wantChain    []string
+		wantSelector string
+	}{
+		{name: "admin wildcard", roles: []string{"nx-admin"}, repo: npm, action: "delete", wantGrants: 1, wantChain: []string{"nx-admin"}},
+		{name: "nested role", roles: []string{"developer"}, repo: releases, action: "EDIT", wantGrants: 1, wantChain: []string{"developer", "maven-deployer"}},
+		{name: "nested role without action", roles: []string{"developer"}, repo: releases, action: "delete"},
+		{name: "builtin all-repository read", roles: []string{"reader"}, repo: npm, action: "read", wantGrants: 1, wantChain: []string{"reader"}},
+		{name: "content selector", roles: []string{"selector-role"}, repo: releases, action: "read", wantGrants: 1, wantChain: []string{"selector-role"}, wantSelector: "team1"},
+		{name: "unknown role", roles: []string{"missing"}, repo: npm, action: "read"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grants := m.Check(tt.roles, tt.repo, tt.action)
+			if len(grants) != tt.wantGrants {
+				t.Fatalf("Check() returned %d grants, want %d: %+v", len(grants), tt.wantGrants, grants)
+			}
+			if tt.wantGrants == 0 {
+				return
+			}
+			if got := grants[0].Roles; len(got) != len(tt.wantChain) || got[len(got)-1] != tt.wantChain[len(tt.wantChain)-1] {
+				t.Errorf("Check() role chain = %v, want %v", got, tt.wantChain)
+			}
+			if grants[0].ContentSelector != tt.wantSelector {
+				t.Errorf("Check() content selector = %q, want %q", grants[0].ContentSelector, tt.wantSelector)
+			}
+		})
+	}
+}
+
+func TestImplies(t *testing.T) {
+	tests := []struct {
+		pattern    string
+		permission string
+		want       bool
+	}{
+		{"nexus:*", "nexus:repository-view:maven2:maven-releases:read", true},
+		{"nexus:repository-view:*:*:read", "nexus:repository-view:npm:npm-hosted:read", true},
+		{"nexus:repository-view:*:*:read", "nexus:repository-view:npm:npm-hosted:edit", false},
+		{"nexus:repository-view:maven2:maven-releases,maven-snapshots:read,add", "nexus:repository-view:maven2:maven-snapshots:add", true},
+		{"nexus:repository-view:maven2", "nexus:repository-view:maven2:maven-releases:delete", true},
+		{"nexus:repository-admin:*:*:*", "nexus:repository-view:maven2:maven-releases:read", false},
+		{"nexus:repository-view:*:*:*:extra", "nexus:repository-view:maven2:r:read", false},
+	}
+
+	for _, tt := range tests {
+		if got := Implies(tt.pattern, tt.permission); got != tt.want {
+			t.Errorf("Implies(%q, %q) = %v, want %v", tt.pattern, tt.permission, got, tt.want)
+		}
+	}
+}
+
+func TestWhoCan(t *testing.T) {
+	m := testModel()
+	users := []nexus.UserResponse{
+		{UserID: "bob", Roles: []string{"reader"}},
+		{UserID: "alice", Roles: []string{"developer"}},
+		{UserID: "carol", ExternalRoles: []string{"nx-admin"}},
+	}
+
+	got := m.WhoCan(users, Repository{Name: "maven-releases", Format: "maven2"}, "add")
+	if len(got) != 2 || got[0].UserID != "alice" || got[1].UserID != "carol" {
+		t.Errorf("WhoCan() = %+v, want alice and carol", got)
+	}
+}
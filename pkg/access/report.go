@@ -0,0 +1,57 @@
+package access
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteCSV 以 CSV 格式输出访问矩阵
+func WriteCSV(w io.Writer, rows []MatrixRow) error {
+	cw := csv.NewWriter(w)
+	header := append([]string{"user", "repository"}, Actions...)
+	if err := cw.Write(header); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	for _, row := range rows {
+		record := []string{row.UserID, row.Repository}
+		for _, action := range Actions {
+			record = append(record, yesNo(row.Actions[action]))
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write csv: %w", err)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteMarkdown 以 Markdown 表格输出访问矩阵
+func WriteMarkdown(w io.Writer, rows []MatrixRow) error {
+	header := append([]string{"User", "Repository"}, Actions...)
+	lines := []string{
+		"| " + strings.Join(header, " | ") + " |",
+		"|" + strings.Repeat(" --- |", len(header)),
+	}
+	for _, row := range rows {
+		cells := []string{row.UserID, row.Repository}
+		for _, action := range Actions {
+			mark := ""
+			if row.Actions[action] {
+				mark = "✓"
+			}
+			cells = append(cells, mark)
+		}
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+	}
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -4,13 +4,15 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
 // Load 从文件加载配置
-func Load(filepath string) (*Config, error) {
-	data, err := os.ReadFile(filepath)
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
@@ -20,23 +22,51 @@ func Load(filepath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
 	return &config, nil
 }
 
+// NameFromPath 返回配置文件的名称（不含目录和扩展名），用于标记该配置生成的资源
+//
+// 字母、数字、点、下划线和连字符以外的字符替换为连字符，使名称可以用在角色 ID 中。
+func NameFromPath(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+			return r
+		default:
+			return '-'
+		}
+	}, name)
+}
+
+// DefaultProfile 默认连接配置的环境变量前缀
+const DefaultProfile = "NEXUS"
+
 // GetNexusCredentials 从环境变量获取 Nexus 认证信息
 func GetNexusCredentials() (url, username, password string, err error) {
-	url = os.Getenv("NEXUS_URL")
-	username = os.Getenv("NEXUS_USERNAME")
-	password = os.Getenv("NEXUS_PASSWORD")
+	return GetNexusProfileCredentials(DefaultProfile)
+}
+
+// GetNexusProfileCredentials 从 <profile>_URL、<profile>_USERNAME 和 <profile>_PASSWORD 环境变量获取认证信息，
+// 用于同时连接多个 Nexus 实例，例如 NEXUS_SOURCE_URL
+func GetNexusProfileCredentials(profile string) (url, username, password string, err error) {
+	url = os.Getenv(profile + "_URL")
+	username = os.Getenv(profile + "_USERNAME")
+	password = os.Getenv(profile + "_PASSWORD")
 
 	if url == "" {
-		return "", "", "", fmt.Errorf("NEXUS_URL environment variable is not set")
+		return "", "", "", fmt.Errorf("%s_URL environment variable is not set", profile)
 	}
 	if username == "" {
-		return "", "", "", fmt.Errorf("NEXUS_USERNAME environment variable is not set")
+		return "", "", "", fmt.Errorf("%s_USERNAME environment variable is not set", profile)
 	}
 	if password == "" {
-		return "", "", "", fmt.Errorf("NEXUS_PASSWORD environment variable is not set")
+		return "", "", "", fmt.Errorf("%s_PASSWORD environment variable is not set", profile)
 	}
 
 	return url, username, password, nil
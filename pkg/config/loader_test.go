@@ -116,3 +116,36 @@ func containsInside(s, substr string) bool {
 	}
 	return false
 }
+
+func TestLoadExampleConfigs(t *testing.T) {
+	files := []string{
+		"../../config/team-admin.yaml",
+		"../../config/repository-manager.yaml",
+		"../../config/team-repositories.yaml",
+	}
+
+	for _, file := range files {
+		t.Run(file, func(t *testing.T) {
+			if _, err := Load(file); err != nil {
+				t.Errorf("Load(%s) unexpected error = %v", file, err)
+			}
+		})
+	}
+}
+
+func TestNameFromPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"config/team-repositories.yaml", "team-repositories"},
+		{"/etc/nexus/team2.yml", "team2"},
+		{"my config.v2.yaml", "my-config.v2"},
+		{"team1", "team1"},
+	}
+	for _, tt := range tests {
+		if got := NameFromPath(tt.path); got != tt.want {
+			t.Errorf("NameFromPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SecretRef 敏感值引用，可以来自环境变量、文件或直接写在配置中
+//
+// 支持以下写法：
+//
+//	authPassword: "plain-text"          # 直接写值（不推荐）
+//	authPassword: {env: LDAP_PASSWORD}  # 从环境变量读取
+//	authPassword: {file: /run/secrets/ldap}  # 从文件读取（去除首尾空白）
+type SecretRef struct {
+	Value string `yaml:"value,omitempty"`
+	Env   string `yaml:"env,omitempty"`
+	File  string `yaml:"file,omitempty"`
+}
+
+// UnmarshalYAML 支持标量字符串和引用对象两种写法
+func (s *SecretRef) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		s.Value = node.Value
+		return nil
+	}
+	type plain SecretRef
+	return node.Decode((*plain)(s))
+}
+
+// IsSet 检查是否配置了值或引用
+func (s SecretRef) IsSet() bool {
+	return s.Value != "" || s.Env != "" || s.File != ""
+}
+
+// Resolve 解析敏感值
+func (s SecretRef) Resolve() (string, error) {
+	switch {
+	case s.Env != "":
+		value := os.Getenv(s.Env)
+		if value == "" {
+			return "", fmt.Errorf("environment variable %s is not set", s.Env)
+		}
+		return value, nil
+	case s.File != "":
+		data, err := os.ReadFile(s.File)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret file: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	default:
+		return s.Value, nil
+	}
+}
+
+// validate 检查引用是否只使用了一种来源
+func (s SecretRef) validate() error {
+	sources := 0
+	for _, v := range []string{s.Value, s.Env, s.File} {
+		if v != "" {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return fmt.Errorf("only one of value, env or file may be set")
+	}
+	return nil
+}
+
+// 生成密码的输出位置
+const (
+	PasswordSinkStdout   = "stdout"
+	PasswordSinkFile     = "file"
+	PasswordSinkTemplate = "template"
+)
+
+// PasswordConfig 用户密码配置
+//
+// 除 SecretRef 的写法外，还可以让 nexus-cli 在创建用户时生成随机密码：
+//
+//	password: "plain-text"
+//	password: {env: DEV_USER_PASSWORD}
+//	password: {generate: {length: 24, sink: file, file: ./passwords.yaml}}
+type PasswordConfig struct {
+	SecretRef `yaml:",inline"`
+	Generate  *PasswordGenerateConfig `yaml:"generate,omitempty"`
+}
+
+// PasswordGenerateConfig 随机密码生成配置
+type PasswordGenerateConfig struct {
+	// Length 密码长度，默认 24
+	Length int `yaml:"length,omitempty"`
+	// Sink 生成的密码写到哪里：stdout（默认，只输出一次）、file 或 template（create --output-template 的 .Password）
+	Sink string `yaml:"sink,omitempty"`
+	// File sink 为 file 时写入的文件，权限为 0600
+	File string `yaml:"file,omitempty"`
+}
+
+// UnmarshalYAML 支持标量字符串和对象两种写法
+func (p *PasswordConfig) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		p.Value = node.Value
+		return nil
+	}
+	// 不能用类型别名解码：内嵌 SecretRef 的 UnmarshalYAML 会被提升，导致 generate 被忽略
+	var raw struct {
+		Value    string                  `yaml:"value"`
+		Env      string                  `yaml:"env"`
+		File     string                  `yaml:"file"`
+		Generate *PasswordGenerateConfig `yaml:"generate"`
+	}
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	p.SecretRef = SecretRef{Value: raw.Value, Env: raw.Env, File: raw.File}
+	p.Generate = raw.Generate
+	return nil
+}
+
+// IsSet 检查是否配置了密码或密码生成
+func (p PasswordConfig) IsSet() bool {
+	return p.SecretRef.IsSet() || p.Generate != nil
+}
+
+// SinkName 返回生成密码的输出位置，未设置时为 stdout
+func (g PasswordGenerateConfig) SinkName() string {
+	if g.Sink == "" {
+		return PasswordSinkStdout
+	}
+	return g.Sink
+}
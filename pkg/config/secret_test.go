@@ -0,0 +1,83 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSecretRef(t *testing.T) {
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "secret")
+	if err := os.WriteFile(secretFile, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("NEXUS_CLI_TEST_SECRET", "from-env")
+
+	tests := []struct {
+		name    string
+		yaml    string
+		want    string
+		wantErr bool
+	}{
+		{name: "plain string", yaml: `password: plain`, want: "plain"},
+		{name: "env reference", yaml: `password: {env: NEXUS_CLI_TEST_SECRET}`, want: "from-env"},
+		{name: "file reference", yaml: "password: {file: " + secretFile + "}", want: "from-file"},
+		{name: "missing env", yaml: `password: {env: NEXUS_CLI_TEST_MISSING}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc struct {
+				Password SecretRef `yaml:"password"`
+			}
+			if err := yaml.Unmarshal([]byte(tt.yaml), &doc); err != nil {
+				t.Fatalf("yaml.Unmarshal() error = %v", err)
+			}
+
+			got, err := doc.Password.Resolve()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Resolve() expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Resolve() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPasswordConfigUnmarshal(t *testing.T) {
+	var users []User
+	data := `
+- id: plain
+  password: "secret"
+- id: generated
+  password:
+    generate:
+      length: 32
+      sink: file
+      file: /tmp/passwords.yaml
+`
+	if err := yaml.Unmarshal([]byte(data), &users); err != nil {
+		t.Fatalf("yaml.Unmarshal() error = %v", err)
+	}
+
+	if users[0].Password.Value != "secret" || users[0].Password.Generate != nil {
+		t.Errorf("plain password = %+v, want value secret without generate", users[0].Password)
+	}
+	gen := users[1].Password.Generate
+	if gen == nil || gen.Length != 32 || gen.SinkName() != PasswordSinkFile || gen.File != "/tmp/passwords.yaml" {
+		t.Errorf("generated password = %+v, want length 32 with file sink", gen)
+	}
+	if users[1].Password.SecretRef.IsSet() {
+		t.Error("generated password should not set a secret reference")
+	}
+}
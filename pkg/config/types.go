@@ -1,10 +1,19 @@
 // Package config provides configuration types and loading functionality.
 package config
 
+import "strings"
+
 // Config 主配置结构
 type Config struct {
+	BlobStores                []BlobStore                `yaml:"blobStores"`
+	CleanupPolicies           []CleanupPolicy            `yaml:"cleanupPolicies"`
+	ContentSelectors          []ContentSelector          `yaml:"contentSelectors"`
+	RoutingRules              []RoutingRule              `yaml:"routingRules"`
+	LDAP                      *LDAPConfig                `yaml:"ldap,omitempty"`
+	Security                  *SecurityConfig            `yaml:"security,omitempty"`
 	Users                     []User                     `yaml:"users"`
 	Repositories              []Repository               `yaml:"repositories"`
+	Tasks                     []Task                     `yaml:"tasks"`
 	Privileges                []Privilege                `yaml:"privileges"`
 	Roles                     []Role                     `yaml:"roles"`
 	UserRepositoryPermissions []UserRepositoryPermission `yaml:"userRepositoryPermissions"`
@@ -12,27 +21,60 @@ type Config struct {
 
 // User 用户配置
 type User struct {
-	ID           string   `yaml:"id"`
-	FirstName    string   `yaml:"firstName"`
-	LastName     string   `yaml:"lastName"`
-	EmailAddress string   `yaml:"emailAddress"`
-	Password     string   `yaml:"password"`
-	Status       string   `yaml:"status"`
-	Roles        []string `yaml:"roles"`
+	ID           string `yaml:"id"`
+	FirstName    string `yaml:"firstName"`
+	LastName     string `yaml:"lastName"`
+	EmailAddress string `yaml:"emailAddress"`
+	// Password 密码，可以直接写值、引用环境变量或文件，或通过 generate 在创建用户时生成
+	Password PasswordConfig `yaml:"password"`
+	// Status 用户状态：active、disabled、locked 或 changepassword（密码过期，下次登录需修改），默认 active
+	Status string   `yaml:"status"`
+	Roles  []string `yaml:"roles"`
+	// Source 用户来源，默认为本地用户；LDAP 用户只管理角色，不会创建或修改密码
+	Source string `yaml:"source,omitempty"`
+}
+
+// 用户状态
+const (
+	UserStatusActive         = "active"
+	UserStatusDisabled       = "disabled"
+	UserStatusLocked         = "locked"
+	UserStatusChangePassword = "changepassword"
+)
+
+// 用户和角色来源
+const (
+	SourceDefault = "default"
+	SourceLDAP    = "LDAP"
+)
+
+// IsExternal 检查用户是否来自外部来源（如 LDAP）
+func (u User) IsExternal() bool {
+	return u.Source != "" && u.Source != SourceDefault
 }
 
 // Repository 仓库配置
 type Repository struct {
-	Name    string         `yaml:"name"`
-	Format  string         `yaml:"format"`
-	Type    string         `yaml:"type"`
-	Online  bool           `yaml:"online"`
-	Storage StorageConfig  `yaml:"storage"`
-	Proxy   *ProxyConfig   `yaml:"proxy,omitempty"`
-	Maven   *MavenConfig   `yaml:"maven,omitempty"`
-	Docker  *DockerConfig  `yaml:"docker,omitempty"`
-	Apt     *AptConfig     `yaml:"apt,omitempty"`
-	Cleanup *CleanupConfig `yaml:"cleanup,omitempty"`
+	Name   string `yaml:"name"`
+	Format string `yaml:"format"`
+	Type   string `yaml:"type"`
+	// Online 是否在线，默认 true；已存在的仓库会按该设置上线或下线，无需重建
+	Online        *bool                `yaml:"online,omitempty"`
+	Storage       StorageConfig        `yaml:"storage"`
+	Proxy         *ProxyConfig         `yaml:"proxy,omitempty"`
+	Maven         *MavenConfig         `yaml:"maven,omitempty"`
+	Docker        *DockerConfig        `yaml:"docker,omitempty"`
+	DockerProxy   *DockerProxyConfig   `yaml:"dockerProxy,omitempty"`
+	Apt           *AptConfig           `yaml:"apt,omitempty"`
+	Cleanup       *CleanupConfig       `yaml:"cleanup,omitempty"`
+	NegativeCache *NegativeCacheConfig `yaml:"negativeCache,omitempty"`
+	HTTPClient    *HTTPClientConfig    `yaml:"httpClient,omitempty"`
+	RoutingRule   string               `yaml:"routingRule,omitempty"`
+}
+
+// IsOnline 返回仓库是否在线，未设置时为 true
+func (r Repository) IsOnline() bool {
+	return r.Online == nil || *r.Online
 }
 
 // StorageConfig 存储配置
@@ -50,6 +92,36 @@ type ProxyConfig struct {
 	Authentication *AuthConfig `yaml:"authentication,omitempty"`
 }
 
+// NegativeCacheConfig 负缓存配置（仅 proxy 仓库），未设置的字段使用默认值
+type NegativeCacheConfig struct {
+	Enabled    *bool `yaml:"enabled,omitempty"`
+	TimeToLive *int  `yaml:"timeToLive,omitempty"`
+}
+
+// HTTPClientConfig 代理仓库访问上游时的 HTTP 客户端配置
+type HTTPClientConfig struct {
+	Blocked    bool                  `yaml:"blocked"`
+	AutoBlock  *bool                 `yaml:"autoBlock,omitempty"`
+	Connection *HTTPConnectionConfig `yaml:"connection,omitempty"`
+}
+
+// HTTPConnectionConfig HTTP 连接配置
+type HTTPConnectionConfig struct {
+	Timeout                 *int   `yaml:"timeout,omitempty"`
+	Retries                 *int   `yaml:"retries,omitempty"`
+	UserAgentSuffix         string `yaml:"userAgentSuffix,omitempty"`
+	EnableCircularRedirects bool   `yaml:"enableCircularRedirects,omitempty"`
+	EnableCookies           bool   `yaml:"enableCookies,omitempty"`
+	UseTrustStore           bool   `yaml:"useTrustStore,omitempty"`
+}
+
+// 代理仓库默认值（与 Nexus UI 创建仓库时的默认值一致）
+const (
+	DefaultNegativeCacheTTL = 1440
+	DefaultHTTPTimeout      = 60
+	DefaultHTTPRetries      = 3
+)
+
 // AuthConfig 认证配置
 type AuthConfig struct {
 	Type       string `yaml:"type"`
@@ -71,7 +143,34 @@ type DockerConfig struct {
 	HTTPSPort      int    `yaml:"httpsPort,omitempty"`
 	ForceBasicAuth bool   `yaml:"forceBasicAuth"`
 	V1Enabled      bool   `yaml:"v1Enabled"`
-	SubdomainAddr  string `yaml:"subdomainAddr,omitempty"`
+	Subdomain      string `yaml:"subdomain,omitempty"`
+	PathEnabled    *bool  `yaml:"pathEnabled,omitempty"`
+	LatestPolicy   *bool  `yaml:"latestPolicy,omitempty"`
+	// Deprecated: 使用 subdomain，保留以兼容旧配置
+	SubdomainAddr string `yaml:"subdomainAddr,omitempty"`
+}
+
+// SubdomainName 返回子域名配置（兼容旧的 subdomainAddr 字段）
+func (d *DockerConfig) SubdomainName() string {
+	if d.Subdomain != "" {
+		return d.Subdomain
+	}
+	return d.SubdomainAddr
+}
+
+// Docker 代理索引类型
+const (
+	DockerIndexRegistry = "REGISTRY"
+	DockerIndexHub      = "HUB"
+	DockerIndexCustom   = "CUSTOM"
+)
+
+// DockerProxyConfig Docker 代理仓库配置
+type DockerProxyConfig struct {
+	IndexType                string   `yaml:"indexType"`
+	IndexURL                 string   `yaml:"indexUrl,omitempty"`
+	CacheForeignLayers       bool     `yaml:"cacheForeignLayers,omitempty"`
+	ForeignLayerURLWhitelist []string `yaml:"foreignLayerUrlWhitelist,omitempty"`
 }
 
 // AptConfig Apt 仓库配置
@@ -85,6 +184,78 @@ type CleanupConfig struct {
 	PolicyNames []string `yaml:"policyNames"`
 }
 
+// CleanupPolicy 清理策略配置
+type CleanupPolicy struct {
+	Name     string          `yaml:"name"`
+	Notes    string          `yaml:"notes,omitempty"`
+	Format   string          `yaml:"format"`
+	Criteria CleanupCriteria `yaml:"criteria"`
+}
+
+// CleanupCriteria 清理条件，多个条件同时满足时组件才会被清理
+type CleanupCriteria struct {
+	// LastBlobUpdated 组件最后更新超过指定天数
+	LastBlobUpdated *int `yaml:"lastBlobUpdated,omitempty"`
+	// LastDownloaded 组件最后下载超过指定天数
+	LastDownloaded *int `yaml:"lastDownloaded,omitempty"`
+	// ReleaseType RELEASES 或 PRERELEASES
+	ReleaseType string `yaml:"releaseType,omitempty"`
+	// AssetRegex 匹配资产路径的正则表达式
+	AssetRegex string `yaml:"assetRegex,omitempty"`
+	// Retain 保留最近的 N 个版本（仅 maven2 和 docker）
+	Retain int `yaml:"retain,omitempty"`
+	// SortBy 保留版本的排序方式: version 或 date
+	SortBy string `yaml:"sortBy,omitempty"`
+}
+
+// RoutingRule 路由规则配置
+type RoutingRule struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	// Mode BLOCK 阻止匹配的请求，ALLOW 只允许匹配的请求
+	Mode string `yaml:"mode"`
+	// Matchers 匹配请求路径的正则表达式（需匹配完整路径）
+	Matchers []string `yaml:"matchers"`
+}
+
+// Task 计划任务配置，按名称与服务器上的任务匹配
+type Task struct {
+	Name string `yaml:"name"`
+	// Type 任务类型，例如 blobstore.compact、repository.rebuild-index、repository.maven.rebuild-metadata
+	Type string `yaml:"type"`
+	// Enabled 是否启用，默认启用
+	Enabled    *bool  `yaml:"enabled,omitempty"`
+	AlertEmail string `yaml:"alertEmail,omitempty"`
+	// NotificationCondition 发送提醒邮件的条件：FAILURE 或 SUCCESS_FAILURE
+	NotificationCondition string       `yaml:"notificationCondition,omitempty"`
+	Schedule              TaskSchedule `yaml:"schedule"`
+	// Properties 任务参数，例如 blobstoreName、repositoryName
+	Properties map[string]string `yaml:"properties,omitempty"`
+}
+
+// TaskSchedule 任务执行计划
+type TaskSchedule struct {
+	// Type manual、once、hourly、daily、weekly、monthly 或 cron，默认 manual（只手动运行）
+	Type string `yaml:"type,omitempty"`
+	// Start 开始时间（RFC 3339，例如 2024-01-01T02:00:00+08:00），除 manual 和 cron 外必填
+	Start string `yaml:"start,omitempty"`
+	// Days weekly 为星期几（1-7），monthly 为日期（1-31）
+	Days []int `yaml:"days,omitempty"`
+	// Cron Quartz cron 表达式，例如 "0 0 2 * * ?"
+	Cron string `yaml:"cron,omitempty"`
+}
+
+// 任务执行计划类型
+const (
+	TaskScheduleManual  = "manual"
+	TaskScheduleOnce    = "once"
+	TaskScheduleHourly  = "hourly"
+	TaskScheduleDaily   = "daily"
+	TaskScheduleWeekly  = "weekly"
+	TaskScheduleMonthly = "monthly"
+	TaskScheduleCron    = "cron"
+)
+
 // Privilege 权限配置
 type Privilege struct {
 	Name        string   `yaml:"name"`
@@ -93,6 +264,22 @@ type Privilege struct {
 	Format      string   `yaml:"format"`
 	Repository  string   `yaml:"repository"`
 	Actions     []string `yaml:"actions"`
+	// ContentSelector repository-content-selector 类型引用的内容选择器
+	ContentSelector string `yaml:"contentSelector,omitempty"`
+	// Pattern wildcard 类型的权限表达式，例如 nexus:repository-view:*:*:read
+	Pattern string `yaml:"pattern,omitempty"`
+	// Domain application 类型的权限域，例如 users
+	Domain string `yaml:"domain,omitempty"`
+	// ScriptName script 类型引用的脚本名称
+	ScriptName string `yaml:"scriptName,omitempty"`
+}
+
+// ContentSelector 内容选择器配置
+type ContentSelector struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	// Expression CSEL 表达式，例如 format == "maven2" and path =^ "/com/example/"
+	Expression string `yaml:"expression"`
 }
 
 // Role 角色配置
@@ -102,11 +289,168 @@ type Role struct {
 	Description string   `yaml:"description"`
 	Privileges  []string `yaml:"privileges"`
 	Roles       []string `yaml:"roles,omitempty"`
+	// Source 为 LDAP 时表示外部角色映射，ID 必须是 LDAP 组名
+	Source string `yaml:"source,omitempty"`
 }
 
-// UserRepositoryPermission 用户仓库权限映射
+// UserRepositoryPermission 仓库权限授予
+//
+// 授予对象为 userId、roleId 或 group 之一。同一授予对象的所有条目会合并到一个自动生成的角色中。
 type UserRepositoryPermission struct {
-	UserID     string   `yaml:"userId"`
-	Repository string   `yaml:"repository"`
-	Privileges []string `yaml:"privileges"`
+	UserID string `yaml:"userId,omitempty"`
+	// RoleID 授予已有角色（在配置中声明或已存在于 Nexus）
+	RoleID string `yaml:"roleId,omitempty"`
+	// Group 授予 LDAP 组，通过同名的外部角色映射生效
+	Group string `yaml:"group,omitempty"`
+	// Repository 仓库名称，支持通配符（如 team1-*），单独的 * 表示所有仓库
+	Repository string `yaml:"repository"`
+	// Privileges repository-view 操作：READ、BROWSE、ADD、EDIT、DELETE 或 ALL
+	Privileges []string `yaml:"privileges,omitempty"`
+	// AdminPrivileges repository-admin 操作（管理仓库配置）：READ、BROWSE、ADD、EDIT、DELETE 或 ALL
+	AdminPrivileges []string `yaml:"adminPrivileges,omitempty"`
+}
+
+// IsWildcard 检查仓库名称是否包含通配符
+func (p UserRepositoryPermission) IsWildcard() bool {
+	return strings.ContainsAny(p.Repository, "*?[")
+}
+
+// RepositoryPermissionActions 仓库权限支持的操作
+var RepositoryPermissionActions = []string{"READ", "BROWSE", "ADD", "EDIT", "DELETE", "ALL"}
+
+// Blob store 类型
+const (
+	BlobStoreTypeFile = "file"
+	BlobStoreTypeS3   = "s3"
+)
+
+// BlobStore blob store 配置
+type BlobStore struct {
+	Name      string             `yaml:"name"`
+	Type      string             `yaml:"type"`
+	Path      string             `yaml:"path,omitempty"`
+	SoftQuota *SoftQuotaConfig   `yaml:"softQuota,omitempty"`
+	S3        *S3BlobStoreConfig `yaml:"s3,omitempty"`
+}
+
+// SoftQuotaConfig blob store 软配额配置
+type SoftQuotaConfig struct {
+	// Type 配额类型: spaceRemainingQuota 或 spaceUsedQuota
+	Type string `yaml:"type"`
+	// Limit 配额上限（字节）
+	Limit int64 `yaml:"limit"`
+}
+
+// S3BlobStoreConfig S3 blob store 配置
+type S3BlobStoreConfig struct {
+	Region                string `yaml:"region"`
+	Bucket                string `yaml:"bucket"`
+	Prefix                string `yaml:"prefix,omitempty"`
+	Expiration            int    `yaml:"expiration"`
+	AccessKeyID           string `yaml:"accessKeyId,omitempty"`
+	SecretAccessKey       string `yaml:"secretAccessKey,omitempty"`
+	Role                  string `yaml:"role,omitempty"`
+	SessionToken          string `yaml:"sessionToken,omitempty"`
+	EncryptionType        string `yaml:"encryptionType,omitempty"`
+	EncryptionKey         string `yaml:"encryptionKey,omitempty"`
+	Endpoint              string `yaml:"endpoint,omitempty"`
+	SignerType            string `yaml:"signerType,omitempty"`
+	ForcePathStyle        bool   `yaml:"forcePathStyle,omitempty"`
+	MaxConnectionPoolSize int    `yaml:"maxConnectionPoolSize,omitempty"`
+}
+
+// LDAPConfig LDAP 配置
+type LDAPConfig struct {
+	// Servers LDAP 服务器列表，列表顺序即 Nexus 查询顺序
+	Servers []LDAPServer `yaml:"servers"`
+}
+
+// LDAPServer LDAP 服务器配置
+type LDAPServer struct {
+	Name         string           `yaml:"name"`
+	Connection   LDAPConnection   `yaml:"connection"`
+	UserMapping  LDAPUserMapping  `yaml:"userMapping"`
+	GroupMapping LDAPGroupMapping `yaml:"groupMapping,omitempty"`
+}
+
+// LDAPConnection LDAP 连接配置
+type LDAPConnection struct {
+	// Protocol ldap 或 ldaps
+	Protocol      string `yaml:"protocol"`
+	Host          string `yaml:"host"`
+	Port          int    `yaml:"port"`
+	SearchBase    string `yaml:"searchBase"`
+	UseTrustStore bool   `yaml:"useTrustStore,omitempty"`
+	// AuthScheme NONE / SIMPLE / DIGEST_MD5 / CRAM_MD5
+	AuthScheme   string `yaml:"authScheme"`
+	AuthRealm    string `yaml:"authRealm,omitempty"`
+	AuthUsername string `yaml:"authUsername,omitempty"`
+	// AuthPassword 绑定密码，建议使用 {env: ...} 或 {file: ...} 引用
+	AuthPassword                SecretRef `yaml:"authPassword,omitempty"`
+	ConnectionTimeoutSeconds    int       `yaml:"connectionTimeoutSeconds,omitempty"`
+	ConnectionRetryDelaySeconds int       `yaml:"connectionRetryDelaySeconds,omitempty"`
+	MaxIncidentsCount           int       `yaml:"maxIncidentsCount,omitempty"`
+}
+
+// LDAPUserMapping LDAP 用户映射配置
+type LDAPUserMapping struct {
+	BaseDn                string `yaml:"baseDn,omitempty"`
+	Subtree               bool   `yaml:"subtree,omitempty"`
+	ObjectClass           string `yaml:"objectClass"`
+	LdapFilter            string `yaml:"ldapFilter,omitempty"`
+	IDAttribute           string `yaml:"idAttribute"`
+	RealNameAttribute     string `yaml:"realNameAttribute"`
+	EmailAddressAttribute string `yaml:"emailAddressAttribute"`
+	PasswordAttribute     string `yaml:"passwordAttribute,omitempty"`
+}
+
+// LDAPGroupMapping LDAP 组映射配置
+type LDAPGroupMapping struct {
+	// Enabled 是否将 LDAP 组映射为角色
+	Enabled bool `yaml:"enabled"`
+	// Type static 或 dynamic
+	Type              string `yaml:"type,omitempty"`
+	BaseDn            string `yaml:"baseDn,omitempty"`
+	Subtree           bool   `yaml:"subtree,omitempty"`
+	ObjectClass       string `yaml:"objectClass,omitempty"`
+	IDAttribute       string `yaml:"idAttribute,omitempty"`
+	MemberAttribute   string `yaml:"memberAttribute,omitempty"`
+	MemberFormat      string `yaml:"memberFormat,omitempty"`
+	MemberOfAttribute string `yaml:"memberOfAttribute,omitempty"`
 }
+
+// LDAP 连接默认值（与 Nexus UI 默认值一致）
+const (
+	DefaultLDAPConnectionTimeout = 30
+	DefaultLDAPRetryDelay        = 300
+	DefaultLDAPMaxIncidents      = 3
+)
+
+// SecurityConfig 安全设置配置
+type SecurityConfig struct {
+	// Realms 启用的安全域 ID，列表顺序即认证顺序，例如 NexusAuthenticatingRealm、DockerToken、NpmToken
+	Realms     []string         `yaml:"realms,omitempty"`
+	Anonymous  *AnonymousConfig `yaml:"anonymous,omitempty"`
+	UserTokens *UserTokenConfig `yaml:"userTokens,omitempty"`
+}
+
+// AnonymousConfig 匿名访问配置
+type AnonymousConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	UserID    string `yaml:"userId,omitempty"`
+	RealmName string `yaml:"realmName,omitempty"`
+}
+
+// UserTokenConfig 用户令牌配置（Nexus Pro）
+type UserTokenConfig struct {
+	Enabled           bool `yaml:"enabled"`
+	ProtectContent    bool `yaml:"protectContent,omitempty"`
+	ExpirationEnabled bool `yaml:"expirationEnabled,omitempty"`
+	ExpirationDays    int  `yaml:"expirationDays,omitempty"`
+}
+
+// 匿名访问默认值
+const (
+	DefaultAnonymousUserID = "anonymous"
+	DefaultAnonymousRealm  = "NexusAuthorizingRealm"
+)
@@ -0,0 +1,664 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/alauda/nexus-cli/pkg/csel"
+	"github.com/alauda/nexus-cli/pkg/password"
+	"github.com/alauda/nexus-cli/pkg/rolegraph"
+	"github.com/alauda/nexus-cli/pkg/routing"
+)
+
+// ValidationError 配置校验错误，汇总所有发现的问题
+type ValidationError struct {
+	Problems []string
+}
+
+// Error 实现 error 接口
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid configuration:\n  - %s", strings.Join(e.Problems, "\n  - "))
+}
+
+// Validate 校验配置的一致性（不访问 Nexus）
+func (c *Config) Validate() error {
+	v := &validator{}
+	v.validateBlobStores(c.BlobStores)
+	v.validateCleanupPolicies(c.CleanupPolicies)
+	v.validateContentSelectors(c.ContentSelectors)
+	v.validateRoutingRules(c.RoutingRules)
+	v.validateLDAP(c.LDAP)
+	v.validateSecurity(c.Security)
+	v.validateUsers(c.Users)
+	v.validateRoles(c.Roles)
+	v.validatePrivileges(c.Privileges)
+	v.validateRepositories(c.Repositories)
+	v.validateDockerPorts(c.Repositories)
+	v.validateTasks(c.Tasks)
+	v.validateRepositoryPermissions(c.UserRepositoryPermissions)
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+// validator 收集校验问题
+type validator struct {
+	problems []string
+}
+
+func (v *validator) addf(format string, args ...interface{}) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+// validateRepositories 校验仓库配置
+func (v *validator) validateRepositories(repos []Repository) {
+	for _, repo := range repos {
+		if repo.Docker != nil && repo.Format != "docker" {
+			v.addf("repository %s: docker settings are only valid for docker repositories", repo.Name)
+		}
+		if repo.DockerProxy != nil && (repo.Format != "docker" || repo.Type != "proxy") {
+			v.addf("repository %s: dockerProxy settings are only valid for docker proxy repositories", repo.Name)
+		}
+		if repo.Docker != nil {
+			v.validateDocker(repo)
+		}
+		if repo.DockerProxy != nil {
+			v.validateDockerProxy(repo.Name, repo.DockerProxy)
+		}
+		v.validateProxyClient(repo)
+	}
+}
+
+// validateProxyClient 校验负缓存、HTTP 客户端和路由规则设置
+func (v *validator) validateProxyClient(repo Repository) {
+	if repo.RoutingRule != "" && repo.Type != "proxy" && repo.Type != "group" {
+		v.addf("repository %s: routingRule is only valid for proxy and group repositories", repo.Name)
+	}
+	if repo.Type != "proxy" {
+		if repo.NegativeCache != nil || repo.HTTPClient != nil {
+			v.addf("repository %s: negativeCache and httpClient are only valid for proxy repositories", repo.Name)
+		}
+		return
+	}
+	if nc := repo.NegativeCache; nc != nil && nc.TimeToLive != nil && *nc.TimeToLive < 0 {
+		v.addf("repository %s: negativeCache.timeToLive must not be negative", repo.Name)
+	}
+	if repo.HTTPClient == nil || repo.HTTPClient.Connection == nil {
+		return
+	}
+	conn := repo.HTTPClient.Connection
+	if conn.Timeout != nil && (*conn.Timeout < 1 || *conn.Timeout > 3600) {
+		v.addf("repository %s: httpClient.connection.timeout must be between 1 and 3600 seconds", repo.Name)
+	}
+	if conn.Retries != nil && (*conn.Retries < 0 || *conn.Retries > 10) {
+		v.addf("repository %s: httpClient.connection.retries must be between 0 and 10", repo.Name)
+	}
+}
+
+// validateDocker 校验 Docker 仓库设置
+func (v *validator) validateDocker(repo Repository) {
+	d := repo.Docker
+	if d.Subdomain != "" && d.SubdomainAddr != "" && d.Subdomain != d.SubdomainAddr {
+		v.addf("repository %s: subdomain and deprecated subdomainAddr are both set with different values", repo.Name)
+	}
+	if name := d.SubdomainName(); name != "" && !subdomainPattern.MatchString(name) {
+		v.addf("repository %s: invalid docker subdomain %q", repo.Name, name)
+	}
+	if d.LatestPolicy != nil && repo.Type != "hosted" {
+		v.addf("repository %s: latestPolicy is only valid for docker hosted repositories", repo.Name)
+	}
+	for _, port := range []int{d.HTTPPort, d.HTTPSPort} {
+		if port < 0 || port > 65535 {
+			v.addf("repository %s: docker connector port %d is out of range", repo.Name, port)
+		}
+	}
+}
+
+// subdomainPattern Nexus 要求子域名为单个 DNS 标签
+var subdomainPattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+
+// validateDockerProxy 校验 Docker 代理索引设置
+func (v *validator) validateDockerProxy(name string, p *DockerProxyConfig) {
+	switch p.IndexType {
+	case DockerIndexRegistry, DockerIndexHub:
+		if p.IndexURL != "" {
+			v.addf("repository %s: indexUrl is only used with indexType %s", name, DockerIndexCustom)
+		}
+	case DockerIndexCustom:
+		if p.IndexURL == "" {
+			v.addf("repository %s: indexUrl is required when indexType is %s", name, DockerIndexCustom)
+		}
+	default:
+		v.addf("repository %s: invalid dockerProxy.indexType %q (expected %s, %s or %s)",
+			name, p.IndexType, DockerIndexRegistry, DockerIndexHub, DockerIndexCustom)
+	}
+	if len(p.ForeignLayerURLWhitelist) > 0 && !p.CacheForeignLayers {
+		v.addf("repository %s: foreignLayerUrlWhitelist requires cacheForeignLayers to be enabled", name)
+	}
+	for _, pattern := range p.ForeignLayerURLWhitelist {
+		if _, err := regexp.Compile(pattern); err != nil {
+			v.addf("repository %s: invalid foreignLayerUrlWhitelist pattern %q: %v", name, pattern, err)
+		}
+	}
+}
+
+// validateDockerPorts 检查所有 Docker 仓库的连接器端口是否冲突
+func (v *validator) validateDockerPorts(repos []Repository) {
+	owners := make(map[int]string)
+	for _, repo := range repos {
+		if repo.Docker == nil {
+			continue
+		}
+		for _, port := range []int{repo.Docker.HTTPPort, repo.Docker.HTTPSPort} {
+			if port == 0 {
+				continue
+			}
+			if owner, ok := owners[port]; ok {
+				if owner == repo.Name {
+					v.addf("repository %s: httpPort and httpsPort must differ (both %d)", repo.Name, port)
+				} else {
+					v.addf("repository %s: docker connector port %d is already used by repository %s", repo.Name, port, owner)
+				}
+				continue
+			}
+			owners[port] = repo.Name
+		}
+	}
+}
+
+// validateBlobStores 校验 blob store 配置
+func (v *validator) validateBlobStores(stores []BlobStore) {
+	seen := make(map[string]bool)
+	for _, store := range stores {
+		if store.Name == "" {
+			v.addf("blob store: name is required")
+			continue
+		}
+		if seen[store.Name] {
+			v.addf("blob store %s: defined more than once", store.Name)
+		}
+		seen[store.Name] = true
+
+		switch strings.ToLower(store.Type) {
+		case BlobStoreTypeFile:
+			if store.S3 != nil {
+				v.addf("blob store %s: s3 settings are only valid for s3 blob stores", store.Name)
+			}
+		case BlobStoreTypeS3:
+			if store.S3 == nil || store.S3.Bucket == "" || store.S3.Region == "" {
+				v.addf("blob store %s: s3.bucket and s3.region are required for s3 blob stores", store.Name)
+			}
+			if store.Path != "" {
+				v.addf("blob store %s: path is only valid for file blob stores", store.Name)
+			}
+		default:
+			v.addf("blob store %s: invalid type %q (expected %s or %s)", store.Name, store.Type, BlobStoreTypeFile, BlobStoreTypeS3)
+		}
+
+		if q := store.SoftQuota; q != nil {
+			if q.Type != "spaceRemainingQuota" && q.Type != "spaceUsedQuota" {
+				v.addf("blob store %s: invalid softQuota.type %q (expected spaceRemainingQuota or spaceUsedQuota)", store.Name, q.Type)
+			}
+			if q.Limit <= 0 {
+				v.addf("blob store %s: softQuota.limit must be positive", store.Name)
+			}
+		}
+	}
+}
+
+// validateCleanupPolicies 校验清理策略配置
+func (v *validator) validateCleanupPolicies(policies []CleanupPolicy) {
+	seen := make(map[string]bool)
+	for _, p := range policies {
+		if p.Name == "" {
+			v.addf("cleanup policy: name is required")
+			continue
+		}
+		if seen[p.Name] {
+			v.addf("cleanup policy %s: defined more than once", p.Name)
+		}
+		seen[p.Name] = true
+
+		if p.Format == "" {
+			v.addf("cleanup policy %s: format is required (use \"*\" for all formats)", p.Name)
+		}
+
+		c := p.Criteria
+		if c.LastBlobUpdated == nil && c.LastDownloaded == nil && c.ReleaseType == "" && c.AssetRegex == "" && c.Retain == 0 {
+			v.addf("cleanup policy %s: at least one criterion is required", p.Name)
+		}
+		if c.LastBlobUpdated != nil && *c.LastBlobUpdated < 1 {
+			v.addf("cleanup policy %s: criteria.lastBlobUpdated must be at least 1 day", p.Name)
+		}
+		if c.LastDownloaded != nil && *c.LastDownloaded < 1 {
+			v.addf("cleanup policy %s: criteria.lastDownloaded must be at least 1 day", p.Name)
+		}
+		if c.ReleaseType != "" && c.ReleaseType != "RELEASES" && c.ReleaseType != "PRERELEASES" {
+			v.addf("cleanup policy %s: invalid criteria.releaseType %q (expected RELEASES or PRERELEASES)", p.Name, c.ReleaseType)
+		}
+		if c.AssetRegex != "" {
+			if _, err := regexp.Compile(c.AssetRegex); err != nil {
+				v.addf("cleanup policy %s: invalid criteria.assetRegex: %v", p.Name, err)
+			}
+		}
+		if c.Retain < 0 {
+			v.addf("cleanup policy %s: criteria.retain must not be negative", p.Name)
+		}
+		if c.Retain > 0 && p.Format != "maven2" && p.Format != "docker" {
+			v.addf("cleanup policy %s: criteria.retain is only supported for maven2 and docker formats", p.Name)
+		}
+		if c.SortBy != "" && c.SortBy != "version" && c.SortBy != "date" {
+			v.addf("cleanup policy %s: invalid criteria.sortBy %q (expected version or date)", p.Name, c.SortBy)
+		}
+	}
+}
+
+// validateContentSelectors 校验内容选择器及其 CSEL 表达式
+func (v *validator) validateContentSelectors(selectors []ContentSelector) {
+	seen := make(map[string]bool)
+	for _, sel := range selectors {
+		if sel.Name == "" {
+			v.addf("content selector: name is required")
+			continue
+		}
+		if seen[sel.Name] {
+			v.addf("content selector %s: defined more than once", sel.Name)
+		}
+		seen[sel.Name] = true
+
+		if err := csel.Validate(sel.Expression); err != nil {
+			v.addf("content selector %s: %v", sel.Name, err)
+		}
+	}
+}
+
+// privilegeActions 各权限类型允许的操作
+var privilegeActions = map[string][]string{
+	"repository-view":             {"BROWSE", "READ", "EDIT", "ADD", "DELETE", "ALL"},
+	"repository-admin":            {"BROWSE", "READ", "EDIT", "ADD", "DELETE", "ALL"},
+	"repository-content-selector": {"BROWSE", "READ", "EDIT", "ADD", "DELETE", "ALL"},
+	"script":                      {"BROWSE", "READ", "EDIT", "ADD", "DELETE", "RUN", "ALL"},
+	"application":                 {"BROWSE", "READ", "EDIT", "ADD", "DELETE", "ASSOCIATE", "DISASSOCIATE", "ALL"},
+}
+
+// validatePrivileges 校验权限配置，每种类型只允许并要求其对应的字段
+func (v *validator) validatePrivileges(privileges []Privilege) {
+	seen := make(map[string]bool)
+	for _, priv := range privileges {
+		if priv.Name == "" {
+			v.addf("privilege: name is required")
+			continue
+		}
+		if seen[priv.Name] {
+			v.addf("privilege %s: defined more than once", priv.Name)
+		}
+		seen[priv.Name] = true
+
+		// 每种类型需要的字段
+		var required, forbidden []string
+		switch priv.Type {
+		case "repository-view", "repository-admin":
+			required = []string{"format", "repository", "actions"}
+			forbidden = []string{"pattern", "domain", "contentSelector", "scriptName"}
+		case "repository-content-selector":
+			required = []string{"format", "repository", "actions", "contentSelector"}
+			forbidden = []string{"pattern", "domain", "scriptName"}
+		case "script":
+			required = []string{"scriptName", "actions"}
+			forbidden = []string{"format", "repository", "pattern", "domain", "contentSelector"}
+		case "application":
+			required = []string{"domain", "actions"}
+			forbidden = []string{"format", "repository", "pattern", "contentSelector", "scriptName"}
+		case "wildcard":
+			required = []string{"pattern"}
+			forbidden = []string{"format", "repository", "actions", "domain", "contentSelector", "scriptName"}
+		default:
+			v.addf("privilege %s: invalid type %q (expected repository-view, repository-admin, "+
+				"repository-content-selector, script, application or wildcard)", priv.Name, priv.Type)
+			continue
+		}
+
+		fields := privilegeFields(priv)
+		for _, field := range required {
+			if !fields[field] {
+				v.addf("privilege %s: %s is required for %s privileges", priv.Name, field, priv.Type)
+			}
+		}
+		for _, field := range forbidden {
+			if fields[field] {
+				v.addf("privilege %s: %s is not valid for %s privileges", priv.Name, field, priv.Type)
+			}
+		}
+
+		if allowed, ok := privilegeActions[priv.Type]; ok {
+			for _, action := range priv.Actions {
+				if !containsFold(allowed, action) {
+					v.addf("privilege %s: invalid action %q for %s privileges (expected one of %s)",
+						priv.Name, action, priv.Type, strings.Join(allowed, ", "))
+				}
+			}
+		}
+	}
+}
+
+// privilegeFields 返回权限配置中已设置的字段
+func privilegeFields(priv Privilege) map[string]bool {
+	return map[string]bool{
+		"format":          priv.Format != "",
+		"repository":      priv.Repository != "",
+		"actions":         len(priv.Actions) > 0,
+		"pattern":         priv.Pattern != "",
+		"domain":          priv.Domain != "",
+		"contentSelector": priv.ContentSelector != "",
+		"scriptName":      priv.ScriptName != "",
+	}
+}
+
+// containsFold 忽略大小写检查切片是否包含指定值
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRoutingRules 校验路由规则配置
+func (v *validator) validateRoutingRules(rules []RoutingRule) {
+	seen := make(map[string]bool)
+	for _, rule := range rules {
+		if rule.Name == "" {
+			v.addf("routing rule: name is required")
+			continue
+		}
+		if seen[rule.Name] {
+			v.addf("routing rule %s: defined more than once", rule.Name)
+		}
+		seen[rule.Name] = true
+
+		// Nexus 和 routing.Evaluate 都不区分模式的大小写
+		if mode := strings.ToUpper(rule.Mode); mode != routing.ModeAllow && mode != routing.ModeBlock {
+			v.addf("routing rule %s: invalid mode %q (expected %s or %s)", rule.Name, rule.Mode, routing.ModeAllow, routing.ModeBlock)
+		}
+		if len(rule.Matchers) == 0 {
+			v.addf("routing rule %s: at least one matcher is required", rule.Name)
+		}
+		for _, matcher := range rule.Matchers {
+			if _, err := routing.Compile(matcher); err != nil {
+				v.addf("routing rule %s: %v", rule.Name, err)
+			}
+		}
+	}
+}
+
+// validateTasks 校验计划任务配置
+func (v *validator) validateTasks(tasks []Task) {
+	seen := make(map[string]bool)
+	for _, task := range tasks {
+		if task.Name == "" {
+			v.addf("task: name is required")
+			continue
+		}
+		if seen[task.Name] {
+			v.addf("task %s: defined more than once", task.Name)
+		}
+		seen[task.Name] = true
+
+		if task.Type == "" {
+			v.addf("task %s: type is required", task.Name)
+		}
+		if c := task.NotificationCondition; c != "" && c != "FAILURE" && c != "SUCCESS_FAILURE" {
+			v.addf("task %s: invalid notificationCondition %q (expected FAILURE or SUCCESS_FAILURE)", task.Name, c)
+		}
+		v.validateTaskSchedule(task.Name, task.Schedule)
+	}
+}
+
+// validateTaskSchedule 校验任务执行计划，每种计划类型只允许使用对应的字段
+func (v *validator) validateTaskSchedule(name string, s TaskSchedule) {
+	typ := scheduleType(s)
+	maxDay := 0
+	switch typ {
+	case TaskScheduleManual, TaskScheduleCron:
+		if s.Start != "" {
+			v.addf("task %s: schedule.start is not used with a %s schedule", name, typ)
+		}
+	case TaskScheduleOnce, TaskScheduleHourly, TaskScheduleDaily, TaskScheduleWeekly, TaskScheduleMonthly:
+		if s.Start == "" {
+			v.addf("task %s: schedule.start is required for a %s schedule", name, typ)
+		} else if _, err := time.Parse(time.RFC3339, s.Start); err != nil {
+			v.addf("task %s: invalid schedule.start %q (expected RFC 3339, e.g. 2024-01-01T02:00:00+08:00)", name, s.Start)
+		}
+		if typ == TaskScheduleWeekly {
+			maxDay = 7
+		} else if typ == TaskScheduleMonthly {
+			maxDay = 31
+		}
+	default:
+		v.addf("task %s: invalid schedule.type %q (expected manual, once, hourly, daily, weekly, monthly or cron)", name, s.Type)
+		return
+	}
+
+	if maxDay == 0 && len(s.Days) > 0 {
+		v.addf("task %s: schedule.days is only valid for weekly and monthly schedules", name)
+	}
+	if maxDay > 0 && len(s.Days) == 0 {
+		v.addf("task %s: schedule.days is required for a %s schedule", name, typ)
+	}
+	for _, day := range s.Days {
+		if maxDay > 0 && (day < 1 || day > maxDay) {
+			v.addf("task %s: schedule.days value %d is out of range 1-%d", name, day, maxDay)
+		}
+	}
+
+	if typ == TaskScheduleCron && s.Cron == "" {
+		v.addf("task %s: schedule.cron is required for a cron schedule", name)
+	}
+	if typ != TaskScheduleCron && s.Cron != "" {
+		v.addf("task %s: schedule.cron is only valid for cron schedules", name)
+	}
+}
+
+// scheduleType 返回执行计划类型，未设置时为 manual
+func scheduleType(s TaskSchedule) string {
+	if s.Type == "" {
+		return TaskScheduleManual
+	}
+	return s.Type
+}
+
+// validateLDAP 校验 LDAP 服务器配置
+func (v *validator) validateLDAP(ldap *LDAPConfig) {
+	if ldap == nil {
+		return
+	}
+	seen := make(map[string]bool)
+	for _, server := range ldap.Servers {
+		if server.Name == "" {
+			v.addf("ldap server: name is required")
+			continue
+		}
+		if seen[server.Name] {
+			v.addf("ldap server %s: defined more than once", server.Name)
+		}
+		seen[server.Name] = true
+
+		conn := server.Connection
+		if conn.Protocol != "ldap" && conn.Protocol != "ldaps" {
+			v.addf("ldap server %s: invalid connection.protocol %q (expected ldap or ldaps)", server.Name, conn.Protocol)
+		}
+		if conn.Host == "" || conn.Port <= 0 || conn.SearchBase == "" {
+			v.addf("ldap server %s: connection.host, connection.port and connection.searchBase are required", server.Name)
+		}
+		switch conn.AuthScheme {
+		case "NONE":
+			if conn.AuthUsername != "" || conn.AuthPassword.IsSet() {
+				v.addf("ldap server %s: authUsername and authPassword are not used with authScheme NONE", server.Name)
+			}
+		case "SIMPLE", "DIGEST_MD5", "CRAM_MD5":
+			if conn.AuthUsername == "" || !conn.AuthPassword.IsSet() {
+				v.addf("ldap server %s: authUsername and authPassword are required with authScheme %s", server.Name, conn.AuthScheme)
+			}
+		default:
+			v.addf("ldap server %s: invalid connection.authScheme %q (expected NONE, SIMPLE, DIGEST_MD5 or CRAM_MD5)", server.Name, conn.AuthScheme)
+		}
+		if err := conn.AuthPassword.validate(); err != nil {
+			v.addf("ldap server %s: authPassword: %v", server.Name, err)
+		}
+
+		um := server.UserMapping
+		if um.ObjectClass == "" || um.IDAttribute == "" || um.RealNameAttribute == "" || um.EmailAddressAttribute == "" {
+			v.addf("ldap server %s: userMapping.objectClass, idAttribute, realNameAttribute and emailAddressAttribute are required", server.Name)
+		}
+
+		gm := server.GroupMapping
+		if gm.Enabled {
+			switch gm.Type {
+			case "static":
+				if gm.ObjectClass == "" || gm.IDAttribute == "" || gm.MemberAttribute == "" || gm.MemberFormat == "" {
+					v.addf("ldap server %s: static groupMapping requires objectClass, idAttribute, memberAttribute and memberFormat", server.Name)
+				}
+			case "dynamic":
+				if gm.MemberOfAttribute == "" {
+					v.addf("ldap server %s: dynamic groupMapping requires memberOfAttribute", server.Name)
+				}
+			default:
+				v.addf("ldap server %s: invalid groupMapping.type %q (expected static or dynamic)", server.Name, gm.Type)
+			}
+		}
+	}
+}
+
+// validateUsers 校验用户配置
+func (v *validator) validateUsers(users []User) {
+	seen := make(map[string]bool)
+	for _, user := range users {
+		if seen[user.ID] {
+			v.addf("user %s: declared more than once", user.ID)
+		}
+		seen[user.ID] = true
+
+		switch user.Status {
+		case "", UserStatusActive, UserStatusDisabled, UserStatusLocked, UserStatusChangePassword:
+		default:
+			v.addf("user %s: invalid status %q (must be active, disabled, locked or changepassword)", user.ID, user.Status)
+		}
+		if user.IsExternal() && user.Status != "" {
+			v.addf("user %s: status cannot be managed for %s users", user.ID, user.Source)
+		}
+		if user.IsExternal() && user.Password.IsSet() {
+			v.addf("user %s: password cannot be managed for %s users", user.ID, user.Source)
+		}
+		v.validatePassword(user)
+	}
+}
+
+// validateRoles 校验角色配置
+//
+// 子角色可能已存在于 Nexus，因此这里只检查配置内部的环，引用不存在的角色在 apply 时检查。
+func (v *validator) validateRoles(roles []Role) {
+	seen := make(map[string]bool)
+	graph := rolegraph.New()
+	for _, role := range roles {
+		if seen[role.ID] {
+			v.addf("role %s: declared more than once", role.ID)
+		}
+		seen[role.ID] = true
+		if role.Source != "" && role.Source != SourceDefault && role.Source != SourceLDAP {
+			v.addf("role %s: invalid source %q (expected %s or %s)", role.ID, role.Source, SourceDefault, SourceLDAP)
+		}
+		graph.AddConfigRole(role.ID, role.Roles)
+	}
+	for _, cycle := range graph.Cycles() {
+		v.addf("roles: cycle detected: %s", rolegraph.FormatCycle(cycle))
+	}
+}
+
+// validateSecurity 校验安全设置
+func (v *validator) validateSecurity(sec *SecurityConfig) {
+	if sec == nil {
+		return
+	}
+	seen := make(map[string]bool)
+	for _, realm := range sec.Realms {
+		if seen[realm] {
+			v.addf("security.realms: realm %s is listed more than once", realm)
+		}
+		seen[realm] = true
+	}
+	if t := sec.UserTokens; t != nil && t.ExpirationEnabled && (t.ExpirationDays < 1 || t.ExpirationDays > 999) {
+		v.addf("security.userTokens: expirationDays must be between 1 and 999 when expiration is enabled")
+	}
+}
+
+// validatePassword 校验用户密码配置
+func (v *validator) validatePassword(user User) {
+	if err := user.Password.SecretRef.validate(); err != nil {
+		v.addf("user %s: password: %v", user.ID, err)
+	}
+	gen := user.Password.Generate
+	if gen == nil {
+		return
+	}
+	if user.Password.SecretRef.IsSet() {
+		v.addf("user %s: password.generate cannot be combined with value, env or file", user.ID)
+	}
+	if gen.Length != 0 && (gen.Length < password.MinLength || gen.Length > password.MaxLength) {
+		v.addf("user %s: password.generate.length must be between %d and %d", user.ID, password.MinLength, password.MaxLength)
+	}
+	switch gen.SinkName() {
+	case PasswordSinkStdout, PasswordSinkTemplate:
+		if gen.File != "" {
+			v.addf("user %s: password.generate.file is only valid with sink file", user.ID)
+		}
+	case PasswordSinkFile:
+		if gen.File == "" {
+			v.addf("user %s: password.generate.file is required with sink file", user.ID)
+		}
+	default:
+		v.addf("user %s: invalid password.generate.sink %q (must be stdout, file or template)", user.ID, gen.Sink)
+	}
+}
+
+// validateRepositoryPermissions 校验仓库权限授予
+func (v *validator) validateRepositoryPermissions(perms []UserRepositoryPermission) {
+	for i, perm := range perms {
+		target := fmt.Sprintf("userRepositoryPermissions[%d]", i)
+
+		grantees := 0
+		for _, id := range []string{perm.UserID, perm.RoleID, perm.Group} {
+			if id != "" {
+				grantees++
+			}
+		}
+		if grantees != 1 {
+			v.addf("%s: exactly one of userId, roleId or group must be set", target)
+		}
+
+		if perm.Repository == "" {
+			v.addf("%s: repository is required", target)
+		} else if _, err := path.Match(perm.Repository, ""); err != nil {
+			v.addf("%s: invalid repository pattern %q: %v", target, perm.Repository, err)
+		}
+
+		if len(perm.Privileges) == 0 && len(perm.AdminPrivileges) == 0 {
+			v.addf("%s: at least one of privileges or adminPrivileges is required", target)
+		}
+		for _, action := range perm.Privileges {
+			if !containsFold(RepositoryPermissionActions, action) {
+				v.addf("%s: invalid privilege %q (must be one of %s)", target, action, strings.Join(RepositoryPermissionActions, ", "))
+			}
+		}
+		for _, action := range perm.AdminPrivileges {
+			if !containsFold(RepositoryPermissionActions, action) {
+				v.addf("%s: invalid adminPrivilege %q (must be one of %s)", target, action, strings.Join(RepositoryPermissionActions, ", "))
+			}
+		}
+	}
+}
@@ -0,0 +1,441 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+// validateCase Validate 的测试用例，errContains 为空表示配置有效
+type validateCase struct {
+	name        string
+	cfg         Config
+	errContains string
+}
+
+// runValidateCases 逐个校验配置，检查是否通过或错误信息是否包含 errContains
+func runValidateCases(t *testing.T, tests []validateCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+
+			if tt.errContains == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.errContains)
+			}
+		})
+	}
+}
+
+func TestValidateRepositories(t *testing.T) {
+	boolPtr := func(b bool) *bool { return &b }
+	intPtr := func(i int) *int { return &i }
+	repos := func(repos ...Repository) Config { return Config{Repositories: repos} }
+	connection := func(timeout, retries *int) *HTTPClientConfig {
+		return &HTTPClientConfig{Connection: &HTTPConnectionConfig{Timeout: timeout, Retries: retries}}
+	}
+
+	runValidateCases(t, []validateCase{
+		// Docker
+		{
+			name: "valid docker hosted and proxy",
+			cfg: repos(
+				Repository{Name: "docker-hosted", Format: "docker", Type: "hosted", Docker: &DockerConfig{HTTPPort: 8082, Subdomain: "hosted", LatestPolicy: boolPtr(true)}},
+				Repository{Name: "docker-proxy", Format: "docker", Type: "proxy", Docker: &DockerConfig{HTTPPort: 8083},
+					DockerProxy: &DockerProxyConfig{IndexType: DockerIndexHub, CacheForeignLayers: true, ForeignLayerURLWhitelist: []string{".*"}}},
+			),
+		},
+		{
+			name: "port collision across repositories",
+			cfg: repos(
+				Repository{Name: "a", Format: "docker", Type: "hosted", Docker: &DockerConfig{HTTPPort: 8082}},
+				Repository{Name: "b", Format: "docker", Type: "group", Docker: &DockerConfig{HTTPSPort: 8082}},
+			),
+			errContains: "port 8082 is already used by repository a",
+		},
+		{
+			name:        "http and https port equal",
+			cfg:         repos(Repository{Name: "a", Format: "docker", Type: "hosted", Docker: &DockerConfig{HTTPPort: 8082, HTTPSPort: 8082}}),
+			errContains: "httpPort and httpsPort must differ",
+		},
+		{
+			name:        "custom index without url",
+			cfg:         repos(Repository{Name: "p", Format: "docker", Type: "proxy", DockerProxy: &DockerProxyConfig{IndexType: DockerIndexCustom}}),
+			errContains: "indexUrl is required",
+		},
+		{
+			name:        "invalid index type",
+			cfg:         repos(Repository{Name: "p", Format: "docker", Type: "proxy", DockerProxy: &DockerProxyConfig{IndexType: "hub"}}),
+			errContains: "invalid dockerProxy.indexType",
+		},
+		{
+			name:        "latest policy on proxy",
+			cfg:         repos(Repository{Name: "p", Format: "docker", Type: "proxy", Docker: &DockerConfig{LatestPolicy: boolPtr(true)}}),
+			errContains: "latestPolicy is only valid",
+		},
+		{
+			name: "whitelist without caching foreign layers",
+			cfg: repos(Repository{Name: "p", Format: "docker", Type: "proxy",
+				DockerProxy: &DockerProxyConfig{IndexType: DockerIndexRegistry, ForeignLayerURLWhitelist: []string{".*"}}}),
+			errContains: "requires cacheForeignLayers",
+		},
+
+		// 负缓存和 HTTP 客户端
+		{
+			name: "proxy with boundary values",
+			cfg: repos(Repository{Name: "p", Format: "maven2", Type: "proxy", NegativeCache: &NegativeCacheConfig{TimeToLive: intPtr(0)},
+				HTTPClient: connection(intPtr(3600), intPtr(0))}),
+		},
+		{
+			name: "proxy without connection settings",
+			cfg:  repos(Repository{Name: "p", Format: "maven2", Type: "proxy", HTTPClient: &HTTPClientConfig{Blocked: true}}),
+		},
+		{
+			name:        "negative ttl",
+			cfg:         repos(Repository{Name: "p", Format: "maven2", Type: "proxy", NegativeCache: &NegativeCacheConfig{TimeToLive: intPtr(-1)}}),
+			errContains: "negativeCache.timeToLive must not be negative",
+		},
+		{
+			name:        "timeout too small",
+			cfg:         repos(Repository{Name: "p", Format: "maven2", Type: "proxy", HTTPClient: connection(intPtr(0), nil)}),
+			errContains: "timeout must be between 1 and 3600 seconds",
+		},
+		{
+			name:        "timeout too large",
+			cfg:         repos(Repository{Name: "p", Format: "maven2", Type: "proxy", HTTPClient: connection(intPtr(3601), nil)}),
+			errContains: "timeout must be between 1 and 3600 seconds",
+		},
+		{
+			name:        "too many retries",
+			cfg:         repos(Repository{Name: "p", Format: "maven2", Type: "proxy", HTTPClient: connection(nil, intPtr(11))}),
+			errContains: "retries must be between 0 and 10",
+		},
+		{
+			name:        "negative cache on group repository",
+			cfg:         repos(Repository{Name: "g", Format: "maven2", Type: "group", NegativeCache: &NegativeCacheConfig{}}),
+			errContains: "negativeCache and httpClient are only valid for proxy repositories",
+		},
+		{
+			name:        "http client on hosted repository",
+			cfg:         repos(Repository{Name: "h", Format: "maven2", Type: "hosted", HTTPClient: &HTTPClientConfig{}}),
+			errContains: "negativeCache and httpClient are only valid for proxy repositories",
+		},
+
+		// 路由规则
+		{
+			name: "routing rule on proxy and group",
+			cfg: repos(
+				Repository{Name: "maven-proxy", Format: "maven2", Type: "proxy", RoutingRule: "block-internal"},
+				Repository{Name: "maven-public", Format: "maven2", Type: "group", RoutingRule: "block-internal"},
+			),
+		},
+		{
+			name:        "routing rule on hosted repository",
+			cfg:         repos(Repository{Name: "maven-releases", Format: "maven2", Type: "hosted", RoutingRule: "block-internal"}),
+			errContains: "routingRule is only valid for proxy and group repositories",
+		},
+	})
+}
+
+func TestValidateBlobStores(t *testing.T) {
+	stores := func(stores ...BlobStore) Config { return Config{BlobStores: stores} }
+
+	runValidateCases(t, []validateCase{
+		{
+			name: "valid file store without path",
+			cfg:  stores(BlobStore{Name: "default", Type: "file"}),
+		},
+		{
+			name: "valid s3 store with soft quota",
+			cfg: stores(BlobStore{Name: "s3", Type: "S3", S3: &S3BlobStoreConfig{Region: "us-east-1", Bucket: "nexus"},
+				SoftQuota: &SoftQuotaConfig{Type: "spaceUsedQuota", Limit: 1024}}),
+		},
+		{
+			name:        "missing name",
+			cfg:         stores(BlobStore{Type: "file"}),
+			errContains: "name is required",
+		},
+		{
+			name:        "duplicate name",
+			cfg:         stores(BlobStore{Name: "b", Type: "file"}, BlobStore{Name: "b", Type: "file"}),
+			errContains: "defined more than once",
+		},
+		{
+			name:        "invalid type",
+			cfg:         stores(BlobStore{Name: "b", Type: "azure"}),
+			errContains: "invalid type",
+		},
+		{
+			name:        "s3 settings on file store",
+			cfg:         stores(BlobStore{Name: "b", Type: "file", S3: &S3BlobStoreConfig{Bucket: "nexus"}}),
+			errContains: "only valid for s3 blob stores",
+		},
+		{
+			name:        "s3 store without bucket",
+			cfg:         stores(BlobStore{Name: "b", Type: "s3", S3: &S3BlobStoreConfig{Region: "us-east-1"}}),
+			errContains: "s3.bucket and s3.region are required",
+		},
+		{
+			name:        "path on s3 store",
+			cfg:         stores(BlobStore{Name: "b", Type: "s3", Path: "/data", S3: &S3BlobStoreConfig{Region: "us-east-1", Bucket: "nexus"}}),
+			errContains: "path is only valid for file blob stores",
+		},
+		{
+			name:        "invalid soft quota type",
+			cfg:         stores(BlobStore{Name: "b", Type: "file", SoftQuota: &SoftQuotaConfig{Type: "count", Limit: 1}}),
+			errContains: "invalid softQuota.type",
+		},
+		{
+			name:        "non-positive soft quota limit",
+			cfg:         stores(BlobStore{Name: "b", Type: "file", SoftQuota: &SoftQuotaConfig{Type: "spaceRemainingQuota"}}),
+			errContains: "softQuota.limit must be positive",
+		},
+	})
+}
+
+func TestValidateCleanupPolicies(t *testing.T) {
+	days := func(d int) *int { return &d }
+	policy := func(p CleanupPolicy) Config { return Config{CleanupPolicies: []CleanupPolicy{p}} }
+
+	runValidateCases(t, []validateCase{
+		{
+			name: "valid maven policy with retain",
+			cfg:  policy(CleanupPolicy{Name: "p", Format: "maven2", Criteria: CleanupCriteria{LastDownloaded: days(30), Retain: 5}}),
+		},
+		{
+			name:        "no criteria",
+			cfg:         policy(CleanupPolicy{Name: "p", Format: "npm"}),
+			errContains: "at least one criterion",
+		},
+		{
+			name:        "retain on unsupported format",
+			cfg:         policy(CleanupPolicy{Name: "p", Format: "npm", Criteria: CleanupCriteria{Retain: 3}}),
+			errContains: "only supported for maven2 and docker",
+		},
+		{
+			name:        "invalid release type",
+			cfg:         policy(CleanupPolicy{Name: "p", Format: "*", Criteria: CleanupCriteria{ReleaseType: "SNAPSHOTS"}}),
+			errContains: "invalid criteria.releaseType",
+		},
+		{
+			name:        "invalid asset regex",
+			cfg:         policy(CleanupPolicy{Name: "p", Format: "raw", Criteria: CleanupCriteria{AssetRegex: "(["}}),
+			errContains: "invalid criteria.assetRegex",
+		},
+	})
+}
+
+func TestValidateRoutingRules(t *testing.T) {
+	rule := func(r RoutingRule) Config { return Config{RoutingRules: []RoutingRule{r}} }
+
+	runValidateCases(t, []validateCase{
+		{
+			name: "lowercase mode",
+			cfg:  rule(RoutingRule{Name: "block-internal", Mode: "block", Matchers: []string{"^/com/example/.*"}}),
+		},
+		{
+			name:        "invalid mode",
+			cfg:         rule(RoutingRule{Name: "r", Mode: "deny", Matchers: []string{".*"}}),
+			errContains: `invalid mode "deny"`,
+		},
+		{
+			name:        "no matchers",
+			cfg:         rule(RoutingRule{Name: "r", Mode: "ALLOW"}),
+			errContains: "at least one matcher is required",
+		},
+	})
+}
+
+func TestValidateTasks(t *testing.T) {
+	task := func(task Task) Config { return Config{Tasks: []Task{task}} }
+
+	runValidateCases(t, []validateCase{
+		{
+			name: "valid manual task",
+			cfg:  task(Task{Name: "compact", Type: "blobstore.compact", Properties: map[string]string{"blobstoreName": "default"}}),
+		},
+		{
+			name: "valid weekly task",
+			cfg: task(Task{Name: "rebuild", Type: "repository.rebuild-index",
+				Schedule: TaskSchedule{Type: TaskScheduleWeekly, Start: "2024-01-01T02:00:00+08:00", Days: []int{1, 7}}}),
+		},
+		{
+			name:        "missing start",
+			cfg:         task(Task{Name: "t", Type: "blobstore.compact", Schedule: TaskSchedule{Type: TaskScheduleDaily}}),
+			errContains: "schedule.start is required",
+		},
+		{
+			name: "day out of range",
+			cfg: task(Task{Name: "t", Type: "blobstore.compact",
+				Schedule: TaskSchedule{Type: TaskScheduleWeekly, Start: "2024-01-01T02:00:00Z", Days: []int{8}}}),
+			errContains: "out of range 1-7",
+		},
+		{
+			name:        "cron without expression",
+			cfg:         task(Task{Name: "t", Type: "blobstore.compact", Schedule: TaskSchedule{Type: TaskScheduleCron}}),
+			errContains: "schedule.cron is required",
+		},
+		{
+			name:        "invalid schedule type",
+			cfg:         task(Task{Name: "t", Type: "blobstore.compact", Schedule: TaskSchedule{Type: "nightly"}}),
+			errContains: "invalid schedule.type",
+		},
+	})
+}
+
+func TestValidateSecurity(t *testing.T) {
+	privilege := func(p Privilege) Config { return Config{Privileges: []Privilege{p}} }
+	users := func(users ...User) Config { return Config{Users: users} }
+	roles := func(roles ...Role) Config { return Config{Roles: roles} }
+	permission := func(p UserRepositoryPermission) Config {
+		return Config{UserRepositoryPermissions: []UserRepositoryPermission{p}}
+	}
+
+	runValidateCases(t, []validateCase{
+		// 权限
+		{
+			name: "valid repository-view privilege",
+			cfg:  privilege(Privilege{Name: "p", Type: "repository-view", Format: "maven2", Repository: "*", Actions: []string{"read", "BROWSE"}}),
+		},
+		{
+			name: "valid wildcard privilege",
+			cfg:  privilege(Privilege{Name: "p", Type: "wildcard", Pattern: "nexus:repository-view:*:*:read"}),
+		},
+		{
+			name: "valid application privilege",
+			cfg:  privilege(Privilege{Name: "p", Type: "application", Domain: "users", Actions: []string{"READ"}}),
+		},
+		{
+			name: "valid script privilege",
+			cfg:  privilege(Privilege{Name: "p", Type: "script", ScriptName: "cleanup", Actions: []string{"RUN"}}),
+		},
+		{
+			name:        "wildcard without pattern",
+			cfg:         privilege(Privilege{Name: "p", Type: "wildcard"}),
+			errContains: "pattern is required for wildcard privileges",
+		},
+		{
+			name:        "application without domain",
+			cfg:         privilege(Privilege{Name: "p", Type: "application", Actions: []string{"READ"}}),
+			errContains: "domain is required",
+		},
+		{
+			name:        "content selector missing",
+			cfg:         privilege(Privilege{Name: "p", Type: "repository-content-selector", Format: "maven2", Repository: "*", Actions: []string{"READ"}}),
+			errContains: "contentSelector is required",
+		},
+		{
+			name:        "repository on wildcard",
+			cfg:         privilege(Privilege{Name: "p", Type: "wildcard", Pattern: "nexus:*", Repository: "x"}),
+			errContains: "repository is not valid for wildcard privileges",
+		},
+		{
+			name:        "run action on repository-view",
+			cfg:         privilege(Privilege{Name: "p", Type: "repository-view", Format: "npm", Repository: "*", Actions: []string{"RUN"}}),
+			errContains: "invalid action \"RUN\"",
+		},
+		{
+			name:        "unknown privilege type",
+			cfg:         privilege(Privilege{Name: "p", Type: "repository"}),
+			errContains: "invalid type",
+		},
+
+		// 用户
+		{
+			name: "valid user statuses",
+			cfg:  users(User{ID: "a", Status: "active"}, User{ID: "b", Status: "disabled"}, User{ID: "c", Status: "locked"}, User{ID: "d"}),
+		},
+		{
+			name:        "invalid user status",
+			cfg:         users(User{ID: "a", Status: "enabled"}),
+			errContains: "invalid status \"enabled\"",
+		},
+		{
+			name:        "duplicate user",
+			cfg:         users(User{ID: "a"}, User{ID: "a"}),
+			errContains: "declared more than once",
+		},
+		{
+			name:        "generate combined with value",
+			cfg:         users(User{ID: "a", Password: PasswordConfig{SecretRef: SecretRef{Value: "x"}, Generate: &PasswordGenerateConfig{}}}),
+			errContains: "cannot be combined",
+		},
+		{
+			name:        "file sink without file",
+			cfg:         users(User{ID: "a", Password: PasswordConfig{Generate: &PasswordGenerateConfig{Sink: PasswordSinkFile}}}),
+			errContains: "password.generate.file is required",
+		},
+		{
+			name:        "generated password too short",
+			cfg:         users(User{ID: "a", Password: PasswordConfig{Generate: &PasswordGenerateConfig{Length: 8}}}),
+			errContains: "length must be between",
+		},
+		{
+			name:        "status on LDAP user",
+			cfg:         users(User{ID: "a", Source: SourceLDAP, Status: "locked"}),
+			errContains: "status cannot be managed for LDAP users",
+		},
+
+		// 角色
+		{
+			name: "nested roles referencing server roles",
+			cfg:  roles(Role{ID: "devs", Roles: []string{"readers", "nx-anonymous"}}, Role{ID: "readers"}),
+		},
+		{
+			name:        "cycle between declared roles",
+			cfg:         roles(Role{ID: "a", Roles: []string{"b"}}, Role{ID: "b", Roles: []string{"a"}}),
+			errContains: "cycle detected: a -> b -> a",
+		},
+		{
+			name:        "role contains itself",
+			cfg:         roles(Role{ID: "a", Roles: []string{"a"}}),
+			errContains: "cycle detected: a -> a",
+		},
+		{
+			name:        "duplicate role",
+			cfg:         roles(Role{ID: "a"}, Role{ID: "a"}),
+			errContains: "role a: declared more than once",
+		},
+
+		// 仓库权限
+		{
+			name: "user with view and admin actions",
+			cfg: permission(UserRepositoryPermission{UserID: "u", Repository: "team1-*",
+				Privileges: []string{"read", "BROWSE"}, AdminPrivileges: []string{"ALL"}}),
+		},
+		{
+			name: "group grant on all repositories",
+			cfg:  permission(UserRepositoryPermission{Group: "developers", Repository: "*", Privileges: []string{"READ"}}),
+		},
+		{
+			name:        "no grantee",
+			cfg:         permission(UserRepositoryPermission{Repository: "r", Privileges: []string{"READ"}}),
+			errContains: "exactly one of userId, roleId or group",
+		},
+		{
+			name:        "two grantees",
+			cfg:         permission(UserRepositoryPermission{UserID: "u", RoleID: "r", Repository: "r", Privileges: []string{"READ"}}),
+			errContains: "exactly one of userId, roleId or group",
+		},
+		{
+			name:        "no actions",
+			cfg:         permission(UserRepositoryPermission{UserID: "u", Repository: "r"}),
+			errContains: "at least one of privileges or adminPrivileges",
+		},
+		{
+			name:        "invalid admin action",
+			cfg:         permission(UserRepositoryPermission{RoleID: "r", Repository: "r", AdminPrivileges: []string{"RUN"}}),
+			errContains: "invalid adminPrivilege \"RUN\"",
+		},
+		{
+			name:        "malformed pattern",
+			cfg:         permission(UserRepositoryPermission{UserID: "u", Repository: "team[", Privileges: []string{"READ"}}),
+			errContains: "invalid repository pattern",
+		},
+	})
+}
@@ -0,0 +1,242 @@
+// Package csel provides offline syntax checking for Nexus content selector (CSEL) expressions.
+package csel
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// tokenKind 词法单元类型
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenOperator
+	tokenAnd
+	tokenOr
+	tokenLParen
+	tokenRParen
+)
+
+// token 词法单元
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// SyntaxError CSEL 语法错误
+type SyntaxError struct {
+	Pos     int
+	Message string
+}
+
+// Error 实现 error 接口
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("csel syntax error at position %d: %s", e.Pos+1, e.Message)
+}
+
+// Validate 检查 CSEL 表达式语法
+//
+// 支持的语法：
+//   - 标识符: format, path, coordinate.<name>
+//   - 比较: ==, !=, =~（正则匹配）, =^（前缀匹配）
+//   - 逻辑: and / &&, or / ||, 括号
+//   - 字符串: 单引号或双引号
+func Validate(expr string) error {
+	if strings.TrimSpace(expr) == "" {
+		return &SyntaxError{Pos: 0, Message: "expression is empty"}
+	}
+
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return err
+	}
+
+	p := &parser{tokens: tokens}
+	if err := p.parseOr(); err != nil {
+		return err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return &SyntaxError{Pos: tok.pos, Message: fmt.Sprintf("unexpected %q", tok.value)}
+	}
+	return nil
+}
+
+// tokenize 将表达式拆分为词法单元
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(expr) {
+		ch := rune(expr[i])
+		switch {
+		case unicode.IsSpace(ch):
+			i++
+		case ch == '(':
+			tokens = append(tokens, token{kind: tokenLParen, value: "(", pos: i})
+			i++
+		case ch == ')':
+			tokens = append(tokens, token{kind: tokenRParen, value: ")", pos: i})
+			i++
+		case ch == '\'' || ch == '"':
+			start := i
+			var sb strings.Builder
+			i++
+			closed := false
+			for i < len(expr) {
+				if expr[i] == '\\' && i+1 < len(expr) {
+					sb.WriteByte(expr[i+1])
+					i += 2
+					continue
+				}
+				if rune(expr[i]) == ch {
+					closed = true
+					i++
+					break
+				}
+				sb.WriteByte(expr[i])
+				i++
+			}
+			if !closed {
+				return nil, &SyntaxError{Pos: start, Message: "unterminated string literal"}
+			}
+			tokens = append(tokens, token{kind: tokenString, value: sb.String(), pos: start})
+		case strings.HasPrefix(expr[i:], "&&"):
+			tokens = append(tokens, token{kind: tokenAnd, value: "&&", pos: i})
+			i += 2
+		case strings.HasPrefix(expr[i:], "||"):
+			tokens = append(tokens, token{kind: tokenOr, value: "||", pos: i})
+			i += 2
+		case ch == '=' || ch == '!':
+			if i+1 < len(expr) {
+				op := expr[i : i+2]
+				if op == "==" || op == "!=" || op == "=~" || op == "=^" {
+					tokens = append(tokens, token{kind: tokenOperator, value: op, pos: i})
+					i += 2
+					continue
+				}
+			}
+			return nil, &SyntaxError{Pos: i, Message: fmt.Sprintf("unknown operator starting with %q", string(ch))}
+		case unicode.IsLetter(ch) || ch == '_':
+			start := i
+			for i < len(expr) && (unicode.IsLetter(rune(expr[i])) || unicode.IsDigit(rune(expr[i])) || expr[i] == '_' || expr[i] == '.') {
+				i++
+			}
+			word := expr[start:i]
+			switch word {
+			case "and":
+				tokens = append(tokens, token{kind: tokenAnd, value: word, pos: start})
+			case "or":
+				tokens = append(tokens, token{kind: tokenOr, value: word, pos: start})
+			default:
+				tokens = append(tokens, token{kind: tokenIdent, value: word, pos: start})
+			}
+		default:
+			return nil, &SyntaxError{Pos: i, Message: fmt.Sprintf("unexpected character %q", string(ch))}
+		}
+	}
+	tokens = append(tokens, token{kind: tokenEOF, value: "end of expression", pos: len(expr)})
+	return tokens, nil
+}
+
+// parser 递归下降语法分析器
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// parseOr or 表达式
+func (p *parser) parseOr() error {
+	if err := p.parseAnd(); err != nil {
+		return err
+	}
+	for p.peek().kind == tokenOr {
+		p.next()
+		if err := p.parseAnd(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseAnd and 表达式
+func (p *parser) parseAnd() error {
+	if err := p.parsePrimary(); err != nil {
+		return err
+	}
+	for p.peek().kind == tokenAnd {
+		p.next()
+		if err := p.parsePrimary(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parsePrimary 括号表达式或比较
+func (p *parser) parsePrimary() error {
+	tok := p.next()
+	switch tok.kind {
+	case tokenLParen:
+		if err := p.parseOr(); err != nil {
+			return err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return &SyntaxError{Pos: closing.pos, Message: fmt.Sprintf("expected ) but found %q", closing.value)}
+		}
+		return nil
+	case tokenIdent:
+		return p.parseComparison(tok)
+	default:
+		return &SyntaxError{Pos: tok.pos, Message: fmt.Sprintf("expected identifier or ( but found %q", tok.value)}
+	}
+}
+
+// parseComparison 比较表达式: identifier operator string
+func (p *parser) parseComparison(ident token) error {
+	if !validIdentifier(ident.value) {
+		return &SyntaxError{Pos: ident.pos, Message: fmt.Sprintf("unknown identifier %q (expected format, path or coordinate.<name>)", ident.value)}
+	}
+
+	op := p.next()
+	if op.kind != tokenOperator {
+		return &SyntaxError{Pos: op.pos, Message: fmt.Sprintf("expected operator after %s but found %q", ident.value, op.value)}
+	}
+
+	value := p.next()
+	if value.kind != tokenString {
+		return &SyntaxError{Pos: value.pos, Message: fmt.Sprintf("expected string literal after %s but found %q", op.value, value.value)}
+	}
+
+	if op.value == "=~" {
+		if _, err := regexp.Compile(value.value); err != nil {
+			return &SyntaxError{Pos: value.pos, Message: fmt.Sprintf("invalid regular expression: %v", err)}
+		}
+	}
+	return nil
+}
+
+// validIdentifier 检查 CSEL 支持的标识符
+func validIdentifier(name string) bool {
+	if name == "format" || name == "path" {
+		return true
+	}
+	attr, ok := strings.CutPrefix(name, "coordinate.")
+	return ok && attr != "" && !strings.Contains(attr, ".")
+}
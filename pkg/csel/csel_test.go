@@ -0,0 +1,41 @@
+package csel
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		expr        string
+		errContains string
+	}{
+		{name: "path prefix", expr: `format == "maven2" and path =^ "/com/example/team1/"`},
+		{name: "regex with parentheses", expr: `(format == 'npm' || format == "maven2") && path =~ "^/com/example/.*"`},
+		{name: "coordinate attribute", expr: `coordinate.groupId == "com.example" or coordinate.version != "1.0"`},
+		{name: "empty", expr: "  ", errContains: "expression is empty"},
+		{name: "unknown identifier", expr: `name == "x"`, errContains: "unknown identifier"},
+		{name: "missing operator", expr: `path "x"`, errContains: "expected operator"},
+		{name: "unterminated string", expr: `path == "x`, errContains: "unterminated string"},
+		{name: "invalid regex", expr: `path =~ "(["`, errContains: "invalid regular expression"},
+		{name: "unbalanced parentheses", expr: `(path == "x"`, errContains: "expected )"},
+		{name: "dangling and", expr: `path == "x" and`, errContains: "expected identifier"},
+		{name: "single equals", expr: `path = "x"`, errContains: "unknown operator"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.expr)
+			if tt.errContains == "" {
+				if err != nil {
+					t.Errorf("Validate(%q) unexpected error = %v", tt.expr, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("Validate(%q) error = %v, want error containing %q", tt.expr, err, tt.errContains)
+			}
+		})
+	}
+}
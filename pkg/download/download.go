@@ -0,0 +1,229 @@
+// Package download mirrors repository assets to a local directory.
+package download
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alauda/nexus-cli/pkg/nexus"
+)
+
+// 下载状态
+const (
+	StatusDownloaded = "downloaded"
+	StatusResumed    = "resumed"
+	StatusUnchanged  = "unchanged"
+	StatusFailed     = "failed"
+)
+
+// partSuffix 未完成下载的文件后缀，再次下载时从该文件的末尾继续
+const partSuffix = ".part"
+
+// Opener 打开仓库中的文件，offset 大于 0 时从该位置继续下载，返回的 bool 表示是否为部分内容
+type Opener func(ctx context.Context, assetPath string, offset int64) (io.ReadCloser, bool, error)
+
+// Entry 清单中的一个文件
+type Entry struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA1   string `json:"sha1,omitempty"`
+	SHA256 string `json:"sha256,omitempty"`
+	// Verified 是否与服务器提供的校验和一致，服务器没有 sha1 和 sha256 时为 false
+	Verified bool   `json:"verified"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Manifest 下载清单
+type Manifest struct {
+	Repository string    `json:"repository"`
+	PathPrefix string    `json:"pathPrefix,omitempty"`
+	Created    time.Time `json:"created"`
+	Assets     []Entry   `json:"assets"`
+}
+
+// WriteManifest 将清单写入文件，文件按路径排序
+func WriteManifest(file string, manifest Manifest) error {
+	sort.Slice(manifest.Assets, func(i, j int) bool { return manifest.Assets[i].Path < manifest.Assets[j].Path })
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if err := os.WriteFile(file, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// LocalPath 返回文件在 dest 中的路径，资产路径中的 .. 不会超出 dest
+func LocalPath(dest, assetPath string) (string, error) {
+	clean := path.Clean("/" + assetPath)
+	if clean == "/" {
+		return "", fmt.Errorf("invalid asset path %q", assetPath)
+	}
+	return filepath.Join(dest, filepath.FromSlash(clean[1:])), nil
+}
+
+// Fetch 下载一个文件到 dest
+//
+// 本地已有校验和一致的文件时不再下载；存在未完成的 .part 文件时通过 Range 请求继续下载。
+// 下载完成后与服务器的 sha256（没有时为 sha1）比较，不一致时删除已下载的内容并返回错误。
+func Fetch(ctx context.Context, open Opener, asset nexus.Asset, dest string) (Entry, error) {
+	entry := Entry{Path: asset.Path, Size: asset.FileSize}
+	local, err := LocalPath(dest, asset.Path)
+	if err != nil {
+		return entry, err
+	}
+
+	if info, err := os.Stat(local); err == nil && (asset.FileSize == 0 || info.Size() == asset.FileSize) {
+		if err := verify(&entry, local, asset.Checksum); err == nil && entry.Verified {
+			entry.Status = StatusUnchanged
+			return entry, nil
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(local), 0o755); err != nil {
+		return entry, fmt.Errorf("failed to create directory: %w", err)
+	}
+	part := local + partSuffix
+	var offset int64
+	if info, err := os.Stat(part); err == nil {
+		offset = info.Size()
+	}
+	// 服务器没有提供文件大小时无法判断 .part 是否完整，重新下载
+	if asset.FileSize == 0 || offset > asset.FileSize {
+		offset = 0
+	}
+
+	entry.Status = StatusDownloaded
+	if offset < asset.FileSize || offset == 0 {
+		resumed, err := fetchPart(ctx, open, asset.Path, part, offset)
+		if err != nil {
+			return entry, err
+		}
+		if resumed {
+			entry.Status = StatusResumed
+		}
+	}
+
+	if err := verify(&entry, part, asset.Checksum); err != nil {
+		_ = os.Remove(part)
+		return entry, err
+	}
+	if err := os.Rename(part, local); err != nil {
+		return entry, fmt.Errorf("failed to rename %s: %w", part, err)
+	}
+	return entry, nil
+}
+
+// fetchPart 将文件下载到 part，offset 大于 0 且服务器支持 Range 时追加到已有内容之后
+func fetchPart(ctx context.Context, open Opener, assetPath, part string, offset int64) (bool, error) {
+	body, partial, err := open(ctx, assetPath, offset)
+	if err != nil {
+		return false, err
+	}
+	defer body.Close()
+
+	resumed := offset > 0 && partial
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if resumed {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	f, err := os.OpenFile(part, flags, 0o644)
+	if err != nil {
+		return false, err
+	}
+	if _, err := io.Copy(f, body); err != nil {
+		f.Close()
+		return false, fmt.Errorf("failed to download %s: %w", assetPath, err)
+	}
+	if err := f.Close(); err != nil {
+		return false, err
+	}
+	return resumed, nil
+}
+
+// verify 计算文件的大小和校验和并与服务器的校验和比较
+func verify(entry *Entry, file string, checksum map[string]string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h1, h256 := sha1.New(), sha256.New()
+	size, err := io.Copy(io.MultiWriter(h1, h256), f)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	entry.Size = size
+	entry.SHA1 = hex.EncodeToString(h1.Sum(nil))
+	entry.SHA256 = hex.EncodeToString(h256.Sum(nil))
+	entry.Verified = false
+
+	for _, algorithm := range []string{"sha256", "sha1"} {
+		expected := checksum[algorithm]
+		if expected == "" {
+			continue
+		}
+		actual := entry.SHA256
+		if algorithm == "sha1" {
+			actual = entry.SHA1
+		}
+		if !strings.EqualFold(expected, actual) {
+			return fmt.Errorf("%s checksum mismatch for %s: expected %s, got %s", algorithm, entry.Path, expected, actual)
+		}
+		entry.Verified = true
+		return nil
+	}
+	return nil
+}
+
+// Run 使用最多 workers 个并发下载，返回与 assets 顺序一致的清单项，失败的项状态为 failed
+func Run(ctx context.Context, assets []nexus.Asset, workers int, fetch func(context.Context, nexus.Asset) (Entry, error)) []Entry {
+	if workers < 1 {
+		workers = 1
+	}
+	entries := make([]Entry, len(assets))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				entry, err := Entry{Path: assets[i].Path}, ctx.Err()
+				if err == nil {
+					entry, err = fetch(ctx, assets[i])
+				}
+				if err != nil {
+					entry.Status = StatusFailed
+					entry.Error = err.Error()
+					if errors.Is(err, context.Canceled) {
+						entry.Error = "canceled"
+					}
+				}
+				entries[i] = entry
+			}
+		}()
+	}
+	for i := range assets {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return entries
+}
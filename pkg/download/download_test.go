@@ -0,0 +1,86 @@
+package download
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alauda/nexus-cli/pkg/nexus"
+)
+
+func TestLocalPath(t *testing.T) {
+	tests := []struct {
+		assetPath string
+		want      string
+		wantErr   bool
+	}{
+		{"com/example/lib-1.0.jar", "dest/com/example/lib-1.0.jar", false},
+		{"/docs/index.html", "dest/docs/index.html", false},
+		{"../../etc/passwd", "dest/etc/passwd", false},
+		{"/", "", true},
+	}
+	for _, tt := range tests {
+		got, err := LocalPath("dest", tt.assetPath)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("LocalPath(%q) error = %v, wantErr %v", tt.assetPath, err, tt.wantErr)
+			continue
+		}
+		if got != filepath.FromSlash(tt.want) {
+			t.Errorf("LocalPath(%q) = %s, want %s", tt.assetPath, got, tt.want)
+		}
+	}
+}
+
+func TestFetch(t *testing.T) {
+	content := "hello nexus"
+	sum := sha256.Sum256([]byte(content))
+	asset := nexus.Asset{
+		Path:     "a/b.txt",
+		FileSize: int64(len(content)),
+		Checksum: map[string]string{"sha256": hex.EncodeToString(sum[:])},
+	}
+	var offsets []int64
+	open := func(_ context.Context, _ string, offset int64) (io.ReadCloser, bool, error) {
+		offsets = append(offsets, offset)
+		return io.NopCloser(strings.NewReader(content[offset:])), offset > 0, nil
+	}
+
+	dest := t.TempDir()
+	local := filepath.Join(dest, "a", "b.txt")
+	if err := os.MkdirAll(filepath.Dir(local), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(local+partSuffix, []byte(content[:5]), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := Fetch(context.Background(), open, asset, dest)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if entry.Status != StatusResumed || !entry.Verified || offsets[0] != 5 {
+		t.Errorf("Fetch() = %+v with offsets %v, want a verified resume from 5", entry, offsets)
+	}
+	if data, _ := os.ReadFile(local); string(data) != content {
+		t.Errorf("downloaded %q, want %q", data, content)
+	}
+
+	entry, err = Fetch(context.Background(), open, asset, dest)
+	if err != nil || entry.Status != StatusUnchanged || len(offsets) != 1 {
+		t.Errorf("second Fetch() = %+v, %v, want unchanged without a request", entry, err)
+	}
+
+	asset.Path = "c.txt"
+	asset.Checksum = map[string]string{"sha1": "0000"}
+	if _, err := Fetch(context.Background(), open, asset, dest); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("Fetch() error = %v, want a checksum mismatch", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "c.txt"+partSuffix)); !os.IsNotExist(err) {
+		t.Error("Fetch() should remove the part file after a checksum mismatch")
+	}
+}
@@ -0,0 +1,151 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/alauda/nexus-cli/pkg/nexus"
+	"github.com/alauda/nexus-cli/pkg/upload"
+)
+
+// Transfer 需要复制的组件，Assets 只包含目标仓库中缺少或校验和不同的资产
+type Transfer struct {
+	Component nexus.Component
+	Assets    []nexus.Asset
+}
+
+// Report 一个仓库的内容差异
+type Report struct {
+	Mapping
+	Format string `json:"format"`
+	// Components 和 Assets 需要复制的组件数和资产数
+	Components int   `json:"components"`
+	Assets     int   `json:"assets"`
+	Bytes      int64 `json:"bytes"`
+	// UpToDate 目标仓库中已存在且校验和一致的资产数
+	UpToDate int `json:"upToDate"`
+	// Unsupported 不复制该仓库内容的原因，例如 proxy 仓库或不支持上传的格式
+	Unsupported string     `json:"unsupported,omitempty"`
+	Transfers   []Transfer `json:"-"`
+}
+
+// Compare 比较源仓库和目标仓库的内容，目标仓库不存在时所有资产都需要复制
+//
+// 资产按路径匹配，比较 sha256（没有时为 sha1），因此重复运行时只复制新增或变化的资产。
+func Compare(ctx context.Context, source, target *nexus.Client, m Mapping) (*Report, error) {
+	repo, err := source.GetRepository(m.Source)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{Mapping: m, Format: repo.Format}
+	if repo.Type != "hosted" {
+		report.Unsupported = fmt.Sprintf("content of %s repositories is not copied", repo.Type)
+		return report, nil
+	}
+	if !supportsUpload(repo.Format) {
+		report.Unsupported = fmt.Sprintf("uploading %s components is not supported", repo.Format)
+		return report, nil
+	}
+
+	existing := make(map[string]map[string]string)
+	it := target.Assets(ctx, m.Target)
+	for it.Next() {
+		asset := it.Item()
+		existing[strings.TrimPrefix(asset.Path, "/")] = asset.Checksum
+	}
+	if err := it.Err(); err != nil && !strings.Contains(err.Error(), "status 404") {
+		return nil, fmt.Errorf("failed to list assets of %s on the target: %w", m.Target, err)
+	}
+
+	components := source.Components(ctx, m.Source)
+	for components.Next() {
+		component := components.Item()
+		transfer := Transfer{Component: component}
+		for _, asset := range component.Assets {
+			// maven 的校验和文件由 Nexus 在目标仓库自动生成，不复制也不计数
+			if repo.Format == "maven2" && upload.IsChecksumFile(asset.Path) {
+				continue
+			}
+			checksum, ok := existing[strings.TrimPrefix(asset.Path, "/")]
+			if ok && sameChecksum(asset.Checksum, checksum) {
+				report.UpToDate++
+				continue
+			}
+			transfer.Assets = append(transfer.Assets, asset)
+			report.Bytes += asset.FileSize
+		}
+		if len(transfer.Assets) > 0 {
+			report.Transfers = append(report.Transfers, transfer)
+			report.Components++
+			report.Assets += len(transfer.Assets)
+		}
+	}
+	if err := components.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list components of %s: %w", m.Source, err)
+	}
+	return report, nil
+}
+
+// sameChecksum 比较两个资产的 sha256 或 sha1，两者都没有时视为不同
+func sameChecksum(a, b map[string]string) bool {
+	for _, algorithm := range []string{"sha256", "sha1"} {
+		if a[algorithm] != "" && b[algorithm] != "" {
+			return strings.EqualFold(a[algorithm], b[algorithm])
+		}
+	}
+	return false
+}
+
+func supportsUpload(format string) bool {
+	for _, f := range upload.Formats() {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Items 将组件转换为上传请求，请求中的文件为源仓库中的资产路径
+func Items(format string, transfer Transfer) ([]upload.Item, error) {
+	files := make([]upload.File, 0, len(transfer.Assets))
+	for _, asset := range transfer.Assets {
+		p := strings.TrimPrefix(asset.Path, "/")
+		files = append(files, upload.File{Path: p, Rel: p})
+	}
+	opts := upload.Options{}
+	if format == "maven2" {
+		c := transfer.Component
+		opts.Maven = upload.MavenOptions{GroupID: c.Group, ArtifactID: c.Name, Version: c.Version}
+	}
+	return upload.Plan(format, files, opts)
+}
+
+// Copy 将报告中的组件从源仓库流式复制到目标仓库，返回每个上传请求的结果
+func Copy(ctx context.Context, source, target *nexus.Client, report *Report, workers int) ([]upload.Result, error) {
+	var items []upload.Item
+	for _, transfer := range report.Transfers {
+		transferItems, err := Items(report.Format, transfer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to plan %s: %w", transfer.Component.Name, err)
+		}
+		items = append(items, transferItems...)
+	}
+
+	return upload.Run(ctx, items, workers, func(ctx context.Context, item upload.Item) error {
+		files := make(map[string]nexus.UploadFile, len(item.Files))
+		for field, assetPath := range item.Files {
+			assetPath := assetPath
+			files[field] = nexus.UploadFile{
+				Name: path.Base(assetPath),
+				Open: func() (io.ReadCloser, error) {
+					body, _, err := source.OpenAsset(ctx, report.Source, assetPath, 0)
+					return body, err
+				},
+			}
+		}
+		return target.UploadComponentFiles(ctx, report.Target, item.Fields, files)
+	}), nil
+}
@@ -0,0 +1,134 @@
+// Package migrate copies repository definitions, security objects and content between Nexus instances.
+package migrate
+
+import (
+	"fmt"
+
+	"github.com/alauda/nexus-cli/pkg/config"
+	"github.com/alauda/nexus-cli/pkg/nexus"
+)
+
+// ExportRepository 将服务器上的仓库配置转换为配置文件中的仓库，name 为目标仓库名称
+//
+// Nexus 不返回上游认证的密码，代理仓库的认证不会导出，需要在目标仓库上重新设置，此时返回警告。
+func ExportRepository(repo *nexus.Repository, name string) (config.Repository, []string) {
+	var warnings []string
+	online := repo.Online
+	exported := config.Repository{
+		Name:        name,
+		Format:      repo.Format,
+		Type:        repo.Type,
+		Online:      &online,
+		RoutingRule: repo.RoutingRule,
+	}
+	if repo.Storage != nil {
+		exported.Storage = config.StorageConfig{
+			BlobStoreName:               repo.Storage.BlobStoreName,
+			StrictContentTypeValidation: repo.Storage.StrictContentTypeValidation,
+			WritePolicy:                 repo.Storage.WritePolicy,
+		}
+	}
+	if repo.Proxy != nil {
+		exported.Proxy = &config.ProxyConfig{
+			RemoteURL:      repo.Proxy.RemoteURL,
+			ContentMaxAge:  repo.Proxy.ContentMaxAge,
+			MetadataMaxAge: repo.Proxy.MetadataMaxAge,
+		}
+	}
+	if repo.NegativeCache != nil {
+		enabled, ttl := repo.NegativeCache.Enabled, repo.NegativeCache.TimeToLive
+		exported.NegativeCache = &config.NegativeCacheConfig{Enabled: &enabled, TimeToLive: &ttl}
+	}
+	if repo.HTTPClient != nil {
+		autoBlock := repo.HTTPClient.AutoBlock
+		exported.HTTPClient = &config.HTTPClientConfig{Blocked: repo.HTTPClient.Blocked, AutoBlock: &autoBlock}
+		if conn := repo.HTTPClient.Connection; conn != nil {
+			exported.HTTPClient.Connection = &config.HTTPConnectionConfig{
+				Timeout:                 conn.Timeout,
+				Retries:                 conn.RetryAttempts,
+				UserAgentSuffix:         conn.UserAgentSuffix,
+				EnableCircularRedirects: conn.EnableCircularRedirects,
+				EnableCookies:           conn.EnableCookies,
+				UseTrustStore:           conn.UseTrustStore,
+			}
+		}
+		if repo.HTTPClient.Authentication != nil {
+			warnings = append(warnings, fmt.Sprintf("repository %s: upstream authentication is not exported, set it on the target repository", repo.Name))
+		}
+	}
+	if repo.Maven != nil {
+		exported.Maven = &config.MavenConfig{
+			VersionPolicy: repo.Maven.VersionPolicy,
+			LayoutPolicy:  repo.Maven.LayoutPolicy,
+		}
+	}
+	if repo.Docker != nil {
+		exported.Docker = &config.DockerConfig{
+			ForceBasicAuth: repo.Docker.ForceBasicAuth,
+			V1Enabled:      repo.Docker.V1Enabled,
+			Subdomain:      repo.Docker.Subdomain,
+			PathEnabled:    repo.Docker.PathEnabled,
+		}
+		if repo.Docker.HTTPPort != nil {
+			exported.Docker.HTTPPort = *repo.Docker.HTTPPort
+		}
+		if repo.Docker.HTTPSPort != nil {
+			exported.Docker.HTTPSPort = *repo.Docker.HTTPSPort
+		}
+	}
+	if repo.DockerProxy != nil {
+		exported.DockerProxy = &config.DockerProxyConfig{
+			IndexType:                repo.DockerProxy.IndexType,
+			IndexURL:                 repo.DockerProxy.IndexURL,
+			CacheForeignLayers:       repo.DockerProxy.CacheForeignLayers,
+			ForeignLayerURLWhitelist: repo.DockerProxy.ForeignLayerURLWhitelist,
+		}
+	}
+	if repo.Apt != nil {
+		exported.Apt = &config.AptConfig{Distribution: repo.Apt.Distribution, Flat: repo.Apt.Flat}
+	}
+	if repo.Cleanup != nil && len(repo.Cleanup.PolicyNames) > 0 {
+		exported.Cleanup = &config.CleanupConfig{PolicyNames: repo.Cleanup.PolicyNames}
+	}
+	return exported, warnings
+}
+
+// ExportContentSelector 将内容选择器转换为配置
+func ExportContentSelector(selector nexus.ContentSelectorResponse) config.ContentSelector {
+	return config.ContentSelector{
+		Name:        selector.Name,
+		Description: selector.Description,
+		Expression:  selector.Expression,
+	}
+}
+
+// ExportPrivilege 将权限转换为配置
+func ExportPrivilege(priv nexus.PrivilegeResponse) config.Privilege {
+	return config.Privilege{
+		Name:            priv.Name,
+		Description:     priv.Description,
+		Type:            priv.Type,
+		Format:          priv.Format,
+		Repository:      priv.Repository,
+		Actions:         priv.Actions,
+		ContentSelector: priv.ContentSelector,
+		Pattern:         priv.Pattern,
+		Domain:          priv.Domain,
+		ScriptName:      priv.ScriptName,
+	}
+}
+
+// ExportRole 将角色转换为配置，外部来源（如 LDAP）的角色保留来源
+func ExportRole(role nexus.RoleResponse) config.Role {
+	exported := config.Role{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		Privileges:  role.Privileges,
+		Roles:       role.Roles,
+	}
+	if role.Source != "" && role.Source != config.SourceDefault {
+		exported.Source = role.Source
+	}
+	return exported
+}
@@ -0,0 +1,260 @@
+package migrate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/alauda/nexus-cli/pkg/config"
+	"github.com/alauda/nexus-cli/pkg/nexus"
+	"github.com/alauda/nexus-cli/pkg/service"
+)
+
+// Mapping 源仓库到目标仓库的映射
+type Mapping struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// ParseMappings 解析 source 或 source:target 形式的仓库参数
+func ParseMappings(args []string) ([]Mapping, error) {
+	var mappings []Mapping
+	seen := make(map[string]bool)
+	for _, arg := range args {
+		source, target, found := strings.Cut(arg, ":")
+		if !found {
+			target = source
+		}
+		if source == "" || target == "" {
+			return nil, fmt.Errorf("invalid repository mapping %q (expected source or source:target)", arg)
+		}
+		if seen[target] {
+			return nil, fmt.Errorf("repository %s is the target of more than one mapping", target)
+		}
+		seen[target] = true
+		mappings = append(mappings, Mapping{Source: source, Target: target})
+	}
+	return mappings, nil
+}
+
+// AllRepositories 返回源实例上所有仓库到同名仓库的映射
+func AllRepositories(source *nexus.Client) ([]Mapping, error) {
+	repos, err := source.ListRepositories()
+	if err != nil {
+		return nil, err
+	}
+	mappings := make([]Mapping, 0, len(repos))
+	for _, repo := range repos {
+		mappings = append(mappings, Mapping{Source: repo.Name, Target: repo.Name})
+	}
+	sort.Slice(mappings, func(i, j int) bool { return mappings[i].Source < mappings[j].Source })
+	return mappings, nil
+}
+
+// Definitions 返回目标实例上缺少的仓库定义
+//
+// 无法通过配置创建的仓库（group 仓库和 createRepository 不支持的格式）不会导出，作为警告返回。
+func Definitions(source, target *nexus.Client, mappings []Mapping) ([]config.Repository, []string, error) {
+	var repos []config.Repository
+	var warnings []string
+	for _, m := range mappings {
+		exists, err := target.RepositoryExists(m.Target)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to check repository %s on the target: %w", m.Target, err)
+		}
+		if exists {
+			continue
+		}
+		repo, err := source.GetRepository(m.Source)
+		if err != nil {
+			return nil, nil, err
+		}
+		if repo.Type == "group" || !service.SupportsRepository(repo.Format, repo.Type) {
+			warnings = append(warnings, fmt.Sprintf("repository %s (%s %s) cannot be created from a configuration, create %s on the target first",
+				m.Source, repo.Format, repo.Type, m.Target))
+			continue
+		}
+		exported, exportWarnings := ExportRepository(repo, m.Target)
+		repos = append(repos, exported)
+		warnings = append(warnings, exportWarnings...)
+	}
+	return repos, warnings, nil
+}
+
+// Security 返回目标实例上缺少的内容选择器、权限和角色，内置的只读权限和角色不会复制
+//
+// mappings 为空时复制所有对象。指定 mappings（-r）时只复制与映射仓库相关的对象：
+// 引用映射仓库的权限按目标仓库复制（仓库和名称中的源仓库名替换为目标仓库名，见 targetPrivilegeName），
+// 这些权限引用的内容选择器，以及包含这些权限或映射仓库内置权限的角色。
+// 角色中引用其他仓库的权限和目标实例上不存在的其他权限会被去掉，作为警告返回。
+func Security(source, target *nexus.Client, mappings []Mapping) (*config.Config, []string, error) {
+	sourceSelectors, err := source.ListContentSelectors()
+	if err != nil {
+		return nil, nil, err
+	}
+	targetSelectors, err := target.ListContentSelectors()
+	if err != nil {
+		return nil, nil, err
+	}
+	sourcePrivileges, err := source.ListPrivileges()
+	if err != nil {
+		return nil, nil, err
+	}
+	targetPrivileges, err := target.ListPrivileges()
+	if err != nil {
+		return nil, nil, err
+	}
+	sourceRoles, err := source.ListRoles()
+	if err != nil {
+		return nil, nil, err
+	}
+	targetRoles, err := target.ListRoles()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	scope := securityScope{
+		targets:          make(map[string][]string),
+		renamed:          make(map[string][]string),
+		repositories:     make(map[string]string),
+		targetPrivileges: make(map[string]bool),
+	}
+	for _, m := range mappings {
+		scope.targets[m.Source] = append(scope.targets[m.Source], m.Target)
+	}
+	for _, priv := range targetPrivileges {
+		scope.targetPrivileges[priv.Name] = true
+	}
+
+	cfg := &config.Config{}
+	selectors := make(map[string]bool)
+	for _, priv := range sourcePrivileges {
+		for _, c := range scope.privilegeCopies(priv) {
+			if priv.ReadOnly || scope.targetPrivileges[c.Name] {
+				continue
+			}
+			scope.targetPrivileges[c.Name] = true
+			cfg.Privileges = append(cfg.Privileges, ExportPrivilege(c))
+			if c.ContentSelector != "" {
+				selectors[c.ContentSelector] = true
+			}
+		}
+	}
+
+	existing := make(map[string]bool)
+	for _, selector := range targetSelectors {
+		existing[selector.Name] = true
+	}
+	for _, selector := range sourceSelectors {
+		if !existing[selector.Name] && (len(mappings) == 0 || selectors[selector.Name]) {
+			cfg.ContentSelectors = append(cfg.ContentSelectors, ExportContentSelector(selector))
+		}
+	}
+
+	existing = make(map[string]bool)
+	for _, role := range targetRoles {
+		existing[role.ID] = true
+	}
+	var warnings []string
+	for _, role := range sourceRoles {
+		if role.ReadOnly || existing[role.ID] {
+			continue
+		}
+		if len(mappings) == 0 {
+			cfg.Roles = append(cfg.Roles, ExportRole(role))
+			continue
+		}
+		privileges, roleWarnings := scope.rolePrivileges(role)
+		if privileges == nil {
+			continue
+		}
+		role.Privileges = privileges
+		cfg.Roles = append(cfg.Roles, ExportRole(role))
+		warnings = append(warnings, roleWarnings...)
+	}
+	return cfg, warnings, nil
+}
+
+// securityScope 按仓库映射限定和重命名安全对象，targets 为空时不限定
+type securityScope struct {
+	// targets 源仓库到目标仓库
+	targets map[string][]string
+	// renamed 引用映射仓库的源权限名称到目标权限名称
+	renamed map[string][]string
+	// repositories 引用其他仓库的源权限名称到仓库名称
+	repositories map[string]string
+	// targetPrivileges 目标实例上已有或将要创建的权限
+	targetPrivileges map[string]bool
+}
+
+// privilegeCopies 返回源权限在目标实例上对应的权限，不需要复制时返回 nil
+func (s *securityScope) privilegeCopies(priv nexus.PrivilegeResponse) []nexus.PrivilegeResponse {
+	if len(s.targets) == 0 {
+		return []nexus.PrivilegeResponse{priv}
+	}
+	if priv.Repository == "" || priv.Repository == "*" {
+		return nil
+	}
+	targets, ok := s.targets[priv.Repository]
+	if !ok {
+		s.repositories[priv.Name] = priv.Repository
+		return nil
+	}
+	copies := make([]nexus.PrivilegeResponse, 0, len(targets))
+	for _, target := range targets {
+		c := priv
+		c.Repository = target
+		c.Name = targetPrivilegeName(priv.Name, priv.Repository, target, len(targets))
+		copies = append(copies, c)
+		s.renamed[priv.Name] = append(s.renamed[priv.Name], c.Name)
+	}
+	return copies
+}
+
+// rolePrivileges 返回角色在目标实例上的权限，角色与映射仓库无关时返回 nil
+func (s *securityScope) rolePrivileges(role nexus.RoleResponse) ([]string, []string) {
+	var privileges, warnings []string
+	related := false
+	for _, name := range role.Privileges {
+		if renamed, ok := s.renamed[name]; ok {
+			related = true
+			privileges = append(privileges, renamed...)
+			continue
+		}
+		if repository, ok := s.repositories[name]; ok {
+			warnings = append(warnings, fmt.Sprintf("role %s: privilege %s is not copied because repository %s is not migrated", role.ID, name, repository))
+			continue
+		}
+		if !s.targetPrivileges[name] {
+			warnings = append(warnings, fmt.Sprintf("role %s: privilege %s is not copied because it does not exist on the target", role.ID, name))
+			continue
+		}
+		privileges = append(privileges, name)
+	}
+	if !related {
+		return nil, nil
+	}
+	return privileges, warnings
+}
+
+// targetPrivilegeName 返回引用 target 仓库的权限名称
+//
+// 名称中以 - 分隔的源仓库名（例如内置权限 nx-repository-view-maven2-maven-releases-read）替换为目标仓库名；
+// 名称不包含源仓库名且同一源仓库映射到多个目标仓库时，在名称后追加目标仓库名以免重名。
+func targetPrivilegeName(name, source, target string, targets int) string {
+	if source == target {
+		return name
+	}
+	parts := strings.Split(name, "-")
+	sourceParts := strings.Split(source, "-")
+	for i := 0; i+len(sourceParts) <= len(parts); i++ {
+		if strings.Join(parts[i:i+len(sourceParts)], "-") == source {
+			replaced := append(append(append([]string{}, parts[:i]...), target), parts[i+len(sourceParts):]...)
+			return strings.Join(replaced, "-")
+		}
+	}
+	if targets > 1 {
+		return name + "-" + target
+	}
+	return name
+}
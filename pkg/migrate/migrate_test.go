@@ -0,0 +1,244 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/alauda/nexus-cli/pkg/nexus"
+)
+
+func TestParseMappings(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    []Mapping
+		wantErr bool
+	}{
+		{[]string{"a", "b:c"}, []Mapping{{"a", "a"}, {"b", "c"}}, false},
+		{[]string{"a:"}, nil, true},
+		{[]string{"a:c", "b:c"}, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMappings(tt.args)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseMappings(%v) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseMappings(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestExportRepository(t *testing.T) {
+	port := 8082
+	repo := &nexus.Repository{
+		Name:    "docker-hosted",
+		Format:  "docker",
+		Type:    "hosted",
+		Online:  true,
+		Storage: &nexus.RepositoryStorage{BlobStoreName: "docker", WritePolicy: "ALLOW_ONCE"},
+		Docker:  &nexus.DockerSettings{HTTPPort: &port, V1Enabled: true},
+		HTTPClient: &nexus.HTTPClientSettings{
+			Authentication: &nexus.Authentication{Type: "username", Username: "u"},
+		},
+	}
+	exported, warnings := ExportRepository(repo, "docker-new")
+	if exported.Name != "docker-new" || exported.Storage.WritePolicy != "ALLOW_ONCE" || exported.Docker.HTTPPort != 8082 {
+		t.Errorf("ExportRepository() = %+v", exported)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("ExportRepository() warnings = %v, want a warning about authentication", warnings)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	responses := map[string]string{
+		"/service/rest/v1/repositories/src": `{"name":"src","format":"raw","type":"hosted"}`,
+		"/service/rest/v1/components": `{"items":[
+			{"id":"1","name":"a","assets":[{"path":"docs/a.txt","checksum":{"sha1":"aaa"},"fileSize":10}]},
+			{"id":"2","name":"b","assets":[{"path":"docs/b.txt","checksum":{"sha1":"bbb"},"fileSize":20}]},
+			{"id":"3","name":"c","assets":[{"path":"docs/c.txt","checksum":{"sha1":"ccc"},"fileSize":30}]}
+		]}`,
+		"/service/rest/v1/assets": `{"items":[
+			{"path":"/docs/a.txt","checksum":{"sha1":"aaa"}},
+			{"path":"/docs/b.txt","checksum":{"sha1":"changed"}}
+		]}`,
+		"/service/rest/v1/repositories/mvn": `{"name":"mvn","format":"maven2","type":"hosted"}`,
+	}
+	mavenComponents := `{"items":[
+		{"id":"4","group":"com.example","name":"app","version":"1.0","assets":[
+			{"path":"com/example/app/1.0/app-1.0.jar","checksum":{"sha1":"jar"},"fileSize":100},
+			{"path":"com/example/app/1.0/app-1.0.jar.sha1","fileSize":40},
+			{"path":"com/example/app/1.0/app-1.0.pom","checksum":{"sha1":"pom"},"fileSize":10},
+			{"path":"com/example/app/1.0/app-1.0.pom.md5","fileSize":32}
+		]}
+	]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/service/rest/v1/components" && r.URL.Query().Get("repository") == "mvn" {
+			_, _ = fmt.Fprint(w, mavenComponents)
+			return
+		}
+		body, ok := responses[r.URL.Path]
+		if !ok || (r.URL.Path == "/service/rest/v1/assets" && r.URL.Query().Get("repository") != "dst") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+	client := nexus.NewClient(server.URL, "admin", "admin123")
+
+	report, err := Compare(context.Background(), client, client, Mapping{Source: "src", Target: "dst"})
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+	if report.Components != 2 || report.Assets != 2 || report.Bytes != 50 || report.UpToDate != 1 {
+		t.Errorf("Compare() = %+v, want 2 components, 2 assets, 50 bytes and 1 up to date", report)
+	}
+
+	items, err := Items(report.Format, report.Transfers[0])
+	if err != nil {
+		t.Fatalf("Items() error = %v", err)
+	}
+	if items[0].Fields["raw.directory"] != "/docs" || items[0].Files["raw.asset1"] != "docs/b.txt" {
+		t.Errorf("Items() = %+v", items[0])
+	}
+
+	report, err = Compare(context.Background(), client, client, Mapping{Source: "src", Target: "missing"})
+	if err != nil {
+		t.Fatalf("Compare() with a missing target error = %v", err)
+	}
+	if report.Assets != 3 {
+		t.Errorf("Compare() with a missing target copies %d assets, want 3", report.Assets)
+	}
+
+	// maven 的校验和文件由目标仓库生成，不计入资产数和大小
+	report, err = Compare(context.Background(), client, client, Mapping{Source: "mvn", Target: "missing"})
+	if err != nil {
+		t.Fatalf("Compare(mvn) error = %v", err)
+	}
+	if report.Components != 1 || report.Assets != 2 || report.Bytes != 110 {
+		t.Errorf("Compare(mvn) = %+v, want 1 component with 2 assets and 110 bytes", report)
+	}
+}
+
+func TestTargetPrivilegeName(t *testing.T) {
+	tests := []struct {
+		name, source, target string
+		targets              int
+		want                 string
+	}{
+		{"nx-repository-view-maven2-maven-releases-read", "maven-releases", "maven-internal", 1, "nx-repository-view-maven2-maven-internal-read"},
+		{"nx-repository-view-maven2-maven-read", "maven", "java", 1, "nx-repository-view-maven2-java-read"},
+		{"npm-hosted-deploy", "npm-hosted", "npm-internal", 1, "npm-internal-deploy"},
+		{"team-deploy", "npm-hosted", "npm-internal", 1, "team-deploy"},
+		{"team-deploy", "npm-hosted", "npm-internal", 2, "team-deploy-npm-internal"},
+		{"npm-hosted-deploy", "npm-hosted", "npm-hosted", 1, "npm-hosted-deploy"},
+	}
+	for _, tt := range tests {
+		if got := targetPrivilegeName(tt.name, tt.source, tt.target, tt.targets); got != tt.want {
+			t.Errorf("targetPrivilegeName(%q, %q, %q) = %q, want %q", tt.name, tt.source, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestSecurity(t *testing.T) {
+	newServer := func(selectors, privileges, roles string) *nexus.Client {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/service/rest/v1/security/content-selectors":
+				_, _ = w.Write([]byte(selectors))
+			case "/service/rest/v1/security/privileges":
+				_, _ = w.Write([]byte(privileges))
+			case "/service/rest/v1/security/roles":
+				_, _ = w.Write([]byte(roles))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		t.Cleanup(server.Close)
+		return nexus.NewClient(server.URL, "admin", "admin123")
+	}
+	source := newServer(
+		`[{"name":"team-a","expression":"path =^ \"/com/example/a/\""},{"name":"team-b","expression":"path =^ \"/com/example/b/\""}]`,
+		`[{"name":"nx-repository-view-npm-npm-hosted-read","type":"repository-view","format":"npm","repository":"npm-hosted","actions":["READ"],"readOnly":true},
+		  {"name":"npm-hosted-team-a","type":"repository-content-selector","format":"npm","repository":"npm-hosted","contentSelector":"team-a","actions":["READ"]},
+		  {"name":"maven-releases-team-b","type":"repository-content-selector","format":"maven2","repository":"maven-releases","contentSelector":"team-b","actions":["READ"]},
+		  {"name":"all-npm","type":"repository-view","format":"npm","repository":"*","actions":["READ"]},
+		  {"name":"custom-app","type":"application","domain":"users","actions":["READ"]}]`,
+		`[{"id":"npm-readers","privileges":["nx-repository-view-npm-npm-hosted-read","nx-search-read","custom-app"]},
+		  {"id":"team-a","privileges":["npm-hosted-team-a","maven-releases-team-b"]},
+		  {"id":"maven-only","privileges":["maven-releases-team-b"]},
+		  {"id":"nx-admin","privileges":["nx-all"],"readOnly":true}]`,
+	)
+	target := newServer(`[]`, `[{"name":"nx-search-read","type":"application","readOnly":true}]`, `[]`)
+
+	tests := []struct {
+		name           string
+		mappings       []Mapping
+		wantSelectors  []string
+		wantPrivileges map[string]string
+		wantRoles      map[string][]string
+		wantWarnings   int
+	}{
+		{
+			name:          "all repositories",
+			wantSelectors: []string{"team-a", "team-b"},
+			wantPrivileges: map[string]string{"npm-hosted-team-a": "npm-hosted", "maven-releases-team-b": "maven-releases",
+				"all-npm": "*", "custom-app": ""},
+			wantRoles: map[string][]string{
+				"npm-readers": {"nx-repository-view-npm-npm-hosted-read", "nx-search-read", "custom-app"},
+				"team-a":      {"npm-hosted-team-a", "maven-releases-team-b"},
+				"maven-only":  {"maven-releases-team-b"},
+			},
+		},
+		{
+			name:           "renamed repository",
+			mappings:       []Mapping{{Source: "npm-hosted", Target: "npm-internal"}},
+			wantSelectors:  []string{"team-a"},
+			wantPrivileges: map[string]string{"npm-internal-team-a": "npm-internal"},
+			wantRoles: map[string][]string{
+				"npm-readers": {"nx-repository-view-npm-npm-internal-read", "nx-search-read"},
+				"team-a":      {"npm-internal-team-a"},
+			},
+			// custom-app 和 maven-releases-team-b 不复制
+			wantWarnings: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, warnings, err := Security(source, target, tt.mappings)
+			if err != nil {
+				t.Fatalf("Security() error = %v", err)
+			}
+			var selectors []string
+			for _, selector := range cfg.ContentSelectors {
+				selectors = append(selectors, selector.Name)
+			}
+			privileges := make(map[string]string)
+			for _, priv := range cfg.Privileges {
+				privileges[priv.Name] = priv.Repository
+			}
+			roles := make(map[string][]string)
+			for _, role := range cfg.Roles {
+				roles[role.ID] = role.Privileges
+			}
+			if !reflect.DeepEqual(selectors, tt.wantSelectors) {
+				t.Errorf("content selectors = %v, want %v", selectors, tt.wantSelectors)
+			}
+			if !reflect.DeepEqual(privileges, tt.wantPrivileges) {
+				t.Errorf("privileges = %v, want %v", privileges, tt.wantPrivileges)
+			}
+			if !reflect.DeepEqual(roles, tt.wantRoles) {
+				t.Errorf("roles = %v, want %v", roles, tt.wantRoles)
+			}
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("warnings = %v, want %d", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
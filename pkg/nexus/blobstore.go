@@ -0,0 +1,225 @@
+package nexus
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Blob store 类型
+const (
+	BlobStoreTypeFile = "File"
+	BlobStoreTypeS3   = "S3"
+)
+
+// BlobStoreSoftQuota 软配额设置
+type BlobStoreSoftQuota struct {
+	Type  string `json:"type"`
+	Limit int64  `json:"limit"`
+}
+
+// BlobStoreResponse blob store 列表项
+type BlobStoreResponse struct {
+	Name                  string              `json:"name"`
+	Type                  string              `json:"type"`
+	Unavailable           bool                `json:"unavailable"`
+	BlobCount             int64               `json:"blobCount"`
+	TotalSizeInBytes      int64               `json:"totalSizeInBytes"`
+	AvailableSpaceInBytes int64               `json:"availableSpaceInBytes"`
+	SoftQuota             *BlobStoreSoftQuota `json:"softQuota,omitempty"`
+}
+
+// FileBlobStoreRequest 文件 blob store 请求
+type FileBlobStoreRequest struct {
+	Name      string              `json:"name,omitempty"`
+	Path      string              `json:"path"`
+	SoftQuota *BlobStoreSoftQuota `json:"softQuota,omitempty"`
+}
+
+// S3BlobStoreRequest S3 blob store 请求
+type S3BlobStoreRequest struct {
+	Name                string                `json:"name"`
+	SoftQuota           *BlobStoreSoftQuota   `json:"softQuota,omitempty"`
+	BucketConfiguration S3BucketConfiguration `json:"bucketConfiguration"`
+}
+
+// S3BucketConfiguration S3 bucket 配置
+type S3BucketConfiguration struct {
+	Bucket                   S3Bucket                    `json:"bucket"`
+	Encryption               *S3Encryption               `json:"encryption,omitempty"`
+	BucketSecurity           *S3BucketSecurity           `json:"bucketSecurity,omitempty"`
+	AdvancedBucketConnection *S3AdvancedBucketConnection `json:"advancedBucketConnection,omitempty"`
+}
+
+// S3Bucket S3 bucket 设置
+type S3Bucket struct {
+	Region     string `json:"region"`
+	Name       string `json:"name"`
+	Prefix     string `json:"prefix,omitempty"`
+	Expiration int    `json:"expiration"`
+}
+
+// S3Encryption S3 加密设置
+type S3Encryption struct {
+	EncryptionType string `json:"encryptionType,omitempty"`
+	EncryptionKey  string `json:"encryptionKey,omitempty"`
+}
+
+// S3BucketSecurity S3 认证设置
+type S3BucketSecurity struct {
+	AccessKeyID     string `json:"accessKeyId,omitempty"`
+	SecretAccessKey string `json:"secretAccessKey,omitempty"`
+	Role            string `json:"role,omitempty"`
+	SessionToken    string `json:"sessionToken,omitempty"`
+}
+
+// S3AdvancedBucketConnection S3 高级连接设置
+type S3AdvancedBucketConnection struct {
+	Endpoint              string `json:"endpoint,omitempty"`
+	SignerType            string `json:"signerType,omitempty"`
+	ForcePathStyle        bool   `json:"forcePathStyle"`
+	MaxConnectionPoolSize int    `json:"maxConnectionPoolSize,omitempty"`
+}
+
+// ListBlobStores 列出所有 blob store
+func (c *Client) ListBlobStores() ([]BlobStoreResponse, error) {
+	data, err := c.get("/service/rest/v1/blobstores")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blob stores: %w", err)
+	}
+
+	var stores []BlobStoreResponse
+	if err := json.Unmarshal(data, &stores); err != nil {
+		return nil, fmt.Errorf("failed to parse blob stores response: %w", err)
+	}
+
+	return stores, nil
+}
+
+// GetBlobStore 获取 blob store 列表信息
+func (c *Client) GetBlobStore(name string) (*BlobStoreResponse, error) {
+	stores, err := c.ListBlobStores()
+	if err != nil {
+		return nil, err
+	}
+	for i := range stores {
+		if stores[i].Name == name {
+			return &stores[i], nil
+		}
+	}
+	return nil, fmt.Errorf("blob store %s not found", name)
+}
+
+// BlobStoreExists 检查 blob store 是否存在
+func (c *Client) BlobStoreExists(name string) (bool, error) {
+	_, err := c.GetBlobStore(name)
+	if err != nil {
+		if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "not found") {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateFileBlobStore 创建文件 blob store
+func (c *Client) CreateFileBlobStore(req FileBlobStoreRequest) error {
+	_, err := c.post("/service/rest/v1/blobstores/file", req)
+	if err != nil {
+		return fmt.Errorf("failed to create file blob store %s: %w", req.Name, err)
+	}
+	return nil
+}
+
+// GetFileBlobStore 获取文件 blob store 配置
+func (c *Client) GetFileBlobStore(name string) (*FileBlobStoreRequest, error) {
+	data, err := c.get(fmt.Sprintf("/service/rest/v1/blobstores/file/%s", name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file blob store %s: %w", name, err)
+	}
+
+	var store FileBlobStoreRequest
+	if err := json.Unmarshal(data, &store); err != nil {
+		return nil, fmt.Errorf("failed to parse file blob store response: %w", err)
+	}
+	store.Name = name
+
+	return &store, nil
+}
+
+// UpdateFileBlobStore 更新文件 blob store
+func (c *Client) UpdateFileBlobStore(name string, req FileBlobStoreRequest) error {
+	_, err := c.put(fmt.Sprintf("/service/rest/v1/blobstores/file/%s", name), req)
+	if err != nil {
+		return fmt.Errorf("failed to update file blob store %s: %w", name, err)
+	}
+	return nil
+}
+
+// CreateS3BlobStore 创建 S3 blob store
+func (c *Client) CreateS3BlobStore(req S3BlobStoreRequest) error {
+	_, err := c.post("/service/rest/v1/blobstores/s3", req)
+	if err != nil {
+		return fmt.Errorf("failed to create s3 blob store %s: %w", req.Name, err)
+	}
+	return nil
+}
+
+// GetS3BlobStore 获取 S3 blob store 配置
+func (c *Client) GetS3BlobStore(name string) (*S3BlobStoreRequest, error) {
+	data, err := c.get(fmt.Sprintf("/service/rest/v1/blobstores/s3/%s", name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get s3 blob store %s: %w", name, err)
+	}
+
+	var store S3BlobStoreRequest
+	if err := json.Unmarshal(data, &store); err != nil {
+		return nil, fmt.Errorf("failed to parse s3 blob store response: %w", err)
+	}
+
+	return &store, nil
+}
+
+// UpdateS3BlobStore 更新 S3 blob store
+func (c *Client) UpdateS3BlobStore(name string, req S3BlobStoreRequest) error {
+	_, err := c.put(fmt.Sprintf("/service/rest/v1/blobstores/s3/%s", name), req)
+	if err != nil {
+		return fmt.Errorf("failed to update s3 blob store %s: %w", name, err)
+	}
+	return nil
+}
+
+// DeleteBlobStore 删除 blob store
+func (c *Client) DeleteBlobStore(name string) error {
+	_, err := c.delete(fmt.Sprintf("/service/rest/v1/blobstores/%s", name))
+	if err != nil {
+		return fmt.Errorf("failed to delete blob store %s: %w", name, err)
+	}
+	return nil
+}
+
+// BlobStoreUsage 返回使用指定 blob store 的仓库名称列表
+func (c *Client) BlobStoreUsage(name string) ([]string, error) {
+	data, err := c.get("/service/rest/v1/repositorySettings")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repository settings: %w", err)
+	}
+
+	var repos []struct {
+		Name    string `json:"name"`
+		Storage struct {
+			BlobStoreName string `json:"blobStoreName"`
+		} `json:"storage"`
+	}
+	if err := json.Unmarshal(data, &repos); err != nil {
+		return nil, fmt.Errorf("failed to parse repository settings response: %w", err)
+	}
+
+	var names []string
+	for _, repo := range repos {
+		if repo.Storage.BlobStoreName == name {
+			names = append(names, repo.Name)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,162 @@
+package nexus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrCleanupPoliciesUnsupported Nexus 版本不提供清理策略 REST API
+var ErrCleanupPoliciesUnsupported = errors.New("this Nexus version does not expose the cleanup policy REST API; " +
+	"create the policies in the UI (Administration > Repository > Cleanup Policies) instead of enabling Groovy scripting")
+
+// ErrCleanupPreviewUnsupported Nexus 不提供清理预览使用的内部接口
+var ErrCleanupPreviewUnsupported = errors.New("cleanup preview relies on an internal Nexus API that is not available on this server; " +
+	"use the preview in the UI (Administration > Repository > Cleanup Policies) instead")
+
+// cleanupPreviewMinVersion 提供清理预览内部接口的最低 Nexus 版本
+const cleanupPreviewMinVersion = "3.29.0"
+
+// CleanupPolicyRequest 清理策略请求/响应
+type CleanupPolicyRequest struct {
+	Name                    string `json:"name"`
+	Notes                   string `json:"notes,omitempty"`
+	Format                  string `json:"format"`
+	CriteriaLastBlobUpdated *int   `json:"criteriaLastBlobUpdated,omitempty"`
+	CriteriaLastDownloaded  *int   `json:"criteriaLastDownloaded,omitempty"`
+	CriteriaReleaseType     string `json:"criteriaReleaseType,omitempty"`
+	CriteriaAssetRegex      string `json:"criteriaAssetRegex,omitempty"`
+	Retain                  int    `json:"retain,omitempty"`
+	SortBy                  string `json:"sortBy,omitempty"`
+}
+
+// CleanupPreviewComponent 清理预览中将被删除的组件
+type CleanupPreviewComponent struct {
+	Group   string `json:"group" yaml:"group"`
+	Name    string `json:"name" yaml:"name"`
+	Version string `json:"version" yaml:"version"`
+}
+
+// CleanupPreviewResult 清理预览结果
+type CleanupPreviewResult struct {
+	Total   int                       `json:"total" yaml:"total"`
+	Results []CleanupPreviewComponent `json:"results" yaml:"results"`
+}
+
+// cleanupError 将 404 转换为不支持清理策略 API 的错误
+func cleanupError(err error) error {
+	if strings.Contains(err.Error(), "status 404") {
+		return fmt.Errorf("%w: %v", ErrCleanupPoliciesUnsupported, err)
+	}
+	return err
+}
+
+// ListCleanupPolicies 列出所有清理策略
+func (c *Client) ListCleanupPolicies() ([]CleanupPolicyRequest, error) {
+	data, err := c.get("/service/rest/v1/cleanup-policies")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cleanup policies: %w", cleanupError(err))
+	}
+
+	var policies []CleanupPolicyRequest
+	if err := json.Unmarshal(data, &policies); err != nil {
+		return nil, fmt.Errorf("failed to parse cleanup policies response: %w", err)
+	}
+
+	return policies, nil
+}
+
+// GetCleanupPolicy 获取清理策略
+func (c *Client) GetCleanupPolicy(name string) (*CleanupPolicyRequest, error) {
+	policies, err := c.ListCleanupPolicies()
+	if err != nil {
+		return nil, err
+	}
+	for i := range policies {
+		if policies[i].Name == name {
+			return &policies[i], nil
+		}
+	}
+	return nil, fmt.Errorf("cleanup policy %s not found", name)
+}
+
+// CreateCleanupPolicy 创建清理策略
+func (c *Client) CreateCleanupPolicy(req CleanupPolicyRequest) error {
+	_, err := c.post("/service/rest/v1/cleanup-policies", req)
+	if err != nil {
+		return fmt.Errorf("failed to create cleanup policy %s: %w", req.Name, cleanupError(err))
+	}
+	return nil
+}
+
+// UpdateCleanupPolicy 更新清理策略
+func (c *Client) UpdateCleanupPolicy(name string, req CleanupPolicyRequest) error {
+	_, err := c.put(fmt.Sprintf("/service/rest/v1/cleanup-policies/%s", name), req)
+	if err != nil {
+		return fmt.Errorf("failed to update cleanup policy %s: %w", name, cleanupError(err))
+	}
+	return nil
+}
+
+// DeleteCleanupPolicy 删除清理策略
+func (c *Client) DeleteCleanupPolicy(name string) error {
+	_, err := c.delete(fmt.Sprintf("/service/rest/v1/cleanup-policies/%s", name))
+	if err != nil {
+		return fmt.Errorf("failed to delete cleanup policy %s: %w", name, cleanupError(err))
+	}
+	return nil
+}
+
+// PreviewCleanupPolicy 预览清理策略在指定仓库中将删除的组件（实验性）
+//
+// 预览使用 UI 的内部接口 /service/rest/internal/cleanup-policies/preview/components，
+// 该接口不属于公开 REST API，可能在 Nexus 升级后变化。Nexus 版本低于 cleanupPreviewMinVersion
+// 或接口不存在时返回 ErrCleanupPreviewUnsupported；无法获取版本时直接请求接口。
+func (c *Client) PreviewCleanupPolicy(repository string, policy CleanupPolicyRequest, limit int) (*CleanupPreviewResult, error) {
+	version, err := c.ServerVersion()
+	if err != nil {
+		return nil, err
+	}
+	if version != "" && !versionAtLeast(version, cleanupPreviewMinVersion) {
+		return nil, fmt.Errorf("%w (Nexus %s, requires %s or later)", ErrCleanupPreviewUnsupported, version, cleanupPreviewMinVersion)
+	}
+
+	query := url.Values{}
+	query.Set("repository", repository)
+	if policy.CriteriaLastBlobUpdated != nil {
+		query.Set("criteriaLastBlobUpdated", strconv.Itoa(*policy.CriteriaLastBlobUpdated))
+	}
+	if policy.CriteriaLastDownloaded != nil {
+		query.Set("criteriaLastDownloaded", strconv.Itoa(*policy.CriteriaLastDownloaded))
+	}
+	if policy.CriteriaReleaseType != "" {
+		query.Set("criteriaReleaseType", policy.CriteriaReleaseType)
+	}
+	if policy.CriteriaAssetRegex != "" {
+		query.Set("criteriaAssetRegex", policy.CriteriaAssetRegex)
+	}
+	if policy.Retain > 0 {
+		query.Set("retain", strconv.Itoa(policy.Retain))
+		query.Set("sortBy", policy.SortBy)
+	}
+	query.Set("start", "0")
+	query.Set("limit", strconv.Itoa(limit))
+
+	data, err := c.get("/service/rest/internal/cleanup-policies/preview/components?" + query.Encode())
+	if err != nil {
+		if strings.Contains(err.Error(), "status 404") || strings.Contains(err.Error(), "status 405") {
+			return nil, fmt.Errorf("failed to preview cleanup policy %s: %w: %v", policy.Name, ErrCleanupPreviewUnsupported, err)
+		}
+		return nil, fmt.Errorf("failed to preview cleanup policy %s: %w", policy.Name, err)
+	}
+
+	var result CleanupPreviewResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse cleanup preview response: %w", err)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,60 @@
+package nexus
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPreviewCleanupPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     string
+		hasPreview  bool
+		wantTotal   int
+		wantErr     bool
+		wantRequest bool
+	}{
+		{name: "supported version", version: "Nexus/3.61.0-02 (OSS)", hasPreview: true, wantTotal: 1, wantRequest: true},
+		{name: "version too old", version: "Nexus/3.28.1-01 (OSS)", hasPreview: true, wantErr: true},
+		{name: "unknown version", version: "", hasPreview: true, wantTotal: 1, wantRequest: true},
+		{name: "internal api removed", version: "Nexus/3.80.0-06 (OSS)", wantErr: true, wantRequest: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requested := false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.version != "" {
+					w.Header().Set("Server", tt.version)
+				}
+				if r.URL.Path != "/service/rest/internal/cleanup-policies/preview/components" {
+					return
+				}
+				requested = true
+				if !tt.hasPreview {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_, _ = w.Write([]byte(`{"total":1,"results":[{"group":"com.example","name":"app","version":"1.0-SNAPSHOT"}]}`))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "admin", "admin123")
+			result, err := client.PreviewCleanupPolicy("maven-snapshots", CleanupPolicyRequest{Name: "snapshots-30d", Format: "maven2"}, 10)
+			if requested != tt.wantRequest {
+				t.Errorf("preview requested = %v, want %v", requested, tt.wantRequest)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrCleanupPreviewUnsupported) {
+					t.Errorf("PreviewCleanupPolicy() error = %v, want ErrCleanupPreviewUnsupported", err)
+				}
+				return
+			}
+			if err != nil || result.Total != tt.wantTotal {
+				t.Errorf("PreviewCleanupPolicy() = %+v, %v, want total %d", result, err, tt.wantTotal)
+			}
+		})
+	}
+}
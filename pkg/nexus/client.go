@@ -3,10 +3,12 @@ package nexus
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -33,6 +35,11 @@ func NewClient(baseURL, username, password string) *Client {
 
 // doRequest 执行 HTTP 请求
 func (c *Client) doRequest(method, path string, body interface{}) ([]byte, error) {
+	return c.doRequestContext(context.Background(), method, path, body)
+}
+
+// doRequestContext 执行 HTTP 请求，ctx 取消时中止请求
+func (c *Client) doRequestContext(ctx context.Context, method, path string, body interface{}) ([]byte, error) {
 	var reqBody io.Reader
 	if body != nil {
 		jsonData, err := json.Marshal(body)
@@ -43,7 +50,7 @@ func (c *Client) doRequest(method, path string, body interface{}) ([]byte, error
 	}
 
 	url := c.baseURL + path
-	req, err := http.NewRequest(method, url, reqBody)
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -98,3 +105,70 @@ func (c *Client) CheckConnection() error {
 	}
 	return nil
 }
+
+// WithCredentials 返回以其他用户身份认证的客户端副本，共享底层 HTTP 客户端
+func (c *Client) WithCredentials(username, password string) *Client {
+	clone := *c
+	clone.username = username
+	clone.password = password
+	return &clone
+}
+
+// CheckAuthentication 检查客户端的用户名和密码能否通过 Nexus 认证
+//
+// /v1/status 允许匿名访问，因此请求需要认证的 /v1/status/check：
+// 401 表示认证失败，403 表示认证成功但没有查看系统状态的权限。
+func (c *Client) CheckAuthentication() (bool, error) {
+	_, err := c.get("/service/rest/v1/status/check")
+	if err == nil {
+		return true, nil
+	}
+	switch {
+	case strings.Contains(err.Error(), "status 401"):
+		return false, nil
+	case strings.Contains(err.Error(), "status 403"):
+		return true, nil
+	default:
+		return false, fmt.Errorf("failed to check authentication for %s: %w", c.username, err)
+	}
+}
+
+// ServerVersion 返回 Nexus 的版本号（例如 3.61.0-02）
+//
+// 版本号来自响应头 Server（例如 "Nexus/3.61.0-02 (OSS)"）；反向代理去掉该响应头时返回空字符串。
+func (c *Client) ServerVersion() (string, error) {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+"/service/rest/v1/status", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(c.username, c.password)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get Nexus version: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	server, ok := strings.CutPrefix(resp.Header.Get("Server"), "Nexus/")
+	if !ok {
+		return "", nil
+	}
+	version, _, _ := strings.Cut(server, " ")
+	return version, nil
+}
+
+// versionAtLeast 检查版本号（例如 3.61.0-02）是否不低于 minimum（例如 3.29.0），只比较数字部分
+func versionAtLeast(version, minimum string) bool {
+	parts := strings.Split(strings.SplitN(version, "-", 2)[0], ".")
+	for i, m := range strings.Split(minimum, ".") {
+		want, _ := strconv.Atoi(m)
+		got := 0
+		if i < len(parts) {
+			got, _ = strconv.Atoi(parts[i])
+		}
+		if got != want {
+			return got > want
+		}
+	}
+	return true
+}
@@ -1,6 +1,8 @@
 package nexus
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -53,3 +55,102 @@ func TestNewClient(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckAuthentication(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/service/rest/v1/status/check" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		user, pass, _ := r.BasicAuth()
+		switch {
+		case user == "admin" && pass == "admin123":
+			w.WriteHeader(http.StatusOK)
+		case user == "dev" && pass == "right":
+			w.WriteHeader(http.StatusForbidden)
+		case user == "broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	admin := NewClient(server.URL, "admin", "admin123")
+
+	tests := []struct {
+		name     string
+		username string
+		password string
+		want     bool
+		wantErr  bool
+	}{
+		{name: "privileged user", username: "admin", password: "admin123", want: true},
+		{name: "unprivileged user with correct password", username: "dev", password: "right", want: true},
+		{name: "wrong password", username: "dev", password: "wrong", want: false},
+		{name: "server error", username: "broken", password: "x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := admin.WithCredentials(tt.username, tt.password).CheckAuthentication()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckAuthentication() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("CheckAuthentication() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if admin.username != "admin" {
+		t.Errorf("WithCredentials() modified the original client, username = %s", admin.username)
+	}
+}
+
+func TestServerVersion(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "oss", header: "Nexus/3.61.0-02 (OSS)", want: "3.61.0-02"},
+		{name: "pro", header: "Nexus/3.29.2-02 (PRO)", want: "3.29.2-02"},
+		{name: "stripped by proxy", header: "nginx", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Server", tt.header)
+			}))
+			defer server.Close()
+
+			got, err := NewClient(server.URL, "admin", "admin123").ServerVersion()
+			if err != nil || got != tt.want {
+				t.Errorf("ServerVersion() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		version string
+		minimum string
+		want    bool
+	}{
+		{"3.29.0-02", "3.29.0", true},
+		{"3.61.0-02", "3.29.0", true},
+		{"3.28.1-01", "3.29.0", false},
+		{"3.9.0-01", "3.29.0", false},
+		{"4.0.0", "3.29.0", true},
+		{"3.29", "3.29.0", true},
+	}
+
+	for _, tt := range tests {
+		if got := versionAtLeast(tt.version, tt.minimum); got != tt.want {
+			t.Errorf("versionAtLeast(%q, %q) = %v, want %v", tt.version, tt.minimum, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package nexus
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// Component 组件
+type Component struct {
+	ID         string  `json:"id"`
+	Repository string  `json:"repository"`
+	Format     string  `json:"format"`
+	Group      string  `json:"group,omitempty"`
+	Name       string  `json:"name"`
+	Version    string  `json:"version,omitempty"`
+	Assets     []Asset `json:"assets,omitempty"`
+}
+
+// Asset 资产（组件中的单个文件）
+type Asset struct {
+	ID           string            `json:"id"`
+	Path         string            `json:"path"`
+	DownloadURL  string            `json:"downloadUrl"`
+	Repository   string            `json:"repository"`
+	Format       string            `json:"format"`
+	ContentType  string            `json:"contentType,omitempty"`
+	Checksum     map[string]string `json:"checksum,omitempty"`
+	FileSize     int64             `json:"fileSize,omitempty"`
+	LastModified string            `json:"lastModified,omitempty"`
+	BlobCreated  string            `json:"blobCreated,omitempty"`
+}
+
+// Components 遍历仓库中的组件
+func (c *Client) Components(ctx context.Context, repository string) *Iterator[Component] {
+	return newIterator[Component](ctx, c, "/service/rest/v1/components", url.Values{"repository": {repository}})
+}
+
+// Assets 遍历仓库中的资产
+func (c *Client) Assets(ctx context.Context, repository string) *Iterator[Asset] {
+	return newIterator[Asset](ctx, c, "/service/rest/v1/assets", url.Values{"repository": {repository}})
+}
+
+// SearchComponents 搜索组件，query 为 /v1/search 的查询参数，例如 maven.groupId、npm.scope、sort 和 direction
+func (c *Client) SearchComponents(ctx context.Context, query url.Values) *Iterator[Component] {
+	return newIterator[Component](ctx, c, "/service/rest/v1/search", query)
+}
+
+// SearchAssets 搜索资产，query 与 SearchComponents 相同
+func (c *Client) SearchAssets(ctx context.Context, query url.Values) *Iterator[Asset] {
+	return newIterator[Asset](ctx, c, "/service/rest/v1/search/assets", query)
+}
+
+// DeleteComponent 删除组件及其所有资产，组件不存在时返回包含 status 404 的错误
+func (c *Client) DeleteComponent(ctx context.Context, id string) error {
+	if _, err := c.doRequestContext(ctx, "DELETE", "/service/rest/v1/components/"+url.PathEscape(id), nil); err != nil {
+		return fmt.Errorf("failed to delete component %s: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package nexus
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// OpenAsset 下载仓库中的文件
+//
+// offset 大于 0 时通过 Range 请求从该位置继续下载，返回的 bool 表示服务器是否只返回了 offset 之后的内容；
+// 服务器不支持 Range 时返回完整文件。下载时间不受客户端默认超时限制，由 ctx 控制，调用方负责关闭返回的 body。
+func (c *Client) OpenAsset(ctx context.Context, repository, assetPath string, offset int64) (io.ReadCloser, bool, error) {
+	segments := strings.Split(strings.TrimPrefix(assetPath, "/"), "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	u := fmt.Sprintf("%s/repository/%s/%s", c.baseURL, url.PathEscape(repository), strings.Join(segments, "/"))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(c.username, c.password)
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	httpClient := *c.httpClient
+	httpClient.Timeout = 0
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to download %s: %w", assetPath, err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, false, nil
+	case http.StatusPartialContent:
+		return resp.Body, true, nil
+	default:
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, false, fmt.Errorf("failed to download %s: request failed with status %d: %s", assetPath, resp.StatusCode, string(body))
+	}
+}
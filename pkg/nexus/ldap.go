@@ -0,0 +1,122 @@
+package nexus
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// LDAPServerRequest LDAP 服务器请求/响应
+type LDAPServerRequest struct {
+	ID                          string `json:"id,omitempty"`
+	Order                       int    `json:"order,omitempty"`
+	Name                        string `json:"name"`
+	Protocol                    string `json:"protocol"`
+	UseTrustStore               bool   `json:"useTrustStore"`
+	Host                        string `json:"host"`
+	Port                        int    `json:"port"`
+	SearchBase                  string `json:"searchBase"`
+	AuthScheme                  string `json:"authScheme"`
+	AuthRealm                   string `json:"authRealm,omitempty"`
+	AuthUsername                string `json:"authUsername,omitempty"`
+	AuthPassword                string `json:"authPassword,omitempty"`
+	ConnectionTimeoutSeconds    int    `json:"connectionTimeoutSeconds"`
+	ConnectionRetryDelaySeconds int    `json:"connectionRetryDelaySeconds"`
+	MaxIncidentsCount           int    `json:"maxIncidentsCount"`
+	UserBaseDn                  string `json:"userBaseDn,omitempty"`
+	UserSubtree                 bool   `json:"userSubtree"`
+	UserObjectClass             string `json:"userObjectClass,omitempty"`
+	UserLdapFilter              string `json:"userLdapFilter,omitempty"`
+	UserIDAttribute             string `json:"userIdAttribute,omitempty"`
+	UserRealNameAttribute       string `json:"userRealNameAttribute,omitempty"`
+	UserEmailAddressAttribute   string `json:"userEmailAddressAttribute,omitempty"`
+	UserPasswordAttribute       string `json:"userPasswordAttribute,omitempty"`
+	LdapGroupsAsRoles           bool   `json:"ldapGroupsAsRoles"`
+	GroupType                   string `json:"groupType,omitempty"`
+	GroupBaseDn                 string `json:"groupBaseDn,omitempty"`
+	GroupSubtree                bool   `json:"groupSubtree"`
+	GroupObjectClass            string `json:"groupObjectClass,omitempty"`
+	GroupIDAttribute            string `json:"groupIdAttribute,omitempty"`
+	GroupMemberAttribute        string `json:"groupMemberAttribute,omitempty"`
+	GroupMemberFormat           string `json:"groupMemberFormat,omitempty"`
+	UserMemberOfAttribute       string `json:"userMemberOfAttribute,omitempty"`
+}
+
+// ListLDAPServers 列出所有 LDAP 服务器（按顺序）
+func (c *Client) ListLDAPServers() ([]LDAPServerRequest, error) {
+	data, err := c.get("/service/rest/v1/security/ldap")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list LDAP servers: %w", err)
+	}
+
+	var servers []LDAPServerRequest
+	if err := json.Unmarshal(data, &servers); err != nil {
+		return nil, fmt.Errorf("failed to parse LDAP servers response: %w", err)
+	}
+
+	return servers, nil
+}
+
+// GetLDAPServer 获取 LDAP 服务器配置
+func (c *Client) GetLDAPServer(name string) (*LDAPServerRequest, error) {
+	data, err := c.get(fmt.Sprintf("/service/rest/v1/security/ldap/%s", url.PathEscape(name)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get LDAP server %s: %w", name, err)
+	}
+
+	var server LDAPServerRequest
+	if err := json.Unmarshal(data, &server); err != nil {
+		return nil, fmt.Errorf("failed to parse LDAP server response: %w", err)
+	}
+
+	return &server, nil
+}
+
+// LDAPServerExists 检查 LDAP 服务器是否存在
+func (c *Client) LDAPServerExists(name string) (bool, error) {
+	_, err := c.GetLDAPServer(name)
+	if err != nil {
+		if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "not found") {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateLDAPServer 创建 LDAP 服务器
+func (c *Client) CreateLDAPServer(req LDAPServerRequest) error {
+	_, err := c.post("/service/rest/v1/security/ldap", req)
+	if err != nil {
+		return fmt.Errorf("failed to create LDAP server %s: %w", req.Name, err)
+	}
+	return nil
+}
+
+// UpdateLDAPServer 更新 LDAP 服务器
+func (c *Client) UpdateLDAPServer(name string, req LDAPServerRequest) error {
+	_, err := c.put(fmt.Sprintf("/service/rest/v1/security/ldap/%s", url.PathEscape(name)), req)
+	if err != nil {
+		return fmt.Errorf("failed to update LDAP server %s: %w", name, err)
+	}
+	return nil
+}
+
+// DeleteLDAPServer 删除 LDAP 服务器
+func (c *Client) DeleteLDAPServer(name string) error {
+	_, err := c.delete(fmt.Sprintf("/service/rest/v1/security/ldap/%s", url.PathEscape(name)))
+	if err != nil {
+		return fmt.Errorf("failed to delete LDAP server %s: %w", name, err)
+	}
+	return nil
+}
+
+// ChangeLDAPServerOrder 设置 LDAP 服务器的查询顺序
+func (c *Client) ChangeLDAPServerOrder(names []string) error {
+	_, err := c.post("/service/rest/v1/security/ldap/change-order", names)
+	if err != nil {
+		return fmt.Errorf("failed to change LDAP server order: %w", err)
+	}
+	return nil
+}
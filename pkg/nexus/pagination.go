@@ -0,0 +1,114 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// Page 分页接口返回的一页结果，continuationToken 为空表示没有下一页
+type Page[T any] struct {
+	Items             []T    `json:"items"`
+	ContinuationToken string `json:"continuationToken"`
+}
+
+// Iterator 逐项遍历分页接口的结果，在当前页用完时使用 continuationToken 请求下一页
+//
+// 用法与 bufio.Scanner 相同：
+//
+//	it := client.Components(ctx, "maven-releases")
+//	for it.Next() {
+//		c := it.Item()
+//	}
+//	if err := it.Err(); err != nil { ... }
+type Iterator[T any] struct {
+	ctx     context.Context
+	client  *Client
+	path    string
+	query   url.Values
+	items   []T
+	current T
+	token   string
+	started bool
+	err     error
+}
+
+// newIterator 创建分页迭代器，query 为除 continuationToken 以外的查询参数
+func newIterator[T any](ctx context.Context, client *Client, path string, query url.Values) *Iterator[T] {
+	if query == nil {
+		query = url.Values{}
+	}
+	return &Iterator[T]{ctx: ctx, client: client, path: path, query: query}
+}
+
+// Next 前进到下一项，没有更多结果或发生错误时返回 false
+func (it *Iterator[T]) Next() bool {
+	for len(it.items) == 0 {
+		if it.err != nil || (it.started && it.token == "") {
+			return false
+		}
+		if err := it.fetch(); err != nil {
+			it.err = err
+			return false
+		}
+	}
+	it.current, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// fetch 请求下一页
+func (it *Iterator[T]) fetch() error {
+	if err := it.ctx.Err(); err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	for k, v := range it.query {
+		query[k] = v
+	}
+	if it.token != "" {
+		query.Set("continuationToken", it.token)
+	}
+	path := it.path
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	data, err := it.client.doRequestContext(it.ctx, "GET", path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", it.path, err)
+	}
+	var page Page[T]
+	if err := json.Unmarshal(data, &page); err != nil {
+		return fmt.Errorf("failed to parse %s response: %w", it.path, err)
+	}
+
+	// 防止服务器返回相同的 token 导致死循环
+	if it.started && page.ContinuationToken != "" && page.ContinuationToken == it.token {
+		return fmt.Errorf("failed to list %s: server returned the same continuation token twice", it.path)
+	}
+	it.started = true
+	it.items = page.Items
+	it.token = page.ContinuationToken
+	return nil
+}
+
+// Item 返回当前项
+func (it *Iterator[T]) Item() T {
+	return it.current
+}
+
+// Err 返回遍历过程中发生的错误
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// All 遍历剩余的所有项
+func (it *Iterator[T]) All() ([]T, error) {
+	var items []T
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
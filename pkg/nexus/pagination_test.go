@@ -0,0 +1,70 @@
+package nexus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIterator(t *testing.T) {
+	pages := map[string]string{
+		"":   `{"items":[{"id":"1","name":"a"},{"id":"2","name":"b"}],"continuationToken":"t1"}`,
+		"t1": `{"items":[],"continuationToken":"t2"}`,
+		"t2": `{"items":[{"id":"3","name":"c"}],"continuationToken":null}`,
+		"t3": `{"items":[{"id":"4","name":"d"}],"continuationToken":"t3"}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("repository") != "maven-releases" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, ok := pages[r.URL.Query().Get("continuationToken")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "admin", "admin123")
+
+	t.Run("follows continuation tokens", func(t *testing.T) {
+		components, err := client.Components(context.Background(), "maven-releases").All()
+		if err != nil {
+			t.Fatalf("All() error = %v", err)
+		}
+		var names []string
+		for _, c := range components {
+			names = append(names, c.Name)
+		}
+		if fmt.Sprint(names) != "[a b c]" {
+			t.Errorf("component names = %v, want [a b c]", names)
+		}
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		it := client.Components(ctx, "maven-releases")
+		if !it.Next() {
+			t.Fatalf("Next() = false, error = %v", it.Err())
+		}
+		cancel()
+		// 当前页剩余的项仍然返回，请求下一页时停止
+		for it.Next() {
+		}
+		if it.Err() != context.Canceled {
+			t.Errorf("Err() = %v, want context.Canceled", it.Err())
+		}
+	})
+
+	t.Run("repeated token", func(t *testing.T) {
+		it := newIterator[Component](context.Background(), client, "/service/rest/v1/components", nil)
+		it.query.Set("repository", "maven-releases")
+		it.token, it.started = "t3", true
+		if _, err := it.All(); err == nil {
+			t.Error("All() expected error for a repeated continuation token")
+		}
+	})
+}
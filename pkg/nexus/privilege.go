@@ -9,54 +9,71 @@ import (
 
 // PrivilegeRequest 权限请求
 type PrivilegeRequest struct {
-	Name        string   `json:"name"`
-	Description string   `json:"description"`
-	Type        string   `json:"type"`
-	Format      string   `json:"format,omitempty"`
-	Repository  string   `json:"repository,omitempty"`
-	Actions     []string `json:"actions,omitempty"`
-	Pattern     string   `json:"pattern,omitempty"`
-	Domain      string   `json:"domain,omitempty"`
+	Name            string   `json:"name"`
+	Description     string   `json:"description"`
+	Type            string   `json:"type"`
+	Format          string   `json:"format,omitempty"`
+	Repository      string   `json:"repository,omitempty"`
+	Actions         []string `json:"actions,omitempty"`
+	Pattern         string   `json:"pattern,omitempty"`
+	Domain          string   `json:"domain,omitempty"`
+	ContentSelector string   `json:"contentSelector,omitempty"`
+	ScriptName      string   `json:"scriptName,omitempty"`
 }
 
 // PrivilegeResponse 权限响应
 type PrivilegeResponse struct {
-	Name        string   `json:"name"`
-	Description string   `json:"description"`
-	Type        string   `json:"type"`
-	Format      string   `json:"format,omitempty"`
-	Repository  string   `json:"repository,omitempty"`
-	Actions     []string `json:"actions,omitempty"`
-	ReadOnly    bool     `json:"readOnly"`
+	Name            string   `json:"name"`
+	Description     string   `json:"description"`
+	Type            string   `json:"type"`
+	Format          string   `json:"format,omitempty"`
+	Repository      string   `json:"repository,omitempty"`
+	Actions         []string `json:"actions,omitempty"`
+	Pattern         string   `json:"pattern,omitempty"`
+	Domain          string   `json:"domain,omitempty"`
+	ContentSelector string   `json:"contentSelector,omitempty"`
+	ScriptName      string   `json:"scriptName,omitempty"`
+	ReadOnly        bool     `json:"readOnly"`
+}
+
+// privilegePath 返回指定权限类型的 API 路径
+func privilegePath(privType string) (string, error) {
+	switch privType {
+	case "repository-view", "repository-admin", "repository-content-selector", "script", "application", "wildcard":
+		return "/service/rest/v1/security/privileges/" + privType, nil
+	default:
+		return "", fmt.Errorf("unsupported privilege type: %s", privType)
+	}
 }
 
 // CreatePrivilege 创建权限
 func (c *Client) CreatePrivilege(req PrivilegeRequest) error {
-	var path string
-	switch req.Type {
-	case "repository-view":
-		path = "/service/rest/v1/security/privileges/repository-view"
-	case "repository-admin":
-		path = "/service/rest/v1/security/privileges/repository-admin"
-	case "repository-content-selector":
-		path = "/service/rest/v1/security/privileges/repository-content-selector"
-	case "script":
-		path = "/service/rest/v1/security/privileges/script"
-	case "application":
-		path = "/service/rest/v1/security/privileges/application"
-	case "wildcard":
-		path = "/service/rest/v1/security/privileges/wildcard"
-	default:
-		return fmt.Errorf("unsupported privilege type: %s", req.Type)
+	path, err := privilegePath(req.Type)
+	if err != nil {
+		return err
 	}
 
-	_, err := c.post(path, req)
+	_, err = c.post(path, req)
 	if err != nil {
 		return fmt.Errorf("failed to create privilege %s: %w", req.Name, err)
 	}
 	return nil
 }
 
+// UpdatePrivilege 更新权限（类型不可修改）
+func (c *Client) UpdatePrivilege(name string, req PrivilegeRequest) error {
+	path, err := privilegePath(req.Type)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.put(fmt.Sprintf("%s/%s", path, name), req)
+	if err != nil {
+		return fmt.Errorf("failed to update privilege %s: %w", name, err)
+	}
+	return nil
+}
+
 // GetPrivilege 获取权限信息
 func (c *Client) GetPrivilege(name string) (*PrivilegeResponse, error) {
 	data, err := c.get(fmt.Sprintf("/service/rest/v1/security/privileges/%s", name))
@@ -17,7 +17,9 @@ type RepositoryRequest struct {
 	HTTPClient    *HTTPClientSettings    `json:"httpClient,omitempty"`
 	Maven         *MavenSettings         `json:"maven,omitempty"`
 	Docker        *DockerSettings        `json:"docker,omitempty"`
+	DockerProxy   *DockerProxySettings   `json:"dockerProxy,omitempty"`
 	Apt           *AptSettings           `json:"apt,omitempty"`
+	RoutingRule   string                 `json:"routingRule,omitempty"`
 }
 
 // CleanupPolicy 清理策略
@@ -27,10 +29,9 @@ type CleanupPolicy struct {
 
 // ProxySettings 代理设置
 type ProxySettings struct {
-	RemoteURL      string          `json:"remoteUrl"`
-	ContentMaxAge  int             `json:"contentMaxAge"`
-	MetadataMaxAge int             `json:"metadataMaxAge"`
-	Authentication *Authentication `json:"authentication,omitempty"`
+	RemoteURL      string `json:"remoteUrl"`
+	ContentMaxAge  int    `json:"contentMaxAge"`
+	MetadataMaxAge int    `json:"metadataMaxAge"`
 }
 
 // Authentication 认证信息
@@ -50,15 +51,20 @@ type NegativeCacheSettings struct {
 
 // HTTPClientSettings HTTP客户端设置
 type HTTPClientSettings struct {
-	Blocked    bool                  `json:"blocked"`
-	AutoBlock  bool                  `json:"autoBlock"`
-	Connection *HTTPClientConnection `json:"connection,omitempty"`
+	Blocked        bool                  `json:"blocked"`
+	AutoBlock      bool                  `json:"autoBlock"`
+	Connection     *HTTPClientConnection `json:"connection,omitempty"`
+	Authentication *Authentication       `json:"authentication,omitempty"`
 }
 
 // HTTPClientConnection HTTP客户端连接设置
 type HTTPClientConnection struct {
-	RetryAttempts int `json:"retries,omitempty"`
-	Timeout       int `json:"timeout,omitempty"`
+	RetryAttempts           *int   `json:"retries,omitempty"`
+	Timeout                 *int   `json:"timeout,omitempty"`
+	UserAgentSuffix         string `json:"userAgentSuffix,omitempty"`
+	EnableCircularRedirects bool   `json:"enableCircularRedirects"`
+	EnableCookies           bool   `json:"enableCookies"`
+	UseTrustStore           bool   `json:"useTrustStore"`
 }
 
 // MavenSettings Maven 设置
@@ -73,7 +79,16 @@ type DockerSettings struct {
 	HTTPSPort      *int   `json:"httpsPort,omitempty"`
 	ForceBasicAuth bool   `json:"forceBasicAuth"`
 	V1Enabled      bool   `json:"v1Enabled"`
-	SubdomainAddr  string `json:"subdomain,omitempty"`
+	Subdomain      string `json:"subdomain,omitempty"`
+	PathEnabled    *bool  `json:"pathEnabled,omitempty"`
+}
+
+// DockerProxySettings Docker 代理索引设置
+type DockerProxySettings struct {
+	IndexType                string   `json:"indexType"`
+	IndexURL                 string   `json:"indexUrl,omitempty"`
+	CacheForeignLayers       bool     `json:"cacheForeignLayers"`
+	ForeignLayerURLWhitelist []string `json:"foreignLayerUrlWhitelist,omitempty"`
 }
 
 // AptSettings Apt 设置
@@ -82,6 +97,82 @@ type AptSettings struct {
 	Flat         bool   `json:"flat,omitempty"`
 }
 
+// RepositorySummary 仓库列表项
+type RepositorySummary struct {
+	Name   string `json:"name"`
+	Format string `json:"format"`
+	Type   string `json:"type"`
+	URL    string `json:"url"`
+}
+
+// Repository 仓库的完整配置，由 format 和 type 对应的 GET 接口返回，不适用的设置为 nil
+type Repository struct {
+	Name          string                 `json:"name"`
+	Format        string                 `json:"format"`
+	Type          string                 `json:"type"`
+	URL           string                 `json:"url"`
+	Online        bool                   `json:"online"`
+	Storage       *RepositoryStorage     `json:"storage,omitempty"`
+	Cleanup       *CleanupPolicy         `json:"cleanup,omitempty"`
+	Component     *ComponentSettings     `json:"component,omitempty"`
+	Proxy         *ProxySettings         `json:"proxy,omitempty"`
+	NegativeCache *NegativeCacheSettings `json:"negativeCache,omitempty"`
+	HTTPClient    *HTTPClientSettings    `json:"httpClient,omitempty"`
+	RoutingRule   string                 `json:"routingRuleName,omitempty"`
+	Group         *GroupSettings         `json:"group,omitempty"`
+	Maven         *MavenSettings         `json:"maven,omitempty"`
+	Docker        *DockerSettings        `json:"docker,omitempty"`
+	DockerProxy   *DockerProxySettings   `json:"dockerProxy,omitempty"`
+	Apt           *AptSettings           `json:"apt,omitempty"`
+	Npm           *NpmSettings           `json:"npm,omitempty"`
+	Raw           *RawSettings           `json:"raw,omitempty"`
+	Yum           *YumSettings           `json:"yum,omitempty"`
+}
+
+// RepositoryStorage 仓库存储设置，writePolicy 只有 hosted 仓库才有
+type RepositoryStorage struct {
+	BlobStoreName               string `json:"blobStoreName"`
+	StrictContentTypeValidation bool   `json:"strictContentTypeValidation"`
+	WritePolicy                 string `json:"writePolicy,omitempty"`
+}
+
+// ComponentSettings hosted 仓库的组件设置
+type ComponentSettings struct {
+	ProprietaryComponents bool `json:"proprietaryComponents"`
+}
+
+// GroupSettings group 仓库的成员设置
+type GroupSettings struct {
+	MemberNames []string `json:"memberNames"`
+	// WritableMember 可写成员，只有 docker 和 npm group 支持
+	WritableMember string `json:"writableMember,omitempty"`
+}
+
+// NpmSettings npm proxy 设置
+type NpmSettings struct {
+	RemoveNonCataloged bool `json:"removeNonCataloged"`
+	RemoveQuarantined  bool `json:"removeQuarantined"`
+}
+
+// RawSettings raw 仓库设置
+type RawSettings struct {
+	ContentDisposition string `json:"contentDisposition,omitempty"`
+}
+
+// YumSettings yum hosted 设置
+type YumSettings struct {
+	RepodataDepth int    `json:"repodataDepth"`
+	DeployPolicy  string `json:"deployPolicy,omitempty"`
+}
+
+// repositoryAPIFormat 返回 format 在仓库管理接口路径中的名称
+func repositoryAPIFormat(format string) string {
+	if format == "maven2" {
+		return "maven"
+	}
+	return format
+}
+
 // CreateMavenHostedRepository 创建 Maven hosted 仓库
 func (c *Client) CreateMavenHostedRepository(req RepositoryRequest) error {
 	_, err := c.post("/service/rest/v1/repositories/maven/hosted", req)
@@ -163,19 +254,54 @@ func (c *Client) CreateNpmGroupRepository(req RepositoryRequest) error {
 	return nil
 }
 
-// GetRepository 获取仓库信息
-func (c *Client) GetRepository(name string) (map[string]interface{}, error) {
+// GetRepository 获取仓库的完整配置
+//
+// 先通过通用接口获取 format 和 type，再从对应的 /v1/repositories/{format}/{type}/{name} 接口读取设置。
+// 该接口不支持的 format（或旧版本 Nexus）只返回基本信息。
+func (c *Client) GetRepository(name string) (*Repository, error) {
+	summary, err := c.getRepositorySummary(name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := c.get(fmt.Sprintf("/service/rest/v1/repositories/%s/%s/%s", repositoryAPIFormat(summary.Format), summary.Type, name))
+	if err != nil {
+		if strings.Contains(err.Error(), "status 404") || strings.Contains(err.Error(), "status 405") {
+			return &Repository{Name: summary.Name, Format: summary.Format, Type: summary.Type, URL: summary.URL}, nil
+		}
+		return nil, fmt.Errorf("failed to get repository %s: %w", name, err)
+	}
+
+	var repo Repository
+	if err := json.Unmarshal(data, &repo); err != nil {
+		return nil, fmt.Errorf("failed to parse repository response: %w", err)
+	}
+	// 格式相关接口返回的 format 与通用接口一致（例如 maven2），为空时以通用接口为准
+	if repo.Format == "" {
+		repo.Format = summary.Format
+	}
+	if repo.Type == "" {
+		repo.Type = summary.Type
+	}
+	if repo.URL == "" {
+		repo.URL = summary.URL
+	}
+	return &repo, nil
+}
+
+// getRepositorySummary 通过通用接口获取仓库的基本信息
+func (c *Client) getRepositorySummary(name string) (*RepositorySummary, error) {
 	data, err := c.get(fmt.Sprintf("/service/rest/v1/repositories/%s", name))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get repository %s: %w", name, err)
 	}
 
-	var repo map[string]interface{}
+	var repo RepositorySummary
 	if err := json.Unmarshal(data, &repo); err != nil {
 		return nil, fmt.Errorf("failed to parse repository response: %w", err)
 	}
 
-	return repo, nil
+	return &repo, nil
 }
 
 // DeleteRepository 删除仓库
@@ -189,7 +315,7 @@ func (c *Client) DeleteRepository(name string) error {
 
 // RepositoryExists 检查仓库是否存在
 func (c *Client) RepositoryExists(name string) (bool, error) {
-	_, err := c.GetRepository(name)
+	_, err := c.getRepositorySummary(name)
 	if err != nil {
 		// 404 错误表示仓库不存在
 		if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "not found") {
@@ -201,13 +327,13 @@ func (c *Client) RepositoryExists(name string) (bool, error) {
 }
 
 // ListRepositories 列出所有仓库
-func (c *Client) ListRepositories() ([]map[string]interface{}, error) {
+func (c *Client) ListRepositories() ([]RepositorySummary, error) {
 	data, err := c.get("/service/rest/v1/repositories")
 	if err != nil {
 		return nil, fmt.Errorf("failed to list repositories: %w", err)
 	}
 
-	var repos []map[string]interface{}
+	var repos []RepositorySummary
 	if err := json.Unmarshal(data, &repos); err != nil {
 		return nil, fmt.Errorf("failed to parse repositories response: %w", err)
 	}
@@ -259,3 +385,99 @@ func (c *Client) CreateGoGroupRepository(req RepositoryRequest) error {
 	}
 	return nil
 }
+
+// SetRepositoryOnline 设置仓库的在线状态，返回状态是否发生变化
+//
+// Nexus 没有单独的上线/下线接口，需要读取完整配置后修改 online 再更新，见 updateRepositorySettings。
+func (c *Client) SetRepositoryOnline(name string, online bool, password string) (bool, error) {
+	return c.updateRepositorySettings(name, password, func(settings map[string]interface{}) bool {
+		if current, _ := settings["online"].(bool); current == online {
+			return false
+		}
+		settings["online"] = online
+		return true
+	})
+}
+
+// SetRepositoryRoutingRule 设置 proxy 或 group 仓库使用的路由规则，返回是否发生变化
+func (c *Client) SetRepositoryRoutingRule(name, rule, password string) (bool, error) {
+	return c.updateRepositorySettings(name, password, func(settings map[string]interface{}) bool {
+		if current, _ := settings["routingRule"].(string); current == rule {
+			return false
+		}
+		settings["routingRule"] = rule
+		return true
+	})
+}
+
+// updateRepositorySettings 读取仓库的完整配置，由 update 修改后写回（PUT /v1/repositories/{format}/{type}/{name}）
+//
+// 配置按原始 JSON 读写，只修改 update 改动的字段，避免丢失 Repository 未建模的字段（例如 aptSigning、storage.latestPolicy）。
+// update 返回 false 时不更新。GET 返回的 routingRuleName 在请求中为 routingRule，传给 update 前已改名。
+// Nexus 不返回上游认证的密码，仓库配置了上游认证时需要通过 password 提供，否则返回错误以免清除密码。
+func (c *Client) updateRepositorySettings(name, password string, update func(settings map[string]interface{}) bool) (bool, error) {
+	summary, err := c.getRepositorySummary(name)
+	if err != nil {
+		return false, err
+	}
+	path := fmt.Sprintf("/service/rest/v1/repositories/%s/%s/%s", repositoryAPIFormat(summary.Format), summary.Type, name)
+	data, err := c.get(path)
+	if err != nil {
+		if strings.Contains(err.Error(), "status 404") || strings.Contains(err.Error(), "status 405") {
+			return false, fmt.Errorf("failed to update repository %s: Nexus does not return the settings of %s repositories", name, summary.Format)
+		}
+		return false, fmt.Errorf("failed to get repository %s: %w", name, err)
+	}
+
+	var settings map[string]interface{}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return false, fmt.Errorf("failed to parse repository response: %w", err)
+	}
+	if rule, ok := settings["routingRuleName"]; ok {
+		delete(settings, "routingRuleName")
+		if rule != nil {
+			settings["routingRule"] = rule
+		}
+	}
+	if !update(settings) {
+		return false, nil
+	}
+
+	if httpClient, ok := settings["httpClient"].(map[string]interface{}); ok {
+		if auth, ok := httpClient["authentication"].(map[string]interface{}); ok {
+			if password == "" {
+				return false, fmt.Errorf("failed to update repository %s: the upstream authentication password is not returned by Nexus and would be cleared", name)
+			}
+			auth["password"] = password
+		}
+	}
+
+	if _, err := c.put(path, settings); err != nil {
+		return false, fmt.Errorf("failed to update repository %s: %w", name, err)
+	}
+	return true, nil
+}
+
+// InvalidateRepositoryCache 使 proxy 或 group 仓库的缓存失效，下次请求时重新从上游获取
+func (c *Client) InvalidateRepositoryCache(name string) error {
+	_, err := c.post(fmt.Sprintf("/service/rest/v1/repositories/%s/invalidate-cache", name), nil)
+	if err != nil {
+		if strings.Contains(err.Error(), "status 400") {
+			return fmt.Errorf("failed to invalidate cache of repository %s: only proxy and group repositories have a cache", name)
+		}
+		return fmt.Errorf("failed to invalidate cache of repository %s: %w", name, err)
+	}
+	return nil
+}
+
+// RebuildRepositoryIndex 重建仓库的搜索索引
+func (c *Client) RebuildRepositoryIndex(name string) error {
+	_, err := c.post(fmt.Sprintf("/service/rest/v1/repositories/%s/rebuild-index", name), nil)
+	if err != nil {
+		if strings.Contains(err.Error(), "status 409") {
+			return fmt.Errorf("failed to rebuild index of repository %s: a rebuild is already in progress", name)
+		}
+		return fmt.Errorf("failed to rebuild index of repository %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package nexus
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetRepository(t *testing.T) {
+	responses := map[string]string{
+		"/service/rest/v1/repositories/maven-central": `{"name":"maven-central","format":"maven2","type":"proxy","url":"http://nexus/repository/maven-central"}`,
+		"/service/rest/v1/repositories/maven/proxy/maven-central": `{
+			"name":"maven-central","format":"maven2","type":"proxy","url":"http://nexus/repository/maven-central","online":true,
+			"storage":{"blobStoreName":"default","strictContentTypeValidation":true},
+			"proxy":{"remoteUrl":"https://repo1.maven.org/maven2/","contentMaxAge":-1,"metadataMaxAge":1440},
+			"negativeCache":{"enabled":true,"timeToLive":1440},
+			"routingRuleName":"block-internal",
+			"maven":{"versionPolicy":"RELEASE","layoutPolicy":"STRICT"}}`,
+		"/service/rest/v1/repositories/npm-all":           `{"name":"npm-all","format":"npm","type":"group","url":"http://nexus/repository/npm-all"}`,
+		"/service/rest/v1/repositories/npm/group/npm-all": `{"name":"npm-all","online":false,"storage":{"blobStoreName":"npm"},"group":{"memberNames":["npm-hosted","npm-proxy"],"writableMember":"npm-hosted"}}`,
+		"/service/rest/v1/repositories/legacy":            `{"name":"legacy","format":"bower","type":"hosted","url":"http://nexus/repository/legacy"}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "admin", "admin123")
+
+	proxy, err := client.GetRepository("maven-central")
+	if err != nil {
+		t.Fatalf("GetRepository(maven-central) error = %v", err)
+	}
+	if !proxy.Online || proxy.Proxy == nil || proxy.Proxy.RemoteURL != "https://repo1.maven.org/maven2/" ||
+		proxy.Maven == nil || proxy.Maven.VersionPolicy != "RELEASE" || proxy.RoutingRule != "block-internal" ||
+		proxy.Storage == nil || proxy.Storage.BlobStoreName != "default" {
+		t.Errorf("GetRepository(maven-central) = %+v, want maven proxy settings", proxy)
+	}
+
+	group, err := client.GetRepository("npm-all")
+	if err != nil {
+		t.Fatalf("GetRepository(npm-all) error = %v", err)
+	}
+	if group.Format != "npm" || group.Type != "group" || group.URL == "" {
+		t.Errorf("GetRepository(npm-all) = %+v, want format, type and url from the summary", group)
+	}
+	if group.Group == nil || len(group.Group.MemberNames) != 2 || group.Group.WritableMember != "npm-hosted" {
+		t.Errorf("GetRepository(npm-all).Group = %+v, want two members", group.Group)
+	}
+
+	legacy, err := client.GetRepository("legacy")
+	if err != nil {
+		t.Fatalf("GetRepository(legacy) error = %v", err)
+	}
+	if legacy.Format != "bower" || legacy.Storage != nil {
+		t.Errorf("GetRepository(legacy) = %+v, want summary only", legacy)
+	}
+
+	if _, err := client.GetRepository("missing"); err == nil {
+		t.Error("GetRepository(missing) expected error")
+	}
+}
+
+func TestSetRepositoryOnline(t *testing.T) {
+	responses := map[string]string{
+		"/service/rest/v1/repositories/npm-proxy": `{"name":"npm-proxy","format":"npm","type":"proxy"}`,
+		"/service/rest/v1/repositories/npm/proxy/npm-proxy": `{"name":"npm-proxy","format":"npm","type":"proxy","online":true,
+			"storage":{"blobStoreName":"default"},"proxy":{"remoteUrl":"https://registry.npmjs.org"},"routingRuleName":"block-internal"}`,
+		"/service/rest/v1/repositories/apt-hosted": `{"name":"apt-hosted","format":"apt","type":"hosted"}`,
+		"/service/rest/v1/repositories/apt/hosted/apt-hosted": `{"name":"apt-hosted","online":true,
+			"storage":{"blobStoreName":"default","latestPolicy":true},"apt":{"distribution":"bionic"},"aptSigning":{"keypair":"KEY"}}`,
+		"/service/rest/v1/repositories/private-proxy": `{"name":"private-proxy","format":"npm","type":"proxy"}`,
+		"/service/rest/v1/repositories/npm/proxy/private-proxy": `{"name":"private-proxy","online":true,"storage":{"blobStoreName":"default"},
+			"httpClient":{"authentication":{"type":"username","username":"ci"}}}`,
+	}
+	var updates []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			updates = append(updates, body)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "admin", "admin123")
+
+	changed, err := client.SetRepositoryOnline("npm-proxy", false, "")
+	if err != nil || !changed {
+		t.Fatalf("SetRepositoryOnline(npm-proxy, false) = %v, %v", changed, err)
+	}
+	if len(updates) != 1 || updates[0]["online"] != false || updates[0]["routingRule"] != "block-internal" {
+		t.Errorf("update request = %v, want online false and the routing rule", updates)
+	}
+
+	if changed, err := client.SetRepositoryOnline("npm-proxy", true, ""); err != nil || changed {
+		t.Errorf("SetRepositoryOnline(npm-proxy, true) = %v, %v, want no change", changed, err)
+	}
+	if _, err := client.SetRepositoryOnline("private-proxy", false, ""); err == nil || len(updates) != 1 {
+		t.Errorf("SetRepositoryOnline(private-proxy) error = %v, want an error without clearing the password", err)
+	}
+	if _, err := client.SetRepositoryOnline("private-proxy", false, "secret"); err != nil || len(updates) != 2 {
+		t.Fatalf("SetRepositoryOnline(private-proxy, secret) error = %v", err)
+	}
+	if auth := updates[1]["httpClient"].(map[string]interface{})["authentication"].(map[string]interface{}); auth["password"] != "secret" || auth["username"] != "ci" {
+		t.Errorf("authentication = %v, want the username kept and the password supplied", auth)
+	}
+
+	// Repository 未建模的字段原样提交
+	if _, err := client.SetRepositoryOnline("apt-hosted", false, ""); err != nil || len(updates) != 3 {
+		t.Fatalf("SetRepositoryOnline(apt-hosted) error = %v", err)
+	}
+	storage, _ := updates[2]["storage"].(map[string]interface{})
+	signing, _ := updates[2]["aptSigning"].(map[string]interface{})
+	if storage["latestPolicy"] != true || signing["keypair"] != "KEY" || updates[2]["online"] != false {
+		t.Errorf("update request = %v, want unknown fields kept", updates[2])
+	}
+}
+
+func TestSetRepositoryRoutingRule(t *testing.T) {
+	responses := map[string]string{
+		"/service/rest/v1/repositories/npm-proxy": `{"name":"npm-proxy","format":"npm","type":"proxy"}`,
+		"/service/rest/v1/repositories/npm/proxy/npm-proxy": `{"name":"npm-proxy","format":"npm","type":"proxy","online":true,
+			"storage":{"blobStoreName":"default"},"proxy":{"remoteUrl":"https://registry.npmjs.org"},"routingRuleName":null}`,
+		"/service/rest/v1/repositories/npm-group": `{"name":"npm-group","format":"npm","type":"group"}`,
+		"/service/rest/v1/repositories/npm/group/npm-group": `{"name":"npm-group","format":"npm","type":"group","online":true,
+			"storage":{"blobStoreName":"default"},"group":{"memberNames":["npm-proxy"]},"routingRuleName":"block-internal"}`,
+	}
+	var updates []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			updates = append(updates, body)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "admin", "admin123")
+
+	changed, err := client.SetRepositoryRoutingRule("npm-proxy", "block-internal", "")
+	if err != nil || !changed {
+		t.Fatalf("SetRepositoryRoutingRule(npm-proxy) = %v, %v", changed, err)
+	}
+	if len(updates) != 1 || updates[0]["routingRule"] != "block-internal" || updates[0]["online"] != true {
+		t.Errorf("update request = %v, want the routing rule set and online kept", updates)
+	}
+	if _, ok := updates[0]["routingRuleName"]; ok {
+		t.Errorf("update request = %v, want routingRuleName renamed", updates[0])
+	}
+
+	if changed, err := client.SetRepositoryRoutingRule("npm-group", "block-internal", ""); err != nil || changed {
+		t.Errorf("SetRepositoryRoutingRule(npm-group) = %v, %v, want no change", changed, err)
+	}
+	if changed, err := client.SetRepositoryRoutingRule("npm-group", "allow-public", ""); err != nil || !changed {
+		t.Fatalf("SetRepositoryRoutingRule(npm-group, allow-public) = %v, %v", changed, err)
+	}
+	if len(updates) != 2 || updates[1]["routingRule"] != "allow-public" {
+		t.Errorf("update request = %v, want the routing rule replaced", updates)
+	}
+}
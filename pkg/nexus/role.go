@@ -3,6 +3,7 @@ package nexus
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"strings"
 )
 
@@ -95,3 +96,18 @@ func (c *Client) ListRoles() ([]RoleResponse, error) {
 
 	return roles, nil
 }
+
+// ListRolesBySource 列出指定来源的角色，例如 LDAP 来源返回可映射的 LDAP 组
+func (c *Client) ListRolesBySource(source string) ([]RoleResponse, error) {
+	data, err := c.get("/service/rest/v1/security/roles?source=" + url.QueryEscape(source))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s roles: %w", source, err)
+	}
+
+	var roles []RoleResponse
+	if err := json.Unmarshal(data, &roles); err != nil {
+		return nil, fmt.Errorf("failed to parse roles response: %w", err)
+	}
+
+	return roles, nil
+}
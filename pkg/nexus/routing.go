@@ -0,0 +1,84 @@
+package nexus
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// RoutingRuleRequest 路由规则请求/响应
+type RoutingRuleRequest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Mode        string   `json:"mode"`
+	Matchers    []string `json:"matchers"`
+}
+
+// CreateRoutingRule 创建路由规则
+func (c *Client) CreateRoutingRule(req RoutingRuleRequest) error {
+	_, err := c.post("/service/rest/v1/routing-rules", req)
+	if err != nil {
+		return fmt.Errorf("failed to create routing rule %s: %w", req.Name, err)
+	}
+	return nil
+}
+
+// GetRoutingRule 获取路由规则
+func (c *Client) GetRoutingRule(name string) (*RoutingRuleRequest, error) {
+	data, err := c.get(fmt.Sprintf("/service/rest/v1/routing-rules/%s", name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get routing rule %s: %w", name, err)
+	}
+
+	var rule RoutingRuleRequest
+	if err := json.Unmarshal(data, &rule); err != nil {
+		return nil, fmt.Errorf("failed to parse routing rule response: %w", err)
+	}
+
+	return &rule, nil
+}
+
+// UpdateRoutingRule 更新路由规则
+func (c *Client) UpdateRoutingRule(name string, req RoutingRuleRequest) error {
+	_, err := c.put(fmt.Sprintf("/service/rest/v1/routing-rules/%s", name), req)
+	if err != nil {
+		return fmt.Errorf("failed to update routing rule %s: %w", name, err)
+	}
+	return nil
+}
+
+// DeleteRoutingRule 删除路由规则
+func (c *Client) DeleteRoutingRule(name string) error {
+	_, err := c.delete(fmt.Sprintf("/service/rest/v1/routing-rules/%s", name))
+	if err != nil {
+		return fmt.Errorf("failed to delete routing rule %s: %w", name, err)
+	}
+	return nil
+}
+
+// RoutingRuleExists 检查路由规则是否存在
+func (c *Client) RoutingRuleExists(name string) (bool, error) {
+	_, err := c.GetRoutingRule(name)
+	if err != nil {
+		if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "not found") {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// ListRoutingRules 列出所有路由规则
+func (c *Client) ListRoutingRules() ([]RoutingRuleRequest, error) {
+	data, err := c.get("/service/rest/v1/routing-rules")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list routing rules: %w", err)
+	}
+
+	var rules []RoutingRuleRequest
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse routing rules response: %w", err)
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,114 @@
+package nexus
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// RealmResponse 可用的安全域
+type RealmResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// AnonymousSettings 匿名访问设置
+type AnonymousSettings struct {
+	Enabled   bool   `json:"enabled"`
+	UserID    string `json:"userId"`
+	RealmName string `json:"realmName"`
+}
+
+// UserTokenSettings 用户令牌设置（Nexus Pro）
+type UserTokenSettings struct {
+	Enabled           bool `json:"enabled"`
+	ProtectContent    bool `json:"protectContent"`
+	ExpirationEnabled bool `json:"expirationEnabled"`
+	ExpirationDays    int  `json:"expirationDays,omitempty"`
+}
+
+// ListAvailableRealms 列出所有可用的安全域
+func (c *Client) ListAvailableRealms() ([]RealmResponse, error) {
+	data, err := c.get("/service/rest/v1/security/realms/available")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list available realms: %w", err)
+	}
+
+	var realms []RealmResponse
+	if err := json.Unmarshal(data, &realms); err != nil {
+		return nil, fmt.Errorf("failed to parse realms response: %w", err)
+	}
+
+	return realms, nil
+}
+
+// GetActiveRealms 获取已启用的安全域（按顺序）
+func (c *Client) GetActiveRealms() ([]string, error) {
+	data, err := c.get("/service/rest/v1/security/realms/active")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active realms: %w", err)
+	}
+
+	var realms []string
+	if err := json.Unmarshal(data, &realms); err != nil {
+		return nil, fmt.Errorf("failed to parse active realms response: %w", err)
+	}
+
+	return realms, nil
+}
+
+// SetActiveRealms 设置已启用的安全域及其顺序
+func (c *Client) SetActiveRealms(realms []string) error {
+	_, err := c.put("/service/rest/v1/security/realms/active", realms)
+	if err != nil {
+		return fmt.Errorf("failed to set active realms: %w", err)
+	}
+	return nil
+}
+
+// GetAnonymousSettings 获取匿名访问设置
+func (c *Client) GetAnonymousSettings() (*AnonymousSettings, error) {
+	data, err := c.get("/service/rest/v1/security/anonymous")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get anonymous access settings: %w", err)
+	}
+
+	var settings AnonymousSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("failed to parse anonymous access settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// UpdateAnonymousSettings 更新匿名访问设置
+func (c *Client) UpdateAnonymousSettings(settings AnonymousSettings) error {
+	_, err := c.put("/service/rest/v1/security/anonymous", settings)
+	if err != nil {
+		return fmt.Errorf("failed to update anonymous access settings: %w", err)
+	}
+	return nil
+}
+
+// GetUserTokenSettings 获取用户令牌设置
+func (c *Client) GetUserTokenSettings() (*UserTokenSettings, error) {
+	data, err := c.get("/service/rest/v1/security/user-tokens")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user token settings: %w", err)
+	}
+
+	var settings UserTokenSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("failed to parse user token settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// UpdateUserTokenSettings 更新用户令牌设置
+func (c *Client) UpdateUserTokenSettings(settings UserTokenSettings) error {
+	_, err := c.put("/service/rest/v1/security/user-tokens", settings)
+	if err != nil {
+		return fmt.Errorf("failed to update user token settings: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package nexus
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ContentSelectorRequest 内容选择器请求
+type ContentSelectorRequest struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description"`
+	Expression  string `json:"expression"`
+}
+
+// ContentSelectorResponse 内容选择器响应
+type ContentSelectorResponse struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Expression  string `json:"expression"`
+}
+
+// CreateContentSelector 创建内容选择器
+func (c *Client) CreateContentSelector(req ContentSelectorRequest) error {
+	_, err := c.post("/service/rest/v1/security/content-selectors", req)
+	if err != nil {
+		return fmt.Errorf("failed to create content selector %s: %w", req.Name, err)
+	}
+	return nil
+}
+
+// GetContentSelector 获取内容选择器
+func (c *Client) GetContentSelector(name string) (*ContentSelectorResponse, error) {
+	data, err := c.get(fmt.Sprintf("/service/rest/v1/security/content-selectors/%s", name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get content selector %s: %w", name, err)
+	}
+
+	var selector ContentSelectorResponse
+	if err := json.Unmarshal(data, &selector); err != nil {
+		return nil, fmt.Errorf("failed to parse content selector response: %w", err)
+	}
+
+	return &selector, nil
+}
+
+// UpdateContentSelector 更新内容选择器（名称不可修改）
+func (c *Client) UpdateContentSelector(name string, req ContentSelectorRequest) error {
+	req.Name = ""
+	_, err := c.put(fmt.Sprintf("/service/rest/v1/security/content-selectors/%s", name), req)
+	if err != nil {
+		return fmt.Errorf("failed to update content selector %s: %w", name, err)
+	}
+	return nil
+}
+
+// DeleteContentSelector 删除内容选择器
+func (c *Client) DeleteContentSelector(name string) error {
+	_, err := c.delete(fmt.Sprintf("/service/rest/v1/security/content-selectors/%s", name))
+	if err != nil {
+		return fmt.Errorf("failed to delete content selector %s: %w", name, err)
+	}
+	return nil
+}
+
+// ContentSelectorExists 检查内容选择器是否存在
+func (c *Client) ContentSelectorExists(name string) (bool, error) {
+	_, err := c.GetContentSelector(name)
+	if err != nil {
+		if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "not found") {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// ListContentSelectors 列出所有内容选择器
+func (c *Client) ListContentSelectors() ([]ContentSelectorResponse, error) {
+	data, err := c.get("/service/rest/v1/security/content-selectors")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list content selectors: %w", err)
+	}
+
+	var selectors []ContentSelectorResponse
+	if err := json.Unmarshal(data, &selectors); err != nil {
+		return nil, fmt.Errorf("failed to parse content selectors response: %w", err)
+	}
+
+	return selectors, nil
+}
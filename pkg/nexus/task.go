@@ -0,0 +1,167 @@
+package nexus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrTaskTemplatesUnsupported Nexus 版本不支持通过 REST API 创建和修改任务
+var ErrTaskTemplatesUnsupported = errors.New("this Nexus version does not support creating or updating tasks through the REST API; " +
+	"create the task in the UI (System > Tasks) and use \"nexus-cli tasks run\" to trigger it")
+
+// 任务状态（currentState）
+const (
+	TaskStateWaiting = "WAITING"
+	TaskStateRunning = "RUNNING"
+	TaskStateDone    = "DONE"
+)
+
+// 任务最后一次运行的结果（lastRunResult）
+const (
+	TaskResultOK          = "OK"
+	TaskResultFailed      = "FAILED"
+	TaskResultCanceled    = "CANCELED"
+	TaskResultInterrupted = "INTERRUPTED"
+)
+
+// Task 任务
+type Task struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	Message       string `json:"message,omitempty"`
+	CurrentState  string `json:"currentState"`
+	LastRunResult string `json:"lastRunResult,omitempty"`
+	NextRun       string `json:"nextRun,omitempty"`
+	LastRun       string `json:"lastRun,omitempty"`
+}
+
+// TaskFrequency 任务的执行计划
+type TaskFrequency struct {
+	// Schedule manual、once、hourly、daily、weekly、monthly 或 cron
+	Schedule string `json:"schedule"`
+	// StartDate 开始时间（Unix 秒），manual 和 cron 不使用
+	StartDate int64 `json:"startDate,omitempty"`
+	// TimeZoneOffset 开始时间的时区偏移，例如 +08:00
+	TimeZoneOffset string `json:"timeZoneOffset,omitempty"`
+	// RecurringDays weekly 为星期几（1-7），monthly 为日期（1-31）
+	RecurringDays  []int  `json:"recurringDays,omitempty"`
+	CronExpression string `json:"cronExpression,omitempty"`
+}
+
+// TaskRequest 创建或更新任务的请求
+type TaskRequest struct {
+	Type                  string            `json:"type"`
+	Name                  string            `json:"name"`
+	Enabled               bool              `json:"enabled"`
+	AlertEmail            string            `json:"alertEmail,omitempty"`
+	NotificationCondition string            `json:"notificationCondition,omitempty"`
+	Frequency             TaskFrequency     `json:"frequency"`
+	Properties            map[string]string `json:"properties,omitempty"`
+}
+
+// TaskSettings 服务器上任务的设置（GET /v1/tasks/{id}）
+//
+// 只返回任务状态的 Nexus 版本不包含这些字段，此时 Enabled 和 Frequency 为 nil。
+type TaskSettings struct {
+	Enabled               *bool                  `json:"enabled"`
+	AlertEmail            string                 `json:"alertEmail"`
+	NotificationCondition string                 `json:"notificationCondition"`
+	Frequency             *TaskFrequency         `json:"frequency"`
+	Properties            map[string]interface{} `json:"properties"`
+}
+
+// taskTemplateError 将 405 转换为不支持任务创建 API 的错误（旧版本只支持 GET 任务）
+func taskTemplateError(err error) error {
+	if strings.Contains(err.Error(), "status 405") {
+		return fmt.Errorf("%w: %v", ErrTaskTemplatesUnsupported, err)
+	}
+	return err
+}
+
+// Tasks 遍历任务，taskType 不为空时只返回该类型的任务
+func (c *Client) Tasks(ctx context.Context, taskType string) *Iterator[Task] {
+	query := url.Values{}
+	if taskType != "" {
+		query.Set("type", taskType)
+	}
+	return newIterator[Task](ctx, c, "/service/rest/v1/tasks", query)
+}
+
+// GetTask 获取任务
+func (c *Client) GetTask(ctx context.Context, id string) (*Task, error) {
+	data, err := c.doRequestContext(ctx, "GET", "/service/rest/v1/tasks/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task %s: %w", id, err)
+	}
+
+	var task Task
+	if err := json.Unmarshal(data, &task); err != nil {
+		return nil, fmt.Errorf("failed to parse task response: %w", err)
+	}
+	return &task, nil
+}
+
+// GetTaskSettings 获取任务的设置，用于判断任务是否需要更新
+func (c *Client) GetTaskSettings(ctx context.Context, id string) (*TaskSettings, error) {
+	data, err := c.doRequestContext(ctx, "GET", "/service/rest/v1/tasks/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task %s: %w", id, err)
+	}
+
+	var settings TaskSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("failed to parse task response: %w", err)
+	}
+	return &settings, nil
+}
+
+// RunTask 立即运行任务
+func (c *Client) RunTask(ctx context.Context, id string) error {
+	if _, err := c.doRequestContext(ctx, "POST", "/service/rest/v1/tasks/"+url.PathEscape(id)+"/run", nil); err != nil {
+		if strings.Contains(err.Error(), "status 405") {
+			return fmt.Errorf("failed to run task %s: the task is disabled", id)
+		}
+		return fmt.Errorf("failed to run task %s: %w", id, err)
+	}
+	return nil
+}
+
+// StopTask 停止正在运行的任务
+func (c *Client) StopTask(ctx context.Context, id string) error {
+	if _, err := c.doRequestContext(ctx, "POST", "/service/rest/v1/tasks/"+url.PathEscape(id)+"/stop", nil); err != nil {
+		if strings.Contains(err.Error(), "status 409") {
+			return fmt.Errorf("failed to stop task %s: the task is not running or cannot be stopped", id)
+		}
+		return fmt.Errorf("failed to stop task %s: %w", id, err)
+	}
+	return nil
+}
+
+// CreateTask 创建任务
+func (c *Client) CreateTask(req TaskRequest) error {
+	if _, err := c.post("/service/rest/v1/tasks", req); err != nil {
+		return fmt.Errorf("failed to create task %s: %w", req.Name, taskTemplateError(err))
+	}
+	return nil
+}
+
+// UpdateTask 更新任务
+func (c *Client) UpdateTask(id string, req TaskRequest) error {
+	if _, err := c.put("/service/rest/v1/tasks/"+url.PathEscape(id), req); err != nil {
+		return fmt.Errorf("failed to update task %s: %w", req.Name, taskTemplateError(err))
+	}
+	return nil
+}
+
+// DeleteTask 删除任务
+func (c *Client) DeleteTask(id string) error {
+	if _, err := c.delete("/service/rest/v1/tasks/" + url.PathEscape(id)); err != nil {
+		return fmt.Errorf("failed to delete task %s: %w", id, taskTemplateError(err))
+	}
+	return nil
+}
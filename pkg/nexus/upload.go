@@ -0,0 +1,105 @@
+package nexus
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// UploadFile 上传的文件，Open 在写入表单时调用，调用方负责关闭返回的内容
+type UploadFile struct {
+	Name string
+	Open func() (io.ReadCloser, error)
+}
+
+// UploadComponent 通过 /v1/components 的 multipart 表单上传组件
+//
+// fields 为普通表单字段（例如 maven2.groupId），files 为文件字段到本地路径的映射（例如 maven2.asset1）。
+// 文件以流的方式发送，不会整体读入内存；上传时间不受客户端默认超时限制，由 ctx 控制。
+func (c *Client) UploadComponent(ctx context.Context, repository string, fields, files map[string]string) error {
+	uploadFiles := make(map[string]UploadFile, len(files))
+	for field, file := range files {
+		file := file
+		uploadFiles[field] = UploadFile{
+			Name: filepath.Base(file),
+			Open: func() (io.ReadCloser, error) { return os.Open(file) },
+		}
+	}
+	return c.UploadComponentFiles(ctx, repository, fields, uploadFiles)
+}
+
+// UploadComponentFiles 与 UploadComponent 相同，文件内容由 UploadFile 提供，例如从另一个 Nexus 下载的内容
+func (c *Client) UploadComponentFiles(ctx context.Context, repository string, fields map[string]string, files map[string]UploadFile) error {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		err := writeUploadForm(mw, fields, files)
+		if err == nil {
+			err = mw.Close()
+		}
+		_ = pw.CloseWithError(err)
+	}()
+
+	path := "/service/rest/v1/components?repository=" + url.QueryEscape(repository)
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+path, pr)
+	if err != nil {
+		_ = pr.Close()
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("Accept", "application/json")
+
+	httpClient := *c.httpClient
+	httpClient.Timeout = 0
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload to %s: %w", repository, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to upload to %s: request failed with status %d: %s", repository, resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// writeUploadForm 写入表单字段和文件，按字段名排序以保证请求稳定
+func writeUploadForm(mw *multipart.Writer, fields map[string]string, files map[string]UploadFile) error {
+	for _, name := range sortedKeys(fields) {
+		if err := mw.WriteField(name, fields[name]); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedKeys(files) {
+		f, err := files[name].Open()
+		if err != nil {
+			return err
+		}
+		part, err := mw.CreateFormFile(name, files[name].Name)
+		if err == nil {
+			_, err = io.Copy(part, f)
+		}
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -3,6 +3,7 @@ package nexus
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"strings"
 )
 
@@ -27,6 +28,8 @@ type UserResponse struct {
 	Status       string   `json:"status"`
 	Roles        []string `json:"roles"`
 	Source       string   `json:"source"`
+	// ExternalRoles 来自外部来源（如 LDAP 组映射）的角色
+	ExternalRoles []string `json:"externalRoles,omitempty"`
 }
 
 // CreateUser 创建用户
@@ -38,23 +41,69 @@ func (c *Client) CreateUser(req UserRequest) error {
 	return nil
 }
 
+// 用户来源
+const (
+	UserSourceDefault = "default"
+	UserSourceLDAP    = "LDAP"
+)
+
 // GetUser 获取用户信息
 func (c *Client) GetUser(userID string) (*UserResponse, error) {
-	data, err := c.get(fmt.Sprintf("/service/rest/v1/security/users?userId=%s", userID))
+	return c.GetUserFromSource(userID, "")
+}
+
+// GetUserFromSource 从指定来源获取用户信息，source 为空时搜索所有来源
+func (c *Client) GetUserFromSource(userID, source string) (*UserResponse, error) {
+	users, err := c.ListUsers(UserFilter{UserID: userID, Source: source})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user %s: %w", userID, err)
 	}
 
-	var users []UserResponse
-	if err := json.Unmarshal(data, &users); err != nil {
-		return nil, fmt.Errorf("failed to parse user response: %w", err)
+	// userId 参数是前缀匹配，需要找到完全匹配的用户
+	for i := range users {
+		if users[i].UserID == userID {
+			return &users[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("user %s not found", userID)
+}
+
+// UserFilter 用户查询条件，零值列出本地用户
+type UserFilter struct {
+	// UserID 用户 ID 前缀
+	UserID string
+	// Source 用户来源，例如 default 或 LDAP
+	Source string
+}
+
+// ListUsers 列出用户
+//
+// 该接口不分页；外部来源的用户很多时应通过 UserID 前缀缩小范围。
+func (c *Client) ListUsers(filter UserFilter) ([]UserResponse, error) {
+	query := url.Values{}
+	if filter.UserID != "" {
+		query.Set("userId", filter.UserID)
+	}
+	if filter.Source != "" {
+		query.Set("source", filter.Source)
+	}
+	path := "/service/rest/v1/security/users"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
 	}
 
-	if len(users) == 0 {
-		return nil, fmt.Errorf("user %s not found", userID)
+	data, err := c.get(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	var users []UserResponse
+	if err := json.Unmarshal(data, &users); err != nil {
+		return nil, fmt.Errorf("failed to parse users response: %w", err)
 	}
 
-	return &users[0], nil
+	return users, nil
 }
 
 // UpdateUser 更新用户
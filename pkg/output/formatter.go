@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strings"
 	"text/tabwriter"
 	"text/template"
 
@@ -34,6 +36,8 @@ type Formatter struct {
 	writer         io.Writer
 	templateString string
 	quiet          bool
+	columns        []string
+	sortBy         string
 }
 
 // NewFormatter 创建新的格式化器
@@ -58,6 +62,16 @@ func (f *Formatter) SetQuiet(quiet bool) {
 	f.quiet = quiet
 }
 
+// SetColumns 设置 table 和 text 格式输出 Rows 时的列，列名为点分字段路径
+func (f *Formatter) SetColumns(columns []string) {
+	f.columns = columns
+}
+
+// SetSortBy 设置输出 Rows 时的排序字段，以 - 开头时降序
+func (f *Formatter) SetSortBy(field string) {
+	f.sortBy = field
+}
+
 // Print 打印普通消息
 func (f *Formatter) Print(message string) {
 	if f.quiet {
@@ -105,6 +119,9 @@ func (f *Formatter) Info(message string) {
 
 // Output 输出结构化数据
 func (f *Formatter) Output(data interface{}) error {
+	if rows, ok := data.(Rows); ok {
+		return f.outputRows(rows)
+	}
 	switch f.format {
 	case FormatJSON:
 		return f.outputJSON(data)
@@ -128,13 +145,13 @@ func (f *Formatter) outputJSON(data interface{}) error {
 	return encoder.Encode(data)
 }
 
-// outputYAML 输出 YAML 格式
+// outputYAML 输出 YAML 格式，json.Number 会被转换为数值，否则会被编码为字符串
 func (f *Formatter) outputYAML(data interface{}) error {
 	encoder := yaml.NewEncoder(f.writer)
 	defer func() {
 		_ = encoder.Close()
 	}()
-	return encoder.Encode(data)
+	return encoder.Encode(plainValue(data))
 }
 
 // outputTemplate 使用自定义模板输出
@@ -181,6 +198,86 @@ func (f *Formatter) outputTable(data interface{}) error {
 	}
 }
 
+// outputRows 输出资源列表：排序后 table 和 text 格式只输出选择的列，其他格式输出完整字段
+func (f *Formatter) outputRows(rows Rows) error {
+	if f.sortBy != "" {
+		rows.Sort(f.sortBy)
+	}
+
+	columns := f.columns
+	if len(columns) == 0 && len(rows) > 0 {
+		for key := range rows[0] {
+			columns = append(columns, key)
+		}
+		sort.Strings(columns)
+	}
+
+	switch f.format {
+	case FormatTable:
+		w := tabwriter.NewWriter(f.writer, 0, 0, 2, ' ', 0)
+		header := make([]string, len(columns))
+		for i, col := range columns {
+			header[i] = strings.ToUpper(col)
+		}
+		_, _ = fmt.Fprintln(w, strings.Join(header, "\t"))
+		for _, row := range rows {
+			_, _ = fmt.Fprintln(w, strings.Join(rowValues(row, columns), "\t"))
+		}
+		return w.Flush()
+	case FormatText:
+		for _, row := range rows {
+			_, _ = fmt.Fprintln(f.writer, strings.Join(rowValues(row, columns), "\t"))
+		}
+		return nil
+	default:
+		return f.Output([]map[string]interface{}(rows))
+	}
+}
+
+// rowValues 返回一行中各列的文本
+func rowValues(row map[string]interface{}, columns []string) []string {
+	values := make([]string, len(columns))
+	for i, col := range columns {
+		v, _ := Lookup(row, col)
+		values[i] = FormatValue(v)
+	}
+	return values
+}
+
+// plainValue 将 json.Number 递归转换为 int64 或 float64
+func plainValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			return i
+		}
+		if fl, err := value.Float64(); err == nil {
+			return fl
+		}
+		return value.String()
+	case []map[string]interface{}:
+		result := make([]interface{}, len(value))
+		for i, item := range value {
+			result[i] = plainValue(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, item := range value {
+			result[i] = plainValue(item)
+		}
+		return result
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			result[k] = plainValue(item)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
 // printTableFromSlice 从切片打印表格
 func (f *Formatter) printTableFromSlice(w *tabwriter.Writer, data []interface{}) error {
 	if len(data) == 0 {
@@ -0,0 +1,159 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Rows 资源列表，每个资源为 JSON 字段名到值的映射，支持按点分路径选择列、过滤和排序
+type Rows []map[string]interface{}
+
+// ToRows 将结构体、结构体切片或 map 转换为 Rows
+//
+// 通过 JSON 编码转换，因此列名与 Nexus API 的字段名一致；数字保留为 json.Number，避免大数被格式化为科学计数法。
+func ToRows(v interface{}) (Rows, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode rows: %w", err)
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] != '[' {
+		data = append(append([]byte{'['}, data...), ']')
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var rows Rows
+	if err := dec.Decode(&rows); err != nil {
+		return nil, fmt.Errorf("failed to decode rows: %w", err)
+	}
+	return rows, nil
+}
+
+// Lookup 按点分路径查找字段，例如 storage.blobStoreName
+func Lookup(row map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = row
+	for _, key := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// FormatValue 将字段值格式化为单元格文本，列表以逗号分隔
+func FormatValue(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case []interface{}:
+		parts := make([]string, len(value))
+		for i, item := range value {
+			parts[i] = FormatValue(item)
+		}
+		return strings.Join(parts, ",")
+	case map[string]interface{}:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+		return string(data)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// Filter 返回字段值与 selector 匹配的行
+//
+// selector 为逗号分隔的 key=value 或 key!=value，所有条件都满足才匹配；
+// 列表字段只要包含该值即满足 key=value，比较不区分大小写。
+func (r Rows) Filter(selector string) (Rows, error) {
+	if strings.TrimSpace(selector) == "" {
+		return r, nil
+	}
+
+	type condition struct {
+		path   string
+		value  string
+		negate bool
+	}
+	var conditions []condition
+	for _, expr := range strings.Split(selector, ",") {
+		expr = strings.TrimSpace(expr)
+		c := condition{}
+		if key, value, ok := strings.Cut(expr, "!="); ok {
+			c = condition{path: strings.TrimSpace(key), value: strings.TrimSpace(value), negate: true}
+		} else if key, value, ok := strings.Cut(expr, "="); ok {
+			c = condition{path: strings.TrimSpace(key), value: strings.TrimSpace(value)}
+		}
+		if c.path == "" {
+			return nil, fmt.Errorf("invalid selector %q (expected key=value or key!=value)", expr)
+		}
+		conditions = append(conditions, c)
+	}
+
+	var result Rows
+	for _, row := range r {
+		match := true
+		for _, c := range conditions {
+			v, _ := Lookup(row, c.path)
+			if valueMatches(v, c.value) == c.negate {
+				match = false
+				break
+			}
+		}
+		if match {
+			result = append(result, row)
+		}
+	}
+	return result, nil
+}
+
+// valueMatches 检查字段值是否等于期望值，列表字段检查是否包含
+func valueMatches(v interface{}, want string) bool {
+	if list, ok := v.([]interface{}); ok {
+		for _, item := range list {
+			if strings.EqualFold(FormatValue(item), want) {
+				return true
+			}
+		}
+		return false
+	}
+	return strings.EqualFold(FormatValue(v), want)
+}
+
+// Sort 按字段排序，字段名以 - 开头时降序；两边都是数字时按数值比较
+func (r Rows) Sort(path string) {
+	desc := strings.HasPrefix(path, "-")
+	path = strings.TrimPrefix(path, "-")
+	sort.SliceStable(r, func(i, j int) bool {
+		a, _ := Lookup(r[i], path)
+		b, _ := Lookup(r[j], path)
+		if desc {
+			return lessValue(b, a)
+		}
+		return lessValue(a, b)
+	})
+}
+
+// lessValue 比较两个字段值
+func lessValue(a, b interface{}) bool {
+	sa, sb := FormatValue(a), FormatValue(b)
+	fa, errA := strconv.ParseFloat(sa, 64)
+	fb, errB := strconv.ParseFloat(sb, 64)
+	if errA == nil && errB == nil {
+		return fa < fb
+	}
+	return sa < sb
+}
@@ -0,0 +1,91 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRows(t *testing.T) {
+	type repo struct {
+		Name    string            `json:"name"`
+		Format  string            `json:"format"`
+		Size    int64             `json:"size"`
+		Roles   []string          `json:"roles"`
+		Storage map[string]string `json:"storage"`
+	}
+	rows, err := ToRows([]repo{
+		{Name: "maven-releases", Format: "maven2", Size: 12000000, Roles: []string{"devs"}, Storage: map[string]string{"blobStoreName": "default"}},
+		{Name: "npm-proxy", Format: "npm", Size: 900, Roles: []string{"devs", "ops"}, Storage: map[string]string{"blobStoreName": "npm"}},
+		{Name: "maven-central", Format: "maven2", Size: 3000000000, Storage: map[string]string{"blobStoreName": "default"}},
+	})
+	if err != nil {
+		t.Fatalf("ToRows() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		selector string
+		sortBy   string
+		want     string
+		wantErr  bool
+	}{
+		{name: "sort by name", sortBy: "name", want: "maven-central\nmaven-releases\nnpm-proxy\n"},
+		{name: "numeric descending sort", sortBy: "-size", want: "maven-central\nmaven-releases\nnpm-proxy\n"},
+		{name: "field selector", selector: "format=MAVEN2", sortBy: "size", want: "maven-releases\nmaven-central\n"},
+		{name: "nested field and negation", selector: "storage.blobStoreName=default,name!=maven-central", want: "maven-releases\n"},
+		{name: "list field contains value", selector: "roles=ops", want: "npm-proxy\n"},
+		{name: "invalid selector", selector: "format", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered, err := append(Rows{}, rows...).Filter(tt.selector)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Filter() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Filter() error = %v", err)
+			}
+
+			var buf bytes.Buffer
+			f := NewFormatter(FormatText, &buf)
+			f.SetColumns([]string{"name"})
+			f.SetSortBy(tt.sortBy)
+			if err := f.Output(filtered); err != nil {
+				t.Fatalf("Output() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Output() = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestOutputRowsTable(t *testing.T) {
+	rows, err := ToRows(map[string]interface{}{"name": "default", "blobCount": 1234567, "roles": []string{"a", "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	f := NewFormatter(FormatTable, &buf)
+	f.SetColumns([]string{"name", "blobCount", "roles"})
+	if err := f.Output(rows); err != nil {
+		t.Fatal(err)
+	}
+	want := "NAME     BLOBCOUNT  ROLES\ndefault  1234567    a,b\n"
+	if buf.String() != want {
+		t.Errorf("table output = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := NewFormatter(FormatYAML, &buf).Output(rows); err != nil {
+		t.Fatal(err)
+	}
+	if want := "- blobCount: 1234567\n  name: default\n  roles:\n    - a\n    - b\n"; buf.String() != want {
+		t.Errorf("yaml output = %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,68 @@
+// Package password generates random passwords and writes them to sinks.
+package password
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+// 密码长度限制
+const (
+	DefaultLength = 24
+	MinLength     = 12
+	MaxLength     = 128
+)
+
+// 字符集：去掉了容易混淆的字符（0/O、1/l/I）以及在 shell 和 URL 中需要转义的字符
+const (
+	lowerChars  = "abcdefghijkmnopqrstuvwxyz"
+	upperChars  = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	digitChars  = "23456789"
+	symbolChars = "-_.@%+="
+)
+
+// Generate 生成指定长度的随机密码，保证包含大小写字母、数字和符号
+func Generate(length int) (string, error) {
+	if length == 0 {
+		length = DefaultLength
+	}
+	if length < MinLength || length > MaxLength {
+		return "", fmt.Errorf("password length must be between %d and %d", MinLength, MaxLength)
+	}
+
+	classes := []string{lowerChars, upperChars, digitChars, symbolChars}
+	all := lowerChars + upperChars + digitChars + symbolChars
+
+	buf := make([]byte, length)
+	for i := range buf {
+		set := all
+		if i < len(classes) {
+			set = classes[i]
+		}
+		c, err := randomChar(set)
+		if err != nil {
+			return "", err
+		}
+		buf[i] = c
+	}
+
+	// 打乱顺序，避免前几位固定为某一类字符
+	for i := len(buf) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate password: %w", err)
+		}
+		buf[i], buf[j.Int64()] = buf[j.Int64()], buf[i]
+	}
+
+	return string(buf), nil
+}
+
+func randomChar(set string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(set))))
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate password: %w", err)
+	}
+	return set[n.Int64()], nil
+}
@@ -0,0 +1,78 @@
+package password
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name    string
+		length  int
+		wantLen int
+		wantErr bool
+	}{
+		{name: "default length", length: 0, wantLen: DefaultLength},
+		{name: "minimum length", length: MinLength, wantLen: MinLength},
+		{name: "too short", length: 8, wantErr: true},
+		{name: "too long", length: MaxLength + 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Generate(tt.length)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Generate(%d) expected error", tt.length)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Generate(%d) unexpected error = %v", tt.length, err)
+			}
+			if len(got) != tt.wantLen {
+				t.Errorf("Generate(%d) length = %d, want %d", tt.length, len(got), tt.wantLen)
+			}
+			for _, set := range []string{lowerChars, upperChars, digitChars, symbolChars} {
+				if !strings.ContainsAny(got, set) {
+					t.Errorf("Generate(%d) = %q, missing a character from %q", tt.length, got, set)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "passwords.yaml")
+
+	if err := WriteFile(path, Credential{UserID: "alice", Password: "old"}, Credential{UserID: "bob", Password: "b"}); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := WriteFile(path, Credential{UserID: "alice", Password: "new"}); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("file permissions = %o, want 600", perm)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]string
+	if err := yaml.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["alice"] != "new" || got["bob"] != "b" {
+		t.Errorf("file content = %v, want alice=new and bob=b", got)
+	}
+}
@@ -0,0 +1,84 @@
+package password
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Credential 用户 ID 和明文密码
+type Credential struct {
+	UserID   string
+	Password string
+}
+
+// WriteFile 将密码合并写入 YAML 文件（userId: password），文件权限为 0600
+//
+// 文件中已有的其他用户会被保留，同一用户的旧密码会被覆盖。
+func WriteFile(path string, creds ...Credential) error {
+	existing := make(map[string]string)
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(data, &existing); err != nil {
+			return fmt.Errorf("failed to parse password file %s: %w", path, err)
+		}
+	case !os.IsNotExist(err):
+		return fmt.Errorf("failed to read password file %s: %w", path, err)
+	}
+	if existing == nil {
+		existing = make(map[string]string)
+	}
+	for _, c := range creds {
+		existing[c.UserID] = c.Password
+	}
+
+	out, err := yaml.Marshal(existing)
+	if err != nil {
+		return fmt.Errorf("failed to marshal passwords: %w", err)
+	}
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return fmt.Errorf("failed to create directory for password file: %w", err)
+		}
+	}
+
+	// 先写临时文件再重命名，避免中途失败导致文件损坏
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".nexus-cli-passwords-*")
+	if err != nil {
+		return fmt.Errorf("failed to create password file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set password file permissions: %w", err)
+	}
+	if _, err := tmp.Write(out); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write password file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write password file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write password file: %w", err)
+	}
+	return nil
+}
+
+// Print 将密码输出到 writer，每行一个 userId: password
+func Print(w io.Writer, creds ...Credential) error {
+	sorted := append([]Credential{}, creds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].UserID < sorted[j].UserID })
+	for _, c := range sorted {
+		if _, err := fmt.Fprintf(w, "%s: %s\n", c.UserID, c.Password); err != nil {
+			return fmt.Errorf("failed to write password: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+// Package purge deletes components in batches and records progress in a resumable journal.
+package purge
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Entry 计划删除的组件
+type Entry struct {
+	ID         string `json:"id"`
+	Repository string `json:"repository"`
+	Group      string `json:"group,omitempty"`
+	Name       string `json:"name"`
+	Version    string `json:"version,omitempty"`
+	// Size 组件所有资产的大小（字节）
+	Size int64 `json:"size"`
+}
+
+// record 日志中的一行：第一行为计划（查询和所有组件），之后每行记录一个已删除的组件
+type record struct {
+	Query   string     `json:"query,omitempty"`
+	Created *time.Time `json:"created,omitempty"`
+	Plan    []Entry    `json:"plan,omitempty"`
+	Deleted string     `json:"deleted,omitempty"`
+}
+
+// Journal 删除日志，记录计划删除的组件和已删除的组件，中断后可以从日志继续
+type Journal struct {
+	Path    string
+	Query   string
+	Created time.Time
+	Entries []Entry
+
+	file    *os.File
+	deleted map[string]bool
+}
+
+// Create 创建日志并写入计划，文件已存在时返回错误以免覆盖未完成的日志
+func Create(path, query string, entries []Entry) (*Journal, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create journal: %w", err)
+	}
+	j := &Journal{Path: path, Query: query, Created: time.Now().UTC(), Entries: entries, file: f, deleted: make(map[string]bool)}
+	if err := j.write(record{Query: query, Created: &j.Created, Plan: entries}); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return j, nil
+}
+
+// Open 打开已有的日志，之后的删除记录追加到文件末尾
+func Open(path string) (*Journal, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	j := &Journal{Path: path, file: f, deleted: make(map[string]bool)}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 256*1024*1024)
+	first := true
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var r record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			// 中断时最后一行可能只写了一部分，该组件会在继续时重新删除
+			continue
+		}
+		if first {
+			j.Query, j.Entries = r.Query, r.Plan
+			if r.Created != nil {
+				j.Created = *r.Created
+			}
+			first = false
+			continue
+		}
+		if r.Deleted != "" {
+			j.deleted[r.Deleted] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to read journal %s: %w", path, err)
+	}
+	if first {
+		f.Close()
+		return nil, fmt.Errorf("journal %s does not contain a plan", path)
+	}
+	return j, nil
+}
+
+// Remaining 返回尚未删除的组件
+func (j *Journal) Remaining() []Entry {
+	var remaining []Entry
+	for _, e := range j.Entries {
+		if !j.deleted[e.ID] {
+			remaining = append(remaining, e)
+		}
+	}
+	return remaining
+}
+
+// MarkDeleted 记录已删除的组件并同步到磁盘
+func (j *Journal) MarkDeleted(ids []string) error {
+	for _, id := range ids {
+		if err := j.write(record{Deleted: id}); err != nil {
+			return err
+		}
+		j.deleted[id] = true
+	}
+	return j.file.Sync()
+}
+
+// Close 关闭日志文件
+func (j *Journal) Close() error {
+	return j.file.Close()
+}
+
+func (j *Journal) write(r record) error {
+	// 每条记录一次写入一行，查询中的 & 保持原样便于阅读
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(r); err != nil {
+		return err
+	}
+	if _, err := j.file.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return nil
+}
+
+// Failure 删除失败的组件
+type Failure struct {
+	Entry Entry
+	Err   error
+}
+
+// Run 分批删除日志中剩余的组件
+//
+// 每批最多 batchSize 个组件，批内最多 workers 个并发；每批结束后将成功删除的组件写入日志，
+// progress 收到已删除数和总数。ctx 取消后在当前批次结束时停止，返回 ctx 的错误。
+func Run(ctx context.Context, j *Journal, batchSize, workers int, del func(context.Context, Entry) error, progress func(done, total int)) ([]Failure, error) {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	remaining := j.Remaining()
+	total := len(j.Entries)
+	done := total - len(remaining)
+
+	var failures []Failure
+	for start := 0; start < len(remaining); start += batchSize {
+		if err := ctx.Err(); err != nil {
+			return failures, err
+		}
+		batch := remaining[start:min(start+batchSize, len(remaining))]
+		errs := make([]error, len(batch))
+		jobs := make(chan int)
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range jobs {
+					errs[i] = del(ctx, batch[i])
+				}
+			}()
+		}
+		for i := range batch {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+
+		var deleted []string
+		for i, err := range errs {
+			if err != nil {
+				failures = append(failures, Failure{Entry: batch[i], Err: err})
+				continue
+			}
+			deleted = append(deleted, batch[i].ID)
+		}
+		if err := j.MarkDeleted(deleted); err != nil {
+			return failures, err
+		}
+		done += len(deleted)
+		if progress != nil {
+			progress(done, total)
+		}
+	}
+	return failures, ctx.Err()
+}
@@ -0,0 +1,63 @@
+package purge
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunResume(t *testing.T) {
+	var entries []Entry
+	for i := 0; i < 7; i++ {
+		entries = append(entries, Entry{ID: fmt.Sprint(i), Repository: "maven-snapshots", Name: "lib"})
+	}
+	path := filepath.Join(t.TempDir(), "purge.journal")
+	journal, err := Create(path, "repository=maven-snapshots", entries)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := Create(path, "", entries); err == nil {
+		t.Error("Create() should not overwrite an existing journal")
+	}
+
+	del := func(_ context.Context, e Entry) error {
+		if e.ID == "4" {
+			return fmt.Errorf("server error")
+		}
+		return nil
+	}
+	var progress []int
+	failures, err := Run(context.Background(), journal, 3, 2, del, func(done, _ int) { progress = append(progress, done) })
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(failures) != 1 || failures[0].Entry.ID != "4" {
+		t.Errorf("Run() failures = %v, want component 4", failures)
+	}
+	if fmt.Sprint(progress) != "[3 5 6]" {
+		t.Errorf("progress = %v, want [3 5 6]", progress)
+	}
+	journal.Close()
+
+	resumed, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer resumed.Close()
+	if resumed.Query != "repository=maven-snapshots" || len(resumed.Entries) != 7 {
+		t.Errorf("Open() = %q with %d entries", resumed.Query, len(resumed.Entries))
+	}
+	remaining := resumed.Remaining()
+	if len(remaining) != 1 || remaining[0].ID != "4" {
+		t.Fatalf("Remaining() = %v, want only component 4", remaining)
+	}
+
+	failures, err = Run(context.Background(), resumed, 3, 2, func(context.Context, Entry) error { return nil }, nil)
+	if err != nil || len(failures) != 0 {
+		t.Fatalf("Run() after resume = %v, %v", failures, err)
+	}
+	if len(resumed.Remaining()) != 0 {
+		t.Errorf("Remaining() after resume = %v, want none", resumed.Remaining())
+	}
+}
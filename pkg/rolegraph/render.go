@@ -0,0 +1,149 @@
+package rolegraph
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteTree 以树形文本输出角色层级
+//
+// 被多个角色包含的子角色会在每个父角色下重复出现；环和不存在的角色会被标注。
+func WriteTree(w io.Writer, g *Graph) error {
+	var b strings.Builder
+	for _, root := range g.Roots() {
+		b.WriteString(g.label(root) + "\n")
+		g.writeChildren(&b, root, "", map[string]bool{root: true})
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeChildren 递归输出子角色，path 为当前路径上的角色，用于发现环
+func (g *Graph) writeChildren(b *strings.Builder, id, prefix string, path map[string]bool) {
+	node, ok := g.nodes[id]
+	if !ok {
+		return
+	}
+	for i, child := range node.Roles {
+		branch, indent := "├── ", "│   "
+		if i == len(node.Roles)-1 {
+			branch, indent = "└── ", "    "
+		}
+		if path[child] {
+			b.WriteString(prefix + branch + child + " (cycle)\n")
+			continue
+		}
+		b.WriteString(prefix + branch + g.label(child) + "\n")
+		path[child] = true
+		g.writeChildren(b, child, prefix+indent, path)
+		delete(path, child)
+	}
+}
+
+// label 返回树形输出中角色的名称和来源
+func (g *Graph) label(id string) string {
+	node, ok := g.nodes[id]
+	if !ok {
+		return id + " (missing)"
+	}
+	return fmt.Sprintf("%s [%s]", id, node.Origin)
+}
+
+// WriteDOT 以 Graphviz DOT 格式输出角色层级
+//
+// 只在配置中声明的角色为虚线，不存在的角色和环上的边为红色。
+func WriteDOT(w io.Writer, g *Graph) error {
+	var b strings.Builder
+	b.WriteString("digraph roles {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+	for _, id := range g.IDs() {
+		if g.nodes[id].Origin == OriginConfig {
+			fmt.Fprintf(&b, "  %q [style=dashed];\n", id)
+		} else {
+			fmt.Fprintf(&b, "  %q;\n", id)
+		}
+	}
+	for _, ref := range g.Orphans() {
+		fmt.Fprintf(&b, "  %q [color=red, fontcolor=red, style=dashed];\n", ref.Missing)
+	}
+	cycleEdges := g.cycleEdges()
+	for _, id := range g.IDs() {
+		for _, child := range g.nodes[id].Roles {
+			if cycleEdges[[2]string{id, child}] {
+				fmt.Fprintf(&b, "  %q -> %q [color=red];\n", id, child)
+			} else {
+				fmt.Fprintf(&b, "  %q -> %q;\n", id, child)
+			}
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid 以 Mermaid flowchart 格式输出角色层级
+//
+// Mermaid 节点 ID 不能包含任意字符，因此使用 r0、r1 等编号，角色 ID 作为标签。
+func WriteMermaid(w io.Writer, g *Graph) error {
+	ids := make(map[string]string)
+	nodeID := func(role string) string {
+		if id, ok := ids[role]; ok {
+			return id
+		}
+		ids[role] = fmt.Sprintf("r%d", len(ids))
+		return ids[role]
+	}
+
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for _, id := range g.IDs() {
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", nodeID(id), mermaidEscape(id))
+		if g.nodes[id].Origin == OriginConfig {
+			fmt.Fprintf(&b, "  class %s configOnly\n", nodeID(id))
+		}
+	}
+	for _, ref := range g.Orphans() {
+		if _, ok := ids[ref.Missing]; !ok {
+			fmt.Fprintf(&b, "  %s[\"%s (missing)\"]\n", nodeID(ref.Missing), mermaidEscape(ref.Missing))
+			fmt.Fprintf(&b, "  class %s missing\n", nodeID(ref.Missing))
+		}
+	}
+
+	cycleEdges := g.cycleEdges()
+	var redLinks []string
+	link := 0
+	for _, id := range g.IDs() {
+		for _, child := range g.nodes[id].Roles {
+			fmt.Fprintf(&b, "  %s --> %s\n", nodeID(id), nodeID(child))
+			if cycleEdges[[2]string{id, child}] {
+				redLinks = append(redLinks, fmt.Sprint(link))
+			}
+			link++
+		}
+	}
+	b.WriteString("  classDef configOnly stroke-dasharray: 5 5\n")
+	b.WriteString("  classDef missing stroke:#d00,color:#d00,stroke-dasharray: 5 5\n")
+	if len(redLinks) > 0 {
+		fmt.Fprintf(&b, "  linkStyle %s stroke:#d00\n", strings.Join(redLinks, ","))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// cycleEdges 返回所有位于环上的边
+func (g *Graph) cycleEdges() map[[2]string]bool {
+	edges := make(map[[2]string]bool)
+	for _, cycle := range g.Cycles() {
+		for i, id := range cycle {
+			edges[[2]string{id, cycle[(i+1)%len(cycle)]}] = true
+		}
+	}
+	return edges
+}
+
+// mermaidEscape 转义 Mermaid 标签中的引号
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
@@ -0,0 +1,270 @@
+// Package rolegraph builds the Nexus role hierarchy and checks it for cycles and missing roles.
+package rolegraph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 角色来源
+const (
+	// OriginConfig 只在配置中声明，尚未创建
+	OriginConfig = "config"
+	// OriginServer 只存在于服务器
+	OriginServer = "server"
+	// OriginBoth 在配置中声明且已存在于服务器
+	OriginBoth = "config+server"
+)
+
+// Node 角色节点
+type Node struct {
+	ID     string   `json:"id" yaml:"id"`
+	Roles  []string `json:"roles,omitempty" yaml:"roles,omitempty"`
+	Origin string   `json:"origin" yaml:"origin"`
+}
+
+// Reference 对不存在角色的引用
+type Reference struct {
+	Role    string `json:"role" yaml:"role"`
+	Missing string `json:"missing" yaml:"missing"`
+}
+
+// Graph 角色层级图，边从角色指向其包含的子角色
+type Graph struct {
+	nodes map[string]*Node
+}
+
+// New 创建空的角色图
+func New() *Graph {
+	return &Graph{nodes: make(map[string]*Node)}
+}
+
+// AddServerRole 添加服务器上的角色
+//
+// 同名角色已在配置中声明时只更新来源，子角色以配置为准。
+func (g *Graph) AddServerRole(id string, roles []string) {
+	if node, ok := g.nodes[id]; ok {
+		if node.Origin == OriginConfig {
+			node.Origin = OriginBoth
+		}
+		return
+	}
+	g.nodes[id] = &Node{ID: id, Roles: dedupe(roles), Origin: OriginServer}
+}
+
+// AddConfigRole 添加配置中声明的角色
+//
+// 配置是期望状态，因此会覆盖服务器上同名角色的子角色。
+func (g *Graph) AddConfigRole(id string, roles []string) {
+	origin := OriginConfig
+	if node, ok := g.nodes[id]; ok && node.Origin != OriginConfig {
+		origin = OriginBoth
+	}
+	g.nodes[id] = &Node{ID: id, Roles: dedupe(roles), Origin: origin}
+}
+
+// Node 按 ID 查找角色
+func (g *Graph) Node(id string) (*Node, bool) {
+	node, ok := g.nodes[id]
+	return node, ok
+}
+
+// IDs 返回所有角色 ID（已排序）
+func (g *Graph) IDs() []string {
+	ids := make([]string, 0, len(g.nodes))
+	for id := range g.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Roots 返回层级的根：不被其他角色包含的角色
+//
+// 只存在于环中的角色没有父角色以外的入口，每个这样的环取 ID 最小的角色作为根。
+func (g *Graph) Roots() []string {
+	referenced := make(map[string]bool)
+	for _, node := range g.nodes {
+		for _, child := range node.Roles {
+			if child != node.ID {
+				referenced[child] = true
+			}
+		}
+	}
+
+	var roots []string
+	visited := make(map[string]bool)
+	for _, id := range g.IDs() {
+		if !referenced[id] {
+			roots = append(roots, id)
+			g.walk(id, visited)
+		}
+	}
+	inCycle := make(map[string]bool)
+	for _, cycle := range g.Cycles() {
+		for _, id := range cycle {
+			inCycle[id] = true
+		}
+	}
+	for _, id := range g.IDs() {
+		if inCycle[id] && !visited[id] {
+			roots = append(roots, id)
+			g.walk(id, visited)
+		}
+	}
+	return roots
+}
+
+// walk 标记从 id 可达的所有角色
+func (g *Graph) walk(id string, visited map[string]bool) {
+	if visited[id] {
+		return
+	}
+	visited[id] = true
+	if node, ok := g.nodes[id]; ok {
+		for _, child := range node.Roles {
+			g.walk(child, visited)
+		}
+	}
+}
+
+// Subgraph 返回从指定角色可达的子图
+func (g *Graph) Subgraph(ids []string) (*Graph, error) {
+	visited := make(map[string]bool)
+	for _, id := range ids {
+		if _, ok := g.nodes[id]; !ok {
+			return nil, fmt.Errorf("role %s not found", id)
+		}
+		g.walk(id, visited)
+	}
+
+	sub := New()
+	for id := range visited {
+		if node, ok := g.nodes[id]; ok {
+			sub.nodes[id] = node
+		}
+	}
+	return sub, nil
+}
+
+// Orphans 返回对不存在角色的引用
+func (g *Graph) Orphans() []Reference {
+	var refs []Reference
+	for _, id := range g.IDs() {
+		for _, child := range g.nodes[id].Roles {
+			if _, ok := g.nodes[child]; !ok {
+				refs = append(refs, Reference{Role: id, Missing: child})
+			}
+		}
+	}
+	return refs
+}
+
+// Cycles 返回角色层级中的环，每个环从 ID 最小的角色开始，例如 [a b c] 表示 a -> b -> c -> a
+func (g *Graph) Cycles() [][]string {
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := make(map[string]int)
+	seen := make(map[string]bool)
+	var cycles [][]string
+	var stack []string
+
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = inProgress
+		stack = append(stack, id)
+		for _, child := range g.nodes[id].Roles {
+			if _, ok := g.nodes[child]; !ok {
+				continue
+			}
+			switch state[child] {
+			case unvisited:
+				visit(child)
+			case inProgress:
+				// 回边：栈中从 child 开始的部分构成环
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == child {
+						cycle := rotate(stack[i:])
+						if key := strings.Join(cycle, "\x00"); !seen[key] {
+							seen[key] = true
+							cycles = append(cycles, cycle)
+						}
+						break
+					}
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = done
+	}
+
+	for _, id := range g.IDs() {
+		if state[id] == unvisited {
+			visit(id)
+		}
+	}
+	return cycles
+}
+
+// Order 返回子角色在前的创建顺序，Nexus 要求角色包含的子角色已存在
+//
+// 存在环时无法确定顺序，返回错误。
+func (g *Graph) Order() ([]string, error) {
+	if cycles := g.Cycles(); len(cycles) > 0 {
+		return nil, fmt.Errorf("role cycle detected: %s", FormatCycle(cycles[0]))
+	}
+	var order []string
+	visited := make(map[string]bool)
+	var visit func(id string)
+	visit = func(id string) {
+		if visited[id] {
+			return
+		}
+		visited[id] = true
+		node, ok := g.nodes[id]
+		if !ok {
+			return
+		}
+		for _, child := range node.Roles {
+			visit(child)
+		}
+		order = append(order, id)
+	}
+	for _, id := range g.IDs() {
+		visit(id)
+	}
+	return order, nil
+}
+
+// FormatCycle 将环格式化为 a -> b -> a
+func FormatCycle(cycle []string) string {
+	return strings.Join(append(append([]string{}, cycle...), cycle[0]), " -> ")
+}
+
+// rotate 将环旋转为从 ID 最小的角色开始，便于去重
+func rotate(cycle []string) []string {
+	start := 0
+	for i, id := range cycle {
+		if id < cycle[start] {
+			start = i
+		}
+	}
+	return append(append([]string{}, cycle[start:]...), cycle[:start]...)
+}
+
+// dedupe 去除重复的子角色，保持原有顺序
+func dedupe(roles []string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, role := range roles {
+		if !seen[role] {
+			seen[role] = true
+			result = append(result, role)
+		}
+	}
+	return result
+}
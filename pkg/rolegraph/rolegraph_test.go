@@ -0,0 +1,98 @@
+package rolegraph
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestCycles(t *testing.T) {
+	tests := []struct {
+		name  string
+		roles map[string][]string
+		want  [][]string
+	}{
+		{
+			name:  "no cycle",
+			roles: map[string][]string{"a": {"b", "c"}, "b": {"c"}, "c": nil},
+		},
+		{
+			name:  "self reference",
+			roles: map[string][]string{"a": {"a"}},
+			want:  [][]string{{"a"}},
+		},
+		{
+			name:  "three role cycle",
+			roles: map[string][]string{"c": {"a"}, "a": {"b"}, "b": {"c"}, "d": {"b"}},
+			want:  [][]string{{"a", "b", "c"}},
+		},
+		{
+			name:  "missing child is not a cycle",
+			roles: map[string][]string{"a": {"ghost"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New()
+			for id, children := range tt.roles {
+				g.AddConfigRole(id, children)
+			}
+			if got := g.Cycles(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Cycles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	g := New()
+	g.AddServerRole("devs", []string{"old-readers"})
+	g.AddServerRole("nx-admin", nil)
+	g.AddConfigRole("devs", []string{"readers", "ghost"})
+	g.AddConfigRole("readers", nil)
+
+	devs, _ := g.Node("devs")
+	if devs.Origin != OriginBoth || !reflect.DeepEqual(devs.Roles, []string{"readers", "ghost"}) {
+		t.Errorf("devs = %+v, want config roles with origin %s", devs, OriginBoth)
+	}
+	if want := []Reference{{Role: "devs", Missing: "ghost"}}; !reflect.DeepEqual(g.Orphans(), want) {
+		t.Errorf("Orphans() = %v, want %v", g.Orphans(), want)
+	}
+	if want := []string{"devs", "nx-admin"}; !reflect.DeepEqual(g.Roots(), want) {
+		t.Errorf("Roots() = %v, want %v", g.Roots(), want)
+	}
+	order, err := g.Order()
+	if err != nil {
+		t.Fatalf("Order() error = %v", err)
+	}
+	if want := []string{"readers", "devs", "nx-admin"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("Order() = %v, want %v", order, want)
+	}
+}
+
+func TestWriteTree(t *testing.T) {
+	g := New()
+	g.AddServerRole("team", []string{"devs", "ops"})
+	g.AddServerRole("devs", []string{"readers"})
+	g.AddServerRole("ops", []string{"team"})
+	g.AddConfigRole("readers", []string{"ghost"})
+	g.AddServerRole("loop", []string{"loop"})
+
+	var buf bytes.Buffer
+	if err := WriteTree(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+	want := `loop [server]
+└── loop (cycle)
+ops [server]
+└── team [server]
+    ├── devs [server]
+    │   └── readers [config]
+    │       └── ghost (missing)
+    └── ops (cycle)
+`
+	if buf.String() != want {
+		t.Errorf("WriteTree() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
@@ -0,0 +1,61 @@
+// Package routing evaluates Nexus routing rules locally.
+package routing
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// 路由规则模式
+const (
+	ModeAllow = "ALLOW"
+	ModeBlock = "BLOCK"
+)
+
+// Result 路由规则评估结果
+type Result struct {
+	Path    string `json:"path" yaml:"path"`
+	Mode    string `json:"mode" yaml:"mode"`
+	Allowed bool   `json:"allowed" yaml:"allowed"`
+	// Matcher 匹配到的表达式，未匹配时为空
+	Matcher string `json:"matcher,omitempty" yaml:"matcher,omitempty"`
+}
+
+// Compile 编译匹配表达式，与 Nexus 一样要求整个路径匹配
+func Compile(matcher string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile("^(?:" + matcher + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid matcher %q: %w", matcher, err)
+	}
+	return re, nil
+}
+
+// Evaluate 评估请求路径是否被路由规则允许
+//
+// BLOCK 模式下任一表达式匹配即拒绝请求；ALLOW 模式下只有匹配的请求才被允许。
+func Evaluate(mode string, matchers []string, path string) (*Result, error) {
+	mode = strings.ToUpper(mode)
+	if mode != ModeAllow && mode != ModeBlock {
+		return nil, fmt.Errorf("invalid routing rule mode %q (expected %s or %s)", mode, ModeAllow, ModeBlock)
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	result := &Result{Path: path, Mode: mode}
+	for _, matcher := range matchers {
+		re, err := Compile(matcher)
+		if err != nil {
+			return nil, err
+		}
+		if re.MatchString(path) {
+			result.Matcher = matcher
+			break
+		}
+	}
+
+	matched := result.Matcher != ""
+	result.Allowed = matched == (mode == ModeAllow)
+	return result, nil
+}
@@ -0,0 +1,82 @@
+package routing
+
+import (
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        string
+		matchers    []string
+		path        string
+		wantAllowed bool
+		wantMatcher string
+		wantErr     bool
+	}{
+		{
+			name:        "block matching npm scope",
+			mode:        ModeBlock,
+			matchers:    []string{"/@example/.*", ".*/com/example/.*"},
+			path:        "/@example/internal-lib/-/internal-lib-1.0.0.tgz",
+			wantAllowed: false,
+			wantMatcher: "/@example/.*",
+		},
+		{
+			name:        "block does not match",
+			mode:        ModeBlock,
+			matchers:    []string{"/@example/.*"},
+			path:        "/lodash/-/lodash-4.17.21.tgz",
+			wantAllowed: true,
+		},
+		{
+			name:        "allow requires match",
+			mode:        "allow",
+			matchers:    []string{"/org/apache/.*"},
+			path:        "com/example/lib/1.0/lib-1.0.jar",
+			wantAllowed: false,
+		},
+		{
+			name:        "matcher must match whole path",
+			mode:        ModeBlock,
+			matchers:    []string{"/com/example"},
+			path:        "/com/example/lib/1.0/lib-1.0.jar",
+			wantAllowed: true,
+		},
+		{
+			name:     "invalid mode",
+			mode:     "DENY",
+			matchers: []string{".*"},
+			path:     "/x",
+			wantErr:  true,
+		},
+		{
+			name:     "invalid matcher",
+			mode:     ModeBlock,
+			matchers: []string{"(["},
+			path:     "/x",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Evaluate(tt.mode, tt.matchers, tt.path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Evaluate() expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Evaluate() unexpected error = %v", err)
+			}
+			if result.Allowed != tt.wantAllowed {
+				t.Errorf("Evaluate() allowed = %v, want %v", result.Allowed, tt.wantAllowed)
+			}
+			if result.Matcher != tt.wantMatcher {
+				t.Errorf("Evaluate() matcher = %q, want %q", result.Matcher, tt.wantMatcher)
+			}
+		})
+	}
+}
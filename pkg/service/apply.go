@@ -2,12 +2,16 @@
 package service
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"strings"
+	"time"
 
 	"github.com/alauda/nexus-cli/pkg/config"
 	"github.com/alauda/nexus-cli/pkg/nexus"
 	"github.com/alauda/nexus-cli/pkg/output"
+	"github.com/alauda/nexus-cli/pkg/rolegraph"
 )
 
 // ApplyService 应用服务
@@ -15,20 +19,46 @@ type ApplyService struct {
 	client    *nexus.Client
 	config    *config.Config
 	formatter *output.Formatter
+
+	// ldapGroups 缓存 LDAP 来源的组，用于检查外部角色映射
+	ldapGroups map[string]bool
+
+	// grantRoles 记录每个授予对象由 userRepositoryPermissions 生成的角色
+	grantRoles map[grantee]string
+	// stalePermissionRoles 本配置生成但不再需要的权限角色
+	stalePermissionRoles map[string]bool
+	// otherPermissionRoles 其他配置文件生成的权限角色，持有者保留这些角色
+	otherPermissionRoles map[string]bool
+
+	// configName 配置名称，记录在生成的权限角色中，用于区分多个配置文件生成的角色
+	configName string
+
+	// generatedPasswords 记录本次创建用户时生成的密码
+	generatedPasswords map[string]string
+
+	// skipPermissionSync 不同步和清理仓库权限角色
+	skipPermissionSync bool
+
+	// allowLocalRealmRemoval 允许在 security.realms 中去掉 NexusAuthenticatingRealm
+	allowLocalRealmRemoval bool
 }
 
 // ApplyResult 应用结果
 type ApplyResult struct {
-	Total               int
-	Success             int
-	Failed              int
-	Skipped             int
-	UsersCreated        int
-	RepositoriesCreated int
-	RolesCreated        int
-	PrivilegesCreated   int
-	Errors              []string
-	Warnings            []string
+	Total                  int
+	Success                int
+	Failed                 int
+	Skipped                int
+	BlobStoresCreated      int
+	CleanupPoliciesCreated int
+	UsersCreated           int
+	RepositoriesCreated    int
+	RolesCreated           int
+	PrivilegesCreated      int
+	// GeneratedPasswords 本次创建用户时生成的密码（userId -> password），由调用方写入配置的 sink
+	GeneratedPasswords map[string]string
+	Errors             []string
+	Warnings           []string
 }
 
 // NewApplyService 创建应用服务
@@ -37,40 +67,97 @@ func NewApplyService(client *nexus.Client, cfg *config.Config, formatter *output
 		formatter = output.NewFormatter(output.FormatText, nil)
 	}
 	return &ApplyService{
-		client:    client,
-		config:    cfg,
-		formatter: formatter,
+		client:             client,
+		config:             cfg,
+		formatter:          formatter,
+		generatedPasswords: make(map[string]string),
 	}
 }
 
+// SetConfigName 设置配置名称（通常为配置文件名），默认为空
+//
+// 生成的权限角色的 ID 和描述会包含配置名称，apply 只清理同名配置生成的权限角色，
+// 因此分别 apply 多个配置文件时不会删除或覆盖彼此的权限角色。
+func (s *ApplyService) SetConfigName(name string) {
+	s.configName = name
+}
+
+// SetSyncPermissionRoles 设置是否同步和清理仓库权限角色（默认开启）
+//
+// 配置中的角色是从其他实例原样复制时（例如迁移）应关闭，
+// 复制来的权限角色不是由本配置的 userRepositoryPermissions 生成的，不应被同步或清理。
+func (s *ApplyService) SetSyncPermissionRoles(enabled bool) {
+	s.skipPermissionSync = !enabled
+}
+
+// SetAllowLocalRealmRemoval 设置是否允许启用的安全域中不包含 NexusAuthenticatingRealm（默认不允许）
+//
+// 去掉该安全域后本地用户（包括 admin 和 nexus-cli 使用的用户）都无法登录，因此需要显式确认。
+func (s *ApplyService) SetAllowLocalRealmRemoval(allow bool) {
+	s.allowLocalRealmRemoval = allow
+}
+
 // Apply 应用配置
 func (s *ApplyService) Apply() (*ApplyResult, error) {
 	result := &ApplyResult{
-		Errors:   []string{},
-		Warnings: []string{},
+		Errors:             []string{},
+		Warnings:           []string{},
+		GeneratedPasswords: s.generatedPasswords,
 	}
 
 	s.formatter.Info("Starting to apply configuration...")
 
-	// 1. 创建权限
-	count, err := s.applyPrivileges()
+	// 0. 创建 blob store（仓库依赖 blob store，必须最先创建）
+	count, err := s.applyBlobStores()
 	if err != nil {
 		result.Errors = append(result.Errors, err.Error())
-		return result, fmt.Errorf("failed to apply privileges: %w", err)
+		return result, fmt.Errorf("failed to apply blob stores: %w", err)
 	}
-	result.PrivilegesCreated = count
+	result.BlobStoresCreated = count
 	result.Success += count
 
-	// 2. 创建角色
-	count, err = s.applyRoles()
+	// 0.1 创建清理策略（仓库可能引用清理策略）
+	count, err = s.applyCleanupPolicies()
 	if err != nil {
 		result.Errors = append(result.Errors, err.Error())
-		return result, fmt.Errorf("failed to apply roles: %w", err)
+		return result, fmt.Errorf("failed to apply cleanup policies: %w", err)
+	}
+	result.CleanupPoliciesCreated = count
+	result.Success += count
+
+	// 0.2 创建内容选择器（repository-content-selector 权限依赖内容选择器）
+	count, err = s.applyContentSelectors()
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result, fmt.Errorf("failed to apply content selectors: %w", err)
+	}
+	result.Success += count
+
+	// 0.3 创建路由规则（代理仓库可能引用路由规则）
+	count, err = s.applyRoutingRules()
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result, fmt.Errorf("failed to apply routing rules: %w", err)
+	}
+	result.Success += count
+
+	// 0.4 配置 LDAP 服务器（LDAP 用户和外部角色映射依赖 LDAP）
+	count, err = s.applyLDAP()
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result, fmt.Errorf("failed to apply LDAP servers: %w", err)
 	}
-	result.RolesCreated = count
 	result.Success += count
 
-	// 3. 创建仓库
+	// 0.5 配置安全域、匿名访问和用户令牌
+	count, err = s.applySecurity()
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result, fmt.Errorf("failed to apply security settings: %w", err)
+	}
+	result.Success += count
+
+	// 1. 创建仓库（仓库权限需要仓库已存在以确定 format 和展开通配符）
 	count, err = s.applyRepositories()
 	if err != nil {
 		result.Errors = append(result.Errors, err.Error())
@@ -79,16 +166,24 @@ func (s *ApplyService) Apply() (*ApplyResult, error) {
 	result.RepositoriesCreated = count
 	result.Success += count
 
-	// 4. 创建用户
-	count, err = s.applyUsers()
+	// 1.1 创建计划任务（任务参数引用仓库和 blob store）
+	count, err = s.applyTasks()
 	if err != nil {
 		result.Errors = append(result.Errors, err.Error())
-		return result, fmt.Errorf("failed to apply users: %w", err)
+		return result, fmt.Errorf("failed to apply tasks: %w", err)
 	}
-	result.UsersCreated = count
 	result.Success += count
 
-	// 5. 配置用户仓库权限
+	// 2. 创建权限
+	count, err = s.applyPrivileges()
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result, fmt.Errorf("failed to apply privileges: %w", err)
+	}
+	result.PrivilegesCreated = count
+	result.Success += count
+
+	// 3. 配置仓库权限角色（用户和角色的成员列表需要包含这些角色）
 	count, err = s.applyUserRepositoryPermissions()
 	if err != nil {
 		result.Errors = append(result.Errors, err.Error())
@@ -96,12 +191,445 @@ func (s *ApplyService) Apply() (*ApplyResult, error) {
 	}
 	result.Success += count
 
+	// 4. 创建角色（包含授予该角色的仓库权限角色）
+	count, err = s.applyRoles()
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result, fmt.Errorf("failed to apply roles: %w", err)
+	}
+	result.RolesCreated = count
+	result.Success += count
+
+	// 5. 创建或更新用户，角色设置为声明的角色加上权限角色
+	count, err = s.applyUsers()
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result, fmt.Errorf("failed to apply users: %w", err)
+	}
+	result.UsersCreated = count
+	result.Success += count
+
+	// 6. 同步未声明的用户、角色和 LDAP 组的权限角色，并清理不再需要的权限角色
+	if !s.skipPermissionSync {
+		count, err = s.syncPermissionRoles()
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			return result, fmt.Errorf("failed to reconcile permission roles: %w", err)
+		}
+		result.Success += count
+	}
+
 	result.Total = result.Success + result.Failed + result.Skipped
 	s.formatter.Success("Configuration applied successfully!")
 	return result, nil
 }
 
-// applyPrivileges 应用权限配置
+// applyBlobStores 应用 blob store 配置
+func (s *ApplyService) applyBlobStores() (int, error) {
+	if len(s.config.BlobStores) == 0 {
+		return 0, nil
+	}
+	s.formatter.Info("Applying blob stores...")
+	count := 0
+	for _, store := range s.config.BlobStores {
+		existing, err := s.client.GetBlobStore(store.Name)
+		if err != nil && !strings.Contains(err.Error(), "not found") {
+			return count, fmt.Errorf("failed to check blob store %s: %w", store.Name, err)
+		}
+
+		if strings.EqualFold(store.Type, config.BlobStoreTypeS3) {
+			req := buildS3BlobStore(store)
+			if existing != nil {
+				if existing.Type != nexus.BlobStoreTypeS3 {
+					return count, fmt.Errorf("blob store %s already exists with type %s", store.Name, existing.Type)
+				}
+				current, err := s.client.GetS3BlobStore(store.Name)
+				if err != nil {
+					return count, err
+				}
+				if s3BlobStoreEqual(*current, req) {
+					continue
+				}
+				if err := s.client.UpdateS3BlobStore(store.Name, req); err != nil {
+					return count, err
+				}
+				s.formatter.Success(fmt.Sprintf("Updated blob store: %s (s3)", store.Name))
+			} else {
+				if err := s.client.CreateS3BlobStore(req); err != nil {
+					return count, err
+				}
+				s.formatter.Success(fmt.Sprintf("Created blob store: %s (s3)", store.Name))
+			}
+			count++
+			continue
+		}
+
+		req := nexus.FileBlobStoreRequest{
+			Name:      store.Name,
+			Path:      store.Path,
+			SoftQuota: buildSoftQuota(store.SoftQuota),
+		}
+		if existing != nil {
+			if existing.Type != nexus.BlobStoreTypeFile {
+				return count, fmt.Errorf("blob store %s already exists with type %s", store.Name, existing.Type)
+			}
+			current, err := s.client.GetFileBlobStore(store.Name)
+			if err != nil {
+				return count, err
+			}
+			if req.Path == "" {
+				// 未配置路径时保留已有路径
+				req.Path = current.Path
+			}
+			if req.Path == current.Path && reflect.DeepEqual(req.SoftQuota, current.SoftQuota) {
+				continue
+			}
+			if err := s.client.UpdateFileBlobStore(store.Name, req); err != nil {
+				return count, err
+			}
+			s.formatter.Success(fmt.Sprintf("Updated blob store: %s (file)", store.Name))
+		} else {
+			if req.Path == "" {
+				// Nexus 默认使用与名称相同的相对路径
+				req.Path = store.Name
+			}
+			if err := s.client.CreateFileBlobStore(req); err != nil {
+				return count, err
+			}
+			s.formatter.Success(fmt.Sprintf("Created blob store: %s (file)", store.Name))
+		}
+		count++
+	}
+	return count, nil
+}
+
+// s3BlobStoreEqual 比较 S3 blob store 的配置是否一致
+//
+// Nexus 不返回密钥和会话令牌，比较时忽略这两项，只修改密钥不会触发更新。
+func s3BlobStoreEqual(current, desired nexus.S3BlobStoreRequest) bool {
+	current.Name, desired.Name = "", ""
+	for _, security := range []**nexus.S3BucketSecurity{&current.BucketConfiguration.BucketSecurity, &desired.BucketConfiguration.BucketSecurity} {
+		if *security != nil {
+			masked := **security
+			masked.SecretAccessKey, masked.SessionToken = "", ""
+			*security = &masked
+		}
+	}
+	return reflect.DeepEqual(current, desired)
+}
+
+// buildSoftQuota 构建 blob store 软配额
+func buildSoftQuota(cfg *config.SoftQuotaConfig) *nexus.BlobStoreSoftQuota {
+	if cfg == nil {
+		return nil
+	}
+	return &nexus.BlobStoreSoftQuota{Type: cfg.Type, Limit: cfg.Limit}
+}
+
+// buildS3BlobStore 构建 S3 blob store 请求
+func buildS3BlobStore(store config.BlobStore) nexus.S3BlobStoreRequest {
+	s3 := store.S3
+	req := nexus.S3BlobStoreRequest{
+		Name:      store.Name,
+		SoftQuota: buildSoftQuota(store.SoftQuota),
+		BucketConfiguration: nexus.S3BucketConfiguration{
+			Bucket: nexus.S3Bucket{
+				Region:     s3.Region,
+				Name:       s3.Bucket,
+				Prefix:     s3.Prefix,
+				Expiration: s3.Expiration,
+			},
+		},
+	}
+	if s3.AccessKeyID != "" || s3.Role != "" {
+		req.BucketConfiguration.BucketSecurity = &nexus.S3BucketSecurity{
+			AccessKeyID:     s3.AccessKeyID,
+			SecretAccessKey: s3.SecretAccessKey,
+			Role:            s3.Role,
+			SessionToken:    s3.SessionToken,
+		}
+	}
+	if s3.EncryptionType != "" {
+		req.BucketConfiguration.Encryption = &nexus.S3Encryption{
+			EncryptionType: s3.EncryptionType,
+			EncryptionKey:  s3.EncryptionKey,
+		}
+	}
+	if s3.Endpoint != "" || s3.SignerType != "" || s3.ForcePathStyle || s3.MaxConnectionPoolSize > 0 {
+		req.BucketConfiguration.AdvancedBucketConnection = &nexus.S3AdvancedBucketConnection{
+			Endpoint:              s3.Endpoint,
+			SignerType:            s3.SignerType,
+			ForcePathStyle:        s3.ForcePathStyle,
+			MaxConnectionPoolSize: s3.MaxConnectionPoolSize,
+		}
+	}
+	return req
+}
+
+// applyCleanupPolicies 应用清理策略配置，已存在且有变化的策略会被更新
+func (s *ApplyService) applyCleanupPolicies() (int, error) {
+	if len(s.config.CleanupPolicies) == 0 {
+		return 0, nil
+	}
+	s.formatter.Info("Applying cleanup policies...")
+
+	existing, err := s.client.ListCleanupPolicies()
+	if err != nil {
+		return 0, err
+	}
+	existingPolicies := make(map[string]nexus.CleanupPolicyRequest, len(existing))
+	for _, p := range existing {
+		existingPolicies[p.Name] = p
+	}
+
+	count := 0
+	for _, policy := range s.config.CleanupPolicies {
+		req := BuildCleanupPolicyRequest(policy)
+		if current, ok := existingPolicies[policy.Name]; ok {
+			if reflect.DeepEqual(current, req) {
+				s.formatter.Info(fmt.Sprintf("Cleanup policy %s is up to date, skipping...", policy.Name))
+				continue
+			}
+			if err := s.client.UpdateCleanupPolicy(policy.Name, req); err != nil {
+				return count, err
+			}
+			s.formatter.Success(fmt.Sprintf("Updated cleanup policy: %s", policy.Name))
+		} else {
+			if err := s.client.CreateCleanupPolicy(req); err != nil {
+				return count, err
+			}
+			s.formatter.Success(fmt.Sprintf("Created cleanup policy: %s", policy.Name))
+		}
+		count++
+	}
+	return count, nil
+}
+
+// BuildCleanupPolicyRequest 将清理策略配置转换为 API 请求
+func BuildCleanupPolicyRequest(policy config.CleanupPolicy) nexus.CleanupPolicyRequest {
+	req := nexus.CleanupPolicyRequest{
+		Name:                    policy.Name,
+		Notes:                   policy.Notes,
+		Format:                  policy.Format,
+		CriteriaLastBlobUpdated: policy.Criteria.LastBlobUpdated,
+		CriteriaLastDownloaded:  policy.Criteria.LastDownloaded,
+		CriteriaReleaseType:     policy.Criteria.ReleaseType,
+		CriteriaAssetRegex:      policy.Criteria.AssetRegex,
+		Retain:                  policy.Criteria.Retain,
+	}
+	if req.Format == "*" {
+		req.Format = "ALL_FORMATS"
+	}
+	if req.Retain > 0 {
+		req.SortBy = policy.Criteria.SortBy
+		if req.SortBy == "" {
+			req.SortBy = "version"
+		}
+	}
+	return req
+}
+
+// applyContentSelectors 应用内容选择器配置，已存在且描述或表达式有变化的选择器会被更新
+func (s *ApplyService) applyContentSelectors() (int, error) {
+	if len(s.config.ContentSelectors) == 0 {
+		return 0, nil
+	}
+	s.formatter.Info("Applying content selectors...")
+	count := 0
+	for _, sel := range s.config.ContentSelectors {
+		exists, err := s.client.ContentSelectorExists(sel.Name)
+		if err != nil {
+			return count, fmt.Errorf("failed to check content selector %s: %w", sel.Name, err)
+		}
+
+		req := nexus.ContentSelectorRequest{
+			Name:        sel.Name,
+			Description: sel.Description,
+			Expression:  sel.Expression,
+		}
+
+		if exists {
+			existing, err := s.client.GetContentSelector(sel.Name)
+			if err != nil {
+				return count, err
+			}
+			if existing.Description == req.Description && existing.Expression == req.Expression {
+				s.formatter.Info(fmt.Sprintf("Content selector %s is up to date, skipping...", sel.Name))
+				continue
+			}
+			if err := s.client.UpdateContentSelector(sel.Name, req); err != nil {
+				return count, err
+			}
+			s.formatter.Success(fmt.Sprintf("Updated content selector: %s", sel.Name))
+		} else {
+			if err := s.client.CreateContentSelector(req); err != nil {
+				return count, err
+			}
+			s.formatter.Success(fmt.Sprintf("Created content selector: %s", sel.Name))
+		}
+		count++
+	}
+	return count, nil
+}
+
+// applyRoutingRules 应用路由规则配置，已存在且有变化的规则会被更新
+func (s *ApplyService) applyRoutingRules() (int, error) {
+	if len(s.config.RoutingRules) == 0 {
+		return 0, nil
+	}
+	s.formatter.Info("Applying routing rules...")
+	count := 0
+	for _, rule := range s.config.RoutingRules {
+		exists, err := s.client.RoutingRuleExists(rule.Name)
+		if err != nil {
+			return count, fmt.Errorf("failed to check routing rule %s: %w", rule.Name, err)
+		}
+
+		req := nexus.RoutingRuleRequest{
+			Name:        rule.Name,
+			Description: rule.Description,
+			Mode:        strings.ToUpper(rule.Mode),
+			Matchers:    rule.Matchers,
+		}
+
+		if exists {
+			existing, err := s.client.GetRoutingRule(rule.Name)
+			if err != nil {
+				return count, err
+			}
+			if routingRuleUpToDate(existing, req) {
+				s.formatter.Info(fmt.Sprintf("Routing rule %s is up to date, skipping...", rule.Name))
+				continue
+			}
+			if err := s.client.UpdateRoutingRule(rule.Name, req); err != nil {
+				return count, err
+			}
+			s.formatter.Success(fmt.Sprintf("Updated routing rule: %s", rule.Name))
+		} else {
+			if err := s.client.CreateRoutingRule(req); err != nil {
+				return count, err
+			}
+			s.formatter.Success(fmt.Sprintf("Created routing rule: %s", rule.Name))
+		}
+		count++
+	}
+	return count, nil
+}
+
+// routingRuleUpToDate 检查服务器上的路由规则是否与期望的一致
+func routingRuleUpToDate(existing *nexus.RoutingRuleRequest, req nexus.RoutingRuleRequest) bool {
+	return existing.Description == req.Description &&
+		strings.EqualFold(existing.Mode, req.Mode) &&
+		reflect.DeepEqual(existing.Matchers, req.Matchers)
+}
+
+// applyTasks 应用计划任务配置，按名称匹配服务器上的任务，已存在且有变化的任务会被更新
+func (s *ApplyService) applyTasks() (int, error) {
+	if len(s.config.Tasks) == 0 {
+		return 0, nil
+	}
+	s.formatter.Info("Applying tasks...")
+
+	existing, err := s.client.Tasks(context.Background(), "").All()
+	if err != nil {
+		return 0, err
+	}
+	byName := make(map[string]nexus.Task, len(existing))
+	for _, t := range existing {
+		byName[t.Name] = t
+	}
+
+	count := 0
+	for _, task := range s.config.Tasks {
+		req, err := BuildTaskRequest(task)
+		if err != nil {
+			return count, err
+		}
+		if current, ok := byName[task.Name]; ok {
+			if current.Type != task.Type {
+				return count, fmt.Errorf("task %s already exists with type %s", task.Name, current.Type)
+			}
+			settings, err := s.client.GetTaskSettings(context.Background(), current.ID)
+			if err != nil {
+				return count, err
+			}
+			if taskUpToDate(settings, req) {
+				s.formatter.Info(fmt.Sprintf("Task %s is up to date, skipping...", task.Name))
+				continue
+			}
+			if err := s.client.UpdateTask(current.ID, req); err != nil {
+				return count, err
+			}
+			s.formatter.Success(fmt.Sprintf("Updated task: %s", task.Name))
+		} else {
+			if err := s.client.CreateTask(req); err != nil {
+				return count, err
+			}
+			s.formatter.Success(fmt.Sprintf("Created task: %s (%s)", task.Name, task.Type))
+		}
+		count++
+	}
+	return count, nil
+}
+
+// taskUpToDate 检查服务器上的任务设置是否与期望的一致
+//
+// Nexus 未返回任务设置时无法比较，视为需要更新；Nexus 会补充任务参数的默认值，只比较配置中的参数。
+func taskUpToDate(current *nexus.TaskSettings, req nexus.TaskRequest) bool {
+	if current.Enabled == nil || current.Frequency == nil {
+		return false
+	}
+	if *current.Enabled != req.Enabled || current.AlertEmail != req.AlertEmail ||
+		current.NotificationCondition != req.NotificationCondition {
+		return false
+	}
+	freq, want := *current.Frequency, req.Frequency
+	if freq.Schedule != want.Schedule || freq.StartDate != want.StartDate || freq.TimeZoneOffset != want.TimeZoneOffset ||
+		freq.CronExpression != want.CronExpression || fmt.Sprint(freq.RecurringDays) != fmt.Sprint(want.RecurringDays) {
+		return false
+	}
+	for key, value := range req.Properties {
+		currentValue, ok := current.Properties[key]
+		if !ok || fmt.Sprint(currentValue) != value {
+			return false
+		}
+	}
+	return true
+}
+
+// BuildTaskRequest 将计划任务配置转换为 API 请求
+func BuildTaskRequest(task config.Task) (nexus.TaskRequest, error) {
+	req := nexus.TaskRequest{
+		Type:                  task.Type,
+		Name:                  task.Name,
+		Enabled:               task.Enabled == nil || *task.Enabled,
+		AlertEmail:            task.AlertEmail,
+		NotificationCondition: task.NotificationCondition,
+		Properties:            task.Properties,
+		Frequency: nexus.TaskFrequency{
+			Schedule:       task.Schedule.Type,
+			RecurringDays:  task.Schedule.Days,
+			CronExpression: task.Schedule.Cron,
+		},
+	}
+	if req.Frequency.Schedule == "" {
+		req.Frequency.Schedule = config.TaskScheduleManual
+	}
+	if req.NotificationCondition == "" {
+		req.NotificationCondition = "FAILURE"
+	}
+	if task.Schedule.Start != "" {
+		start, err := time.Parse(time.RFC3339, task.Schedule.Start)
+		if err != nil {
+			return req, fmt.Errorf("task %s: invalid schedule.start: %w", task.Name, err)
+		}
+		req.Frequency.StartDate = start.Unix()
+		req.Frequency.TimeZoneOffset = start.Format("-07:00")
+	}
+	return req, nil
+}
+
+// applyPrivileges 应用权限配置，已存在且有变化的权限会被更新
 func (s *ApplyService) applyPrivileges() (int, error) {
 	s.formatter.Info("Applying privileges...")
 	count := 0
@@ -111,17 +639,30 @@ func (s *ApplyService) applyPrivileges() (int, error) {
 			return count, fmt.Errorf("failed to check privilege %s: %w", priv.Name, err)
 		}
 
-		req := nexus.PrivilegeRequest{
-			Name:        priv.Name,
-			Description: priv.Description,
-			Type:        priv.Type,
-			Format:      priv.Format,
-			Repository:  priv.Repository,
-			Actions:     priv.Actions,
-		}
+		req := buildPrivilegeRequest(priv)
 
 		if exists {
-			s.formatter.Info(fmt.Sprintf("Privilege %s already exists, skipping...", priv.Name))
+			existing, err := s.client.GetPrivilege(priv.Name)
+			if err != nil {
+				return count, fmt.Errorf("failed to get privilege %s: %w", priv.Name, err)
+			}
+			if existing.ReadOnly {
+				s.formatter.Warning(fmt.Sprintf("Privilege %s is read-only (built-in), skipping...", priv.Name))
+				continue
+			}
+			if existing.Type != req.Type {
+				return count, fmt.Errorf("privilege %s already exists with type %s, cannot change it to %s",
+					priv.Name, existing.Type, req.Type)
+			}
+			if privilegeUpToDate(existing, req) {
+				s.formatter.Info(fmt.Sprintf("Privilege %s is up to date, skipping...", priv.Name))
+				continue
+			}
+			if err := s.client.UpdatePrivilege(priv.Name, req); err != nil {
+				return count, fmt.Errorf("failed to update privilege %s: %w", priv.Name, err)
+			}
+			s.formatter.Success(fmt.Sprintf("Updated privilege: %s", priv.Name))
+			count++
 			continue
 		}
 
@@ -134,11 +675,70 @@ func (s *ApplyService) applyPrivileges() (int, error) {
 	return count, nil
 }
 
+// buildPrivilegeRequest 将权限配置转换为 API 请求
+func buildPrivilegeRequest(priv config.Privilege) nexus.PrivilegeRequest {
+	actions := make([]string, 0, len(priv.Actions))
+	for _, action := range priv.Actions {
+		actions = append(actions, strings.ToUpper(action))
+	}
+	return nexus.PrivilegeRequest{
+		Name:            priv.Name,
+		Description:     priv.Description,
+		Type:            priv.Type,
+		Format:          priv.Format,
+		Repository:      priv.Repository,
+		Actions:         actions,
+		Pattern:         priv.Pattern,
+		Domain:          priv.Domain,
+		ContentSelector: priv.ContentSelector,
+		ScriptName:      priv.ScriptName,
+	}
+}
+
+// privilegeUpToDate 比较现有权限与期望配置是否一致
+func privilegeUpToDate(existing *nexus.PrivilegeResponse, req nexus.PrivilegeRequest) bool {
+	return existing.Description == req.Description &&
+		existing.Format == req.Format &&
+		existing.Repository == req.Repository &&
+		existing.Pattern == req.Pattern &&
+		existing.Domain == req.Domain &&
+		existing.ContentSelector == req.ContentSelector &&
+		existing.ScriptName == req.ScriptName &&
+		sameStringSet(existing.Actions, req.Actions)
+}
+
+// sameStringSet 忽略顺序和大小写比较两个字符串集合
+func sameStringSet(a, b []string) bool {
+	set := make(map[string]int)
+	for _, v := range a {
+		set[strings.ToUpper(v)]++
+	}
+	for _, v := range b {
+		set[strings.ToUpper(v)]--
+	}
+	for _, n := range set {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
+
 // applyRoles 应用角色配置
 func (s *ApplyService) applyRoles() (int, error) {
 	s.formatter.Info("Applying roles...")
+	roles, err := s.roleApplyOrder()
+	if err != nil {
+		return 0, err
+	}
 	count := 0
-	for _, role := range s.config.Roles {
+	for _, role := range roles {
+		if role.Source == config.SourceLDAP {
+			if err := s.checkExternalRoleMapping(role); err != nil {
+				return count, fmt.Errorf("failed to check LDAP group %s: %w", role.ID, err)
+			}
+		}
+
 		exists, err := s.client.RoleExists(role.ID)
 		if err != nil {
 			return count, fmt.Errorf("failed to check role %s: %w", role.ID, err)
@@ -149,10 +749,15 @@ func (s *ApplyService) applyRoles() (int, error) {
 			Name:        role.Name,
 			Description: role.Description,
 			Privileges:  role.Privileges,
-			Roles:       role.Roles,
+			Roles:       s.desiredRoleMembers(role),
 		}
 
 		if exists {
+			existing, err := s.client.GetRole(role.ID)
+			if err != nil {
+				return count, fmt.Errorf("failed to get role %s: %w", role.ID, err)
+			}
+			req.Roles = s.keepOtherPermissionRoles(req.Roles, existing.Roles)
 			if err := s.client.UpdateRole(role.ID, req); err != nil {
 				return count, fmt.Errorf("failed to update role %s: %w", role.ID, err)
 			}
@@ -168,6 +773,48 @@ func (s *ApplyService) applyRoles() (int, error) {
 	return count, nil
 }
 
+// roleApplyOrder 合并配置和服务器上的角色检查角色层级，返回子角色在前的应用顺序
+//
+// Nexus 要求子角色已存在，提前检查环和不存在的子角色可以避免只应用了一部分角色。
+func (s *ApplyService) roleApplyOrder() ([]config.Role, error) {
+	existing, err := s.client.ListRoles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+
+	graph := rolegraph.New()
+	for _, role := range existing {
+		graph.AddServerRole(role.ID, role.Roles)
+	}
+	declared := make(map[string]config.Role)
+	for _, role := range s.config.Roles {
+		graph.AddConfigRole(role.ID, s.desiredRoleMembers(role))
+		declared[role.ID] = role
+	}
+
+	var problems []string
+	for _, ref := range graph.Orphans() {
+		if _, ok := declared[ref.Role]; ok {
+			problems = append(problems, fmt.Sprintf("role %s contains role %s which does not exist", ref.Role, ref.Missing))
+		}
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid role hierarchy: %s", strings.Join(problems, "; "))
+	}
+	order, err := graph.Order()
+	if err != nil {
+		return nil, fmt.Errorf("invalid role hierarchy: %w", err)
+	}
+
+	var roles []config.Role
+	for _, id := range order {
+		if role, ok := declared[id]; ok {
+			roles = append(roles, role)
+		}
+	}
+	return roles, nil
+}
+
 // applyRepositories 应用仓库配置
 func (s *ApplyService) applyRepositories() (int, error) {
 	s.formatter.Info("Applying repositories...")
@@ -178,8 +825,22 @@ func (s *ApplyService) applyRepositories() (int, error) {
 			return count, fmt.Errorf("failed to check repository %s: %w", repo.Name, err)
 		}
 
+		if err := s.checkRepositoryRoutingRule(repo); err != nil {
+			return count, err
+		}
+
 		if exists {
-			s.formatter.Info(fmt.Sprintf("Repository %s already exists, skipping...", repo.Name))
+			changed, err := s.applyRepositoryOnline(repo)
+			if err != nil {
+				return count, err
+			}
+			ruleChanged, err := s.applyRepositoryRoutingRule(repo)
+			if err != nil {
+				return count, err
+			}
+			if !changed && !ruleChanged {
+				s.formatter.Info(fmt.Sprintf("Repository %s already exists, skipping...", repo.Name))
+			}
 			continue
 		}
 
@@ -192,11 +853,107 @@ func (s *ApplyService) applyRepositories() (int, error) {
 	return count, nil
 }
 
+// applyRepositoryOnline 按配置中的 online 让已存在的仓库上线或下线，返回状态是否发生变化
+func (s *ApplyService) applyRepositoryOnline(repo config.Repository) (bool, error) {
+	if repo.Online == nil {
+		return false, nil
+	}
+	changed, err := s.client.SetRepositoryOnline(repo.Name, *repo.Online, upstreamPassword(repo))
+	if err != nil || !changed {
+		return false, err
+	}
+	if *repo.Online {
+		s.formatter.Success(fmt.Sprintf("Brought repository online: %s", repo.Name))
+	} else {
+		s.formatter.Success(fmt.Sprintf("Took repository offline: %s", repo.Name))
+	}
+	return true, nil
+}
+
+// applyRepositoryRoutingRule 让已存在的仓库使用配置中的路由规则，返回是否发生变化
+//
+// 未配置 routingRule 时不修改仓库当前的路由规则。
+func (s *ApplyService) applyRepositoryRoutingRule(repo config.Repository) (bool, error) {
+	if repo.RoutingRule == "" {
+		return false, nil
+	}
+	changed, err := s.client.SetRepositoryRoutingRule(repo.Name, repo.RoutingRule, upstreamPassword(repo))
+	if err != nil || !changed {
+		return false, err
+	}
+	s.formatter.Success(fmt.Sprintf("Set routing rule of repository %s: %s", repo.Name, repo.RoutingRule))
+	return true, nil
+}
+
+// checkRepositoryRoutingRule 检查仓库引用的路由规则在配置中声明或已存在于服务器上
+func (s *ApplyService) checkRepositoryRoutingRule(repo config.Repository) error {
+	if repo.RoutingRule == "" {
+		return nil
+	}
+	for _, rule := range s.config.RoutingRules {
+		if rule.Name == repo.RoutingRule {
+			return nil
+		}
+	}
+	exists, err := s.client.RoutingRuleExists(repo.RoutingRule)
+	if err != nil {
+		return fmt.Errorf("failed to check routing rule %s: %w", repo.RoutingRule, err)
+	}
+	if !exists {
+		return fmt.Errorf("repository %s references routing rule %s, which is neither declared in the config nor exists on the server", repo.Name, repo.RoutingRule)
+	}
+	return nil
+}
+
+// upstreamPassword 返回仓库上游认证的密码
+//
+// Nexus 不返回上游认证的密码，更新已存在的仓库时使用配置中的密码。
+func upstreamPassword(repo config.Repository) string {
+	if repo.Proxy != nil && repo.Proxy.Authentication != nil {
+		return repo.Proxy.Authentication.Password
+	}
+	return ""
+}
+
+// repositoryCreators createRepository 支持的格式和类型及对应的创建方法
+var repositoryCreators = map[string]map[string]func(*nexus.Client, nexus.RepositoryRequest) error{
+	"maven2": {
+		"hosted": (*nexus.Client).CreateMavenHostedRepository,
+		"proxy":  (*nexus.Client).CreateMavenProxyRepository,
+		"group":  (*nexus.Client).CreateMavenGroupRepository,
+	},
+	"docker": {
+		"hosted": (*nexus.Client).CreateDockerHostedRepository,
+		"proxy":  (*nexus.Client).CreateDockerProxyRepository,
+		"group":  (*nexus.Client).CreateDockerGroupRepository,
+	},
+	"npm": {
+		"hosted": (*nexus.Client).CreateNpmHostedRepository,
+		"proxy":  (*nexus.Client).CreateNpmProxyRepository,
+		"group":  (*nexus.Client).CreateNpmGroupRepository,
+	},
+	"pypi": {
+		"hosted": (*nexus.Client).CreatePypiHostedRepository,
+		"proxy":  (*nexus.Client).CreatePypiProxyRepository,
+		"group":  (*nexus.Client).CreatePypiGroupRepository,
+	},
+	"go": {
+		"proxy": (*nexus.Client).CreateGoProxyRepository,
+		"group": (*nexus.Client).CreateGoGroupRepository,
+	},
+}
+
+// SupportsRepository 检查是否支持创建该格式和类型的仓库
+func SupportsRepository(format, repoType string) bool {
+	_, ok := repositoryCreators[format][repoType]
+	return ok
+}
+
 // createRepository 创建仓库
 func (s *ApplyService) createRepository(repo config.Repository) error {
 	req := nexus.RepositoryRequest{
 		Name:   repo.Name,
-		Online: repo.Online,
+		Online: repo.IsOnline(),
 		Storage: map[string]interface{}{
 			"blobStoreName":               repo.Storage.BlobStoreName,
 			"strictContentTypeValidation": repo.Storage.StrictContentTypeValidation,
@@ -215,8 +972,13 @@ func (s *ApplyService) createRepository(repo config.Repository) error {
 			ContentMaxAge:  repo.Proxy.ContentMaxAge,
 			MetadataMaxAge: repo.Proxy.MetadataMaxAge,
 		}
+
+		// 添加必需的代理设置（negativeCache 和 httpClient），未配置时使用默认值
+		req.NegativeCache = buildNegativeCache(repo.NegativeCache)
+		req.HTTPClient = buildHTTPClient(repo.HTTPClient)
 		if repo.Proxy.Authentication != nil {
-			req.Proxy.Authentication = &nexus.Authentication{
+			// Nexus 的上游认证属于 httpClient 设置
+			req.HTTPClient.Authentication = &nexus.Authentication{
 				Type:       repo.Proxy.Authentication.Type,
 				Username:   repo.Proxy.Authentication.Username,
 				Password:   repo.Proxy.Authentication.Password,
@@ -224,20 +986,11 @@ func (s *ApplyService) createRepository(repo config.Repository) error {
 				NtlmDomain: repo.Proxy.Authentication.NtlmDomain,
 			}
 		}
+	}
 
-		// 添加必需的代理设置（negativeCache 和 httpClient）
-		req.NegativeCache = &nexus.NegativeCacheSettings{
-			Enabled:    true,
-			TimeToLive: 1440,
-		}
-		req.HTTPClient = &nexus.HTTPClientSettings{
-			Blocked:   false,
-			AutoBlock: true,
-			Connection: &nexus.HTTPClientConnection{
-				RetryAttempts: 3,
-				Timeout:       60,
-			},
-		}
+	// 路由规则适用于 proxy 和 group 仓库
+	if repo.Type == "proxy" || repo.Type == "group" {
+		req.RoutingRule = repo.RoutingRule
 	}
 
 	// 添加 Maven 配置
@@ -260,8 +1013,26 @@ func (s *ApplyService) createRepository(repo config.Repository) error {
 		if repo.Docker.HTTPSPort > 0 {
 			req.Docker.HTTPSPort = &repo.Docker.HTTPSPort
 		}
-		if repo.Docker.SubdomainAddr != "" {
-			req.Docker.SubdomainAddr = repo.Docker.SubdomainAddr
+		req.Docker.Subdomain = repo.Docker.SubdomainName()
+		req.Docker.PathEnabled = repo.Docker.PathEnabled
+		// latestPolicy 属于 hosted 仓库的存储设置
+		if repo.Type == "hosted" && repo.Docker.LatestPolicy != nil {
+			req.Storage["latestPolicy"] = *repo.Docker.LatestPolicy
+		}
+	}
+
+	// 添加 Docker 代理索引配置（Nexus 要求 docker proxy 必须提供）
+	if repo.Format == "docker" && repo.Type == "proxy" {
+		req.DockerProxy = &nexus.DockerProxySettings{
+			IndexType: config.DockerIndexRegistry,
+		}
+		if repo.DockerProxy != nil {
+			req.DockerProxy = &nexus.DockerProxySettings{
+				IndexType:                repo.DockerProxy.IndexType,
+				IndexURL:                 repo.DockerProxy.IndexURL,
+				CacheForeignLayers:       repo.DockerProxy.CacheForeignLayers,
+				ForeignLayerURLWhitelist: repo.DockerProxy.ForeignLayerURLWhitelist,
+			}
 		}
 	}
 
@@ -273,199 +1044,66 @@ func (s *ApplyService) createRepository(repo config.Repository) error {
 	}
 
 	// 根据格式和类型调用相应的创建方法
-	switch repo.Format {
-	case "maven2":
-		switch repo.Type {
-		case "hosted":
-			return s.client.CreateMavenHostedRepository(req)
-		case "proxy":
-			return s.client.CreateMavenProxyRepository(req)
-		case "group":
-			return s.client.CreateMavenGroupRepository(req)
-		}
-	case "docker":
-		switch repo.Type {
-		case "hosted":
-			return s.client.CreateDockerHostedRepository(req)
-		case "proxy":
-			return s.client.CreateDockerProxyRepository(req)
-		case "group":
-			return s.client.CreateDockerGroupRepository(req)
-		}
-	case "npm":
-		switch repo.Type {
-		case "hosted":
-			return s.client.CreateNpmHostedRepository(req)
-		case "proxy":
-			return s.client.CreateNpmProxyRepository(req)
-		case "group":
-			return s.client.CreateNpmGroupRepository(req)
-		}
-	case "pypi":
-		switch repo.Type {
-		case "hosted":
-			return s.client.CreatePypiHostedRepository(req)
-		case "proxy":
-			return s.client.CreatePypiProxyRepository(req)
-		case "group":
-			return s.client.CreatePypiGroupRepository(req)
-		}
-	case "go":
-		switch repo.Type {
-		case "proxy":
-			return s.client.CreateGoProxyRepository(req)
-		case "group":
-			return s.client.CreateGoGroupRepository(req)
-		default:
-			return fmt.Errorf("go format only supports proxy and group types")
-		}
-	default:
+	types, ok := repositoryCreators[repo.Format]
+	if !ok {
 		return fmt.Errorf("unsupported repository format: %s", repo.Format)
 	}
-
-	return fmt.Errorf("unsupported repository type: %s for format: %s", repo.Type, repo.Format)
+	create, ok := types[repo.Type]
+	if !ok {
+		return fmt.Errorf("unsupported repository type: %s for format: %s", repo.Type, repo.Format)
+	}
+	return create(s.client, req)
 }
 
-// applyUsers 应用用户配置
-func (s *ApplyService) applyUsers() (int, error) {
-	s.formatter.Info("Applying users...")
-	count := 0
-	for _, user := range s.config.Users {
-		exists, err := s.client.UserExists(user.ID)
-		if err != nil {
-			return count, fmt.Errorf("failed to check user %s: %w", user.ID, err)
-		}
-
-		req := nexus.UserRequest{
-			UserID:       user.ID,
-			FirstName:    user.FirstName,
-			LastName:     user.LastName,
-			EmailAddress: user.EmailAddress,
-			Status:       user.Status,
-			Roles:        user.Roles,
-		}
-
-		if exists {
-			// 获取现有用户信息以保留 Source 字段
-			existingUser, err := s.client.GetUser(user.ID)
-			if err != nil {
-				return count, fmt.Errorf("failed to get existing user %s: %w", user.ID, err)
-			}
-			req.Source = existingUser.Source
-
-			if err := s.client.UpdateUser(user.ID, req); err != nil {
-				return count, fmt.Errorf("failed to update user %s: %w", user.ID, err)
-			}
-			s.formatter.Success(fmt.Sprintf("Updated user: %s", user.ID))
-
-			// 更新密码（如果提供）
-			if user.Password != "" {
-				if err := s.client.ChangePassword(user.ID, user.Password); err != nil {
-					s.formatter.Warning(fmt.Sprintf("Failed to change password for user %s: %v", user.ID, err))
-				}
-			}
-		} else {
-			req.Password = user.Password
-			if err := s.client.CreateUser(req); err != nil {
-				return count, fmt.Errorf("failed to create user %s: %w", user.ID, err)
-			}
-			s.formatter.Success(fmt.Sprintf("Created user: %s", user.ID))
-		}
-		count++
+// buildNegativeCache 构建负缓存设置，未配置的字段使用默认值
+func buildNegativeCache(cfg *config.NegativeCacheConfig) *nexus.NegativeCacheSettings {
+	settings := &nexus.NegativeCacheSettings{
+		Enabled:    true,
+		TimeToLive: config.DefaultNegativeCacheTTL,
 	}
-	return count, nil
+	if cfg == nil {
+		return settings
+	}
+	if cfg.Enabled != nil {
+		settings.Enabled = *cfg.Enabled
+	}
+	if cfg.TimeToLive != nil {
+		settings.TimeToLive = *cfg.TimeToLive
+	}
+	return settings
 }
 
-// applyUserRepositoryPermissions 应用用户仓库权限
-func (s *ApplyService) applyUserRepositoryPermissions() (int, error) {
-	s.formatter.Info("Applying user repository permissions...")
-	count := 0
-
-	// 为每个用户仓库权限映射创建专门的角色
-	for _, perm := range s.config.UserRepositoryPermissions {
-		roleName := fmt.Sprintf("%s-%s-role", perm.UserID, perm.Repository)
-
-		// 获取仓库信息以确定 format
-		repo, err := s.client.GetRepository(perm.Repository)
-		if err != nil {
-			return count, fmt.Errorf("failed to get repository %s: %w", perm.Repository, err)
-		}
-		repoFormat := ""
-		if formatVal, ok := repo["format"]; ok {
-			if formatStr, ok := formatVal.(string); ok {
-				repoFormat = formatStr
-			}
-		}
-		if repoFormat == "" {
-			return count, fmt.Errorf("failed to determine format for repository %s", perm.Repository)
-		}
-
-		// 构建权限列表
-		var privileges []string
-		for _, action := range perm.Privileges {
-			// 使用 Nexus 内置的权限命名格式: nx-repository-view-{format}-{name}-{action}
-			privName := fmt.Sprintf("nx-repository-view-%s-%s-%s", repoFormat, perm.Repository, strings.ToLower(action))
-			privileges = append(privileges, privName)
-		}
-
-		// 创建或更新角色
-		exists, err := s.client.RoleExists(roleName)
-		if err != nil {
-			return count, fmt.Errorf("failed to check role %s: %w", roleName, err)
-		}
-
-		roleReq := nexus.RoleRequest{
-			ID:          roleName,
-			Name:        fmt.Sprintf("%s access to %s", perm.UserID, perm.Repository),
-			Description: fmt.Sprintf("Auto-generated role for %s to access %s", perm.UserID, perm.Repository),
-			Privileges:  privileges,
-		}
-
-		if exists {
-			if err := s.client.UpdateRole(roleName, roleReq); err != nil {
-				return count, fmt.Errorf("failed to update role %s: %w", roleName, err)
-			}
-			s.formatter.Success(fmt.Sprintf("Updated permission role: %s", roleName))
-		} else {
-			if err := s.client.CreateRole(roleReq); err != nil {
-				return count, fmt.Errorf("failed to create role %s: %w", roleName, err)
-			}
-			s.formatter.Success(fmt.Sprintf("Created permission role: %s", roleName))
-		}
-
-		// 更新用户，添加此角色
-		user, err := s.client.GetUser(perm.UserID)
-		if err != nil {
-			return count, fmt.Errorf("failed to get user %s: %w", perm.UserID, err)
-		}
+// buildHTTPClient 构建 HTTP 客户端设置，未配置的字段使用默认值
+func buildHTTPClient(cfg *config.HTTPClientConfig) *nexus.HTTPClientSettings {
+	timeout := config.DefaultHTTPTimeout
+	retries := config.DefaultHTTPRetries
+	settings := &nexus.HTTPClientSettings{
+		Blocked:   false,
+		AutoBlock: true,
+		Connection: &nexus.HTTPClientConnection{
+			RetryAttempts: &retries,
+			Timeout:       &timeout,
+		},
+	}
+	if cfg == nil {
+		return settings
+	}
 
-		// 检查角色是否已存在
-		roleExists := false
-		for _, r := range user.Roles {
-			if r == roleName {
-				roleExists = true
-				break
-			}
+	settings.Blocked = cfg.Blocked
+	if cfg.AutoBlock != nil {
+		settings.AutoBlock = *cfg.AutoBlock
+	}
+	if conn := cfg.Connection; conn != nil {
+		if conn.Timeout != nil {
+			timeout = *conn.Timeout
 		}
-
-		if !roleExists {
-			user.Roles = append(user.Roles, roleName)
-			userReq := nexus.UserRequest{
-				UserID:       user.UserID,
-				FirstName:    user.FirstName,
-				LastName:     user.LastName,
-				EmailAddress: user.EmailAddress,
-				Status:       user.Status,
-				Source:       user.Source,
-				Roles:        user.Roles,
-			}
-			if err := s.client.UpdateUser(perm.UserID, userReq); err != nil {
-				return count, fmt.Errorf("failed to update user %s with role %s: %w", perm.UserID, roleName, err)
-			}
-			s.formatter.Success(fmt.Sprintf("Assigned role %s to user %s", roleName, perm.UserID))
+		if conn.Retries != nil {
+			retries = *conn.Retries
 		}
-		count++
+		settings.Connection.UserAgentSuffix = conn.UserAgentSuffix
+		settings.Connection.EnableCircularRedirects = conn.EnableCircularRedirects
+		settings.Connection.EnableCookies = conn.EnableCookies
+		settings.Connection.UseTrustStore = conn.UseTrustStore
 	}
-
-	return count, nil
+	return settings
 }
@@ -0,0 +1,437 @@
+package service
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alauda/nexus-cli/pkg/config"
+	"github.com/alauda/nexus-cli/pkg/nexus"
+	"github.com/alauda/nexus-cli/pkg/output"
+)
+
+func TestApplyBlobStores(t *testing.T) {
+	stores := map[string]string{
+		"/service/rest/v1/blobstores/file/default": `{"path":"default"}`,
+		"/service/rest/v1/blobstores/file/data":    `{"path":"/nexus-data/data"}`,
+		"/service/rest/v1/blobstores/s3/s3-store": `{"name":"s3-store","bucketConfiguration":{
+			"bucket":{"region":"us-east-1","name":"nexus","expiration":3},
+			"bucketSecurity":{"accessKeyId":"AKIA","secretAccessKey":"#~NXRM~PLACEHOLDER~PASSWORD~#"}}}`,
+	}
+	updates := make(map[string]nexus.FileBlobStoreRequest)
+	var s3Updates int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/service/rest/v1/blobstores":
+			_, _ = w.Write([]byte(`[{"name":"default","type":"File"},{"name":"data","type":"File"},{"name":"s3-store","type":"S3"}]`))
+		case r.Method == http.MethodPut && r.URL.Path == "/service/rest/v1/blobstores/s3/s3-store":
+			s3Updates++
+		case r.Method == http.MethodPut:
+			var req nexus.FileBlobStoreRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			updates[req.Name] = req
+		default:
+			body, ok := stores[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(body))
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{BlobStores: []config.BlobStore{
+		{Name: "default", Type: "file"},
+		{Name: "data", Type: "file", SoftQuota: &config.SoftQuotaConfig{Type: "spaceUsedQuota", Limit: 1024}},
+		{Name: "s3-store", Type: "s3", S3: &config.S3BlobStoreConfig{Region: "us-east-1", Bucket: "nexus", Expiration: 3, AccessKeyID: "AKIA", SecretAccessKey: "secret"}},
+	}}
+	s := NewApplyService(nexus.NewClient(server.URL, "admin", "admin123"), cfg, output.NewFormatter(output.FormatText, io.Discard))
+	count, err := s.applyBlobStores()
+	if err != nil {
+		t.Fatalf("applyBlobStores() error = %v", err)
+	}
+
+	// 只有软配额变化的 data 需要更新，且保留已有路径
+	if count != 1 || len(updates) != 1 || s3Updates != 0 {
+		t.Fatalf("applyBlobStores() = %d, updates = %v, s3 updates = %d, want only data updated", count, updates, s3Updates)
+	}
+	if data := updates["data"]; data.Path != "/nexus-data/data" || data.SoftQuota == nil || data.SoftQuota.Limit != 1024 {
+		t.Errorf("update of data = %+v, want the existing path and the new soft quota", data)
+	}
+}
+
+func TestApplyRepositoriesRoutingRule(t *testing.T) {
+	responses := map[string]string{
+		"/service/rest/v1/repositories/npm-proxy": `{"name":"npm-proxy","format":"npm","type":"proxy"}`,
+		"/service/rest/v1/repositories/npm/proxy/npm-proxy": `{"name":"npm-proxy","format":"npm","type":"proxy","online":true,
+			"storage":{"blobStoreName":"default"},"proxy":{"remoteUrl":"https://registry.npmjs.org"}}`,
+		"/service/rest/v1/routing-rules/allow-public": `{"name":"allow-public","mode":"ALLOW","matchers":[".*"]}`,
+	}
+	var updates []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			updates = append(updates, body)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+	client := nexus.NewClient(server.URL, "admin", "admin123")
+
+	// 已存在的仓库也应用路由规则，规则可以只存在于服务器上
+	cfg := &config.Config{Repositories: []config.Repository{{Name: "npm-proxy", Format: "npm", Type: "proxy", RoutingRule: "allow-public"}}}
+	s := NewApplyService(client, cfg, output.NewFormatter(output.FormatText, io.Discard))
+	if _, err := s.applyRepositories(); err != nil {
+		t.Fatalf("applyRepositories() error = %v", err)
+	}
+	if len(updates) != 1 || updates[0]["routingRule"] != "allow-public" {
+		t.Errorf("updates = %v, want the routing rule set on npm-proxy", updates)
+	}
+
+	cfg.Repositories[0].RoutingRule = "block-internal"
+	if _, err := s.applyRepositories(); err == nil || !strings.Contains(err.Error(), "neither declared in the config nor exists on the server") {
+		t.Errorf("applyRepositories() error = %v, want the missing routing rule reported", err)
+	}
+	if len(updates) != 1 {
+		t.Errorf("updates = %v, want no update for a missing routing rule", updates)
+	}
+}
+
+func TestApplyRoutingRules(t *testing.T) {
+	rules := map[string]string{
+		"/service/rest/v1/routing-rules/block-internal": `{"name":"block-internal","description":"internal packages","mode":"BLOCK","matchers":["/@example/.*"]}`,
+		"/service/rest/v1/routing-rules/allow-public":   `{"name":"allow-public","mode":"ALLOW","matchers":[".*"]}`,
+	}
+	var updated, created []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			updated = append(updated, r.URL.Path)
+		case http.MethodPost:
+			created = append(created, r.URL.Path)
+		default:
+			body, ok := rules[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(body))
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{RoutingRules: []config.RoutingRule{
+		{Name: "block-internal", Description: "internal packages", Mode: "block", Matchers: []string{"/@example/.*"}},
+		{Name: "allow-public", Mode: "ALLOW", Matchers: []string{"/org/.*"}},
+		{Name: "block-snapshots", Mode: "BLOCK", Matchers: []string{".*-SNAPSHOT.*"}},
+	}}
+	s := NewApplyService(nexus.NewClient(server.URL, "admin", "admin123"), cfg, output.NewFormatter(output.FormatText, io.Discard))
+	count, err := s.applyRoutingRules()
+	if err != nil {
+		t.Fatalf("applyRoutingRules() error = %v", err)
+	}
+
+	// 只有表达式变化的 allow-public 需要更新，模式不区分大小写
+	if count != 2 || len(created) != 1 || !reflect.DeepEqual(updated, []string{"/service/rest/v1/routing-rules/allow-public"}) {
+		t.Errorf("applyRoutingRules() = %d, updated = %v, created = %v, want allow-public updated and block-snapshots created", count, updated, created)
+	}
+}
+
+func TestApplyCleanupPolicies(t *testing.T) {
+	var updated, created []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			updated = append(updated, r.URL.Path)
+		case http.MethodPost:
+			created = append(created, r.URL.Path)
+		default:
+			_, _ = w.Write([]byte(`[
+				{"name":"snapshots-30d","notes":"old snapshots","format":"maven2","criteriaLastDownloaded":30,"criteriaReleaseType":"PRERELEASES","retain":5,"sortBy":"version"},
+				{"name":"all-90d","format":"ALL_FORMATS","criteriaLastBlobUpdated":90}]`))
+		}
+	}))
+	defer server.Close()
+
+	intPtr := func(i int) *int { return &i }
+	cfg := &config.Config{CleanupPolicies: []config.CleanupPolicy{
+		{Name: "snapshots-30d", Notes: "old snapshots", Format: "maven2",
+			Criteria: config.CleanupCriteria{LastDownloaded: intPtr(30), ReleaseType: "PRERELEASES", Retain: 5, SortBy: "version"}},
+		{Name: "all-90d", Format: "*", Criteria: config.CleanupCriteria{LastBlobUpdated: intPtr(60)}},
+		{Name: "npm-30d", Format: "npm", Criteria: config.CleanupCriteria{LastDownloaded: intPtr(30)}},
+	}}
+	s := NewApplyService(nexus.NewClient(server.URL, "admin", "admin123"), cfg, output.NewFormatter(output.FormatText, io.Discard))
+	count, err := s.applyCleanupPolicies()
+	if err != nil {
+		t.Fatalf("applyCleanupPolicies() error = %v", err)
+	}
+
+	// 未变化的 snapshots-30d 不会更新
+	if count != 2 || len(created) != 1 || !reflect.DeepEqual(updated, []string{"/service/rest/v1/cleanup-policies/all-90d"}) {
+		t.Errorf("applyCleanupPolicies() = %d, updated = %v, created = %v, want all-90d updated and npm-30d created", count, updated, created)
+	}
+}
+
+func TestApplyContentSelectors(t *testing.T) {
+	selectors := map[string]string{
+		"/service/rest/v1/security/content-selectors/team-a": `{"name":"team-a","type":"csel","description":"team a","expression":"path =^ \"/com/example/a/\""}`,
+		"/service/rest/v1/security/content-selectors/team-b": `{"name":"team-b","type":"csel","description":"team b","expression":"path =^ \"/com/example/b/\""}`,
+	}
+	var updated, created []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			updated = append(updated, r.URL.Path)
+		case http.MethodPost:
+			created = append(created, r.URL.Path)
+		default:
+			body, ok := selectors[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(body))
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{ContentSelectors: []config.ContentSelector{
+		{Name: "team-a", Description: "team a", Expression: `path =^ "/com/example/a/"`},
+		{Name: "team-b", Description: "team b", Expression: `path =^ "/com/example/b/" or path =^ "/org/example/b/"`},
+		{Name: "team-c", Description: "team c", Expression: `path =^ "/com/example/c/"`},
+	}}
+	s := NewApplyService(nexus.NewClient(server.URL, "admin", "admin123"), cfg, output.NewFormatter(output.FormatText, io.Discard))
+	count, err := s.applyContentSelectors()
+	if err != nil {
+		t.Fatalf("applyContentSelectors() error = %v", err)
+	}
+
+	// 未变化的 team-a 不会更新
+	if count != 2 || len(created) != 1 || !reflect.DeepEqual(updated, []string{"/service/rest/v1/security/content-selectors/team-b"}) {
+		t.Errorf("applyContentSelectors() = %d, updated = %v, created = %v, want team-b updated and team-c created", count, updated, created)
+	}
+}
+
+func TestBuildTaskRequest(t *testing.T) {
+	disabled := false
+	tests := []struct {
+		name    string
+		task    config.Task
+		want    nexus.TaskRequest
+		wantErr bool
+	}{
+		{
+			name: "defaults to manual schedule and failure notification",
+			task: config.Task{Name: "compact", Type: "blobstore.compact", Properties: map[string]string{"blobstoreName": "default"}},
+			want: nexus.TaskRequest{Type: "blobstore.compact", Name: "compact", Enabled: true, NotificationCondition: "FAILURE",
+				Frequency: nexus.TaskFrequency{Schedule: "manual"}, Properties: map[string]string{"blobstoreName": "default"}},
+		},
+		{
+			name: "start date keeps the configured time zone",
+			task: config.Task{Name: "compact", Type: "blobstore.compact", Enabled: &disabled, NotificationCondition: "SUCCESS_FAILURE",
+				Schedule: config.TaskSchedule{Type: "weekly", Start: "2024-01-01T02:00:00+08:00", Days: []int{1, 5}}},
+			want: nexus.TaskRequest{Type: "blobstore.compact", Name: "compact", NotificationCondition: "SUCCESS_FAILURE",
+				Frequency: nexus.TaskFrequency{Schedule: "weekly", StartDate: 1704045600, TimeZoneOffset: "+08:00", RecurringDays: []int{1, 5}}},
+		},
+		{
+			name: "utc start date",
+			task: config.Task{Name: "compact", Type: "blobstore.compact", Schedule: config.TaskSchedule{Type: "daily", Start: "2024-01-01T02:00:00Z"}},
+			want: nexus.TaskRequest{Type: "blobstore.compact", Name: "compact", Enabled: true, NotificationCondition: "FAILURE",
+				Frequency: nexus.TaskFrequency{Schedule: "daily", StartDate: 1704074400, TimeZoneOffset: "+00:00"}},
+		},
+		{
+			name: "cron schedule",
+			task: config.Task{Name: "compact", Type: "blobstore.compact", Schedule: config.TaskSchedule{Type: "cron", Cron: "0 0 2 * * ?"}},
+			want: nexus.TaskRequest{Type: "blobstore.compact", Name: "compact", Enabled: true, NotificationCondition: "FAILURE",
+				Frequency: nexus.TaskFrequency{Schedule: "cron", CronExpression: "0 0 2 * * ?"}},
+		},
+		{
+			name:    "invalid start date",
+			task:    config.Task{Name: "compact", Type: "blobstore.compact", Schedule: config.TaskSchedule{Type: "once", Start: "2024-01-01 02:00"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildTaskRequest(tt.task)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("BuildTaskRequest() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BuildTaskRequest() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BuildTaskRequest() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyTasks(t *testing.T) {
+	tasks := map[string]string{
+		"/service/rest/v1/tasks/1": `{"id":"1","name":"compact-default","type":"blobstore.compact","enabled":true,"notificationCondition":"FAILURE",
+			"frequency":{"schedule":"cron","cronExpression":"0 0 2 * * ?"},"properties":{"blobstoreName":"default","dryRun":false}}`,
+		"/service/rest/v1/tasks/2": `{"id":"2","name":"compact-data","type":"blobstore.compact","enabled":true,"notificationCondition":"FAILURE",
+			"frequency":{"schedule":"cron","cronExpression":"0 0 2 * * ?"},"properties":{"blobstoreName":"data"}}`,
+		// 只返回任务状态的旧版本
+		"/service/rest/v1/tasks/3": `{"id":"3","name":"rebuild-index","type":"repository.rebuild-index","currentState":"WAITING"}`,
+	}
+	var updated []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/service/rest/v1/tasks":
+			_, _ = w.Write([]byte(`{"items":[{"id":"1","name":"compact-default","type":"blobstore.compact"},
+				{"id":"2","name":"compact-data","type":"blobstore.compact"},
+				{"id":"3","name":"rebuild-index","type":"repository.rebuild-index"}]}`))
+		case r.Method == http.MethodPut:
+			updated = append(updated, r.URL.Path)
+		default:
+			body, ok := tasks[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(body))
+		}
+	}))
+	defer server.Close()
+
+	cron := config.TaskSchedule{Type: "cron", Cron: "0 0 2 * * ?"}
+	cfg := &config.Config{Tasks: []config.Task{
+		{Name: "compact-default", Type: "blobstore.compact", Schedule: cron, Properties: map[string]string{"blobstoreName": "default"}},
+		{Name: "compact-data", Type: "blobstore.compact", Schedule: config.TaskSchedule{Type: "cron", Cron: "0 0 3 * * ?"},
+			Properties: map[string]string{"blobstoreName": "data"}},
+		{Name: "rebuild-index", Type: "repository.rebuild-index", Properties: map[string]string{"repositoryName": "maven-central"}},
+	}}
+	s := NewApplyService(nexus.NewClient(server.URL, "admin", "admin123"), cfg, output.NewFormatter(output.FormatText, io.Discard))
+	count, err := s.applyTasks()
+	if err != nil {
+		t.Fatalf("applyTasks() error = %v", err)
+	}
+
+	// compact-default 未变化（Nexus 补充的 dryRun 参数不影响比较），无法比较的 rebuild-index 照常更新
+	if want := []string{"/service/rest/v1/tasks/2", "/service/rest/v1/tasks/3"}; count != 2 || !reflect.DeepEqual(updated, want) {
+		t.Errorf("applyTasks() = %d, updated = %v, want %v", count, updated, want)
+	}
+}
+
+func TestBuildNegativeCache(t *testing.T) {
+	boolPtr := func(b bool) *bool { return &b }
+	intPtr := func(i int) *int { return &i }
+
+	tests := []struct {
+		name string
+		cfg  *config.NegativeCacheConfig
+		want nexus.NegativeCacheSettings
+	}{
+		{name: "not configured", want: nexus.NegativeCacheSettings{Enabled: true, TimeToLive: 1440}},
+		{name: "empty", cfg: &config.NegativeCacheConfig{}, want: nexus.NegativeCacheSettings{Enabled: true, TimeToLive: 1440}},
+		{name: "disabled keeps default ttl", cfg: &config.NegativeCacheConfig{Enabled: boolPtr(false)},
+			want: nexus.NegativeCacheSettings{Enabled: false, TimeToLive: 1440}},
+		{name: "custom ttl", cfg: &config.NegativeCacheConfig{TimeToLive: intPtr(0)},
+			want: nexus.NegativeCacheSettings{Enabled: true, TimeToLive: 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildNegativeCache(tt.cfg); *got != tt.want {
+				t.Errorf("buildNegativeCache() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildHTTPClient(t *testing.T) {
+	boolPtr := func(b bool) *bool { return &b }
+	intPtr := func(i int) *int { return &i }
+
+	tests := []struct {
+		name string
+		cfg  *config.HTTPClientConfig
+		want nexus.HTTPClientSettings
+	}{
+		{
+			name: "not configured",
+			want: nexus.HTTPClientSettings{AutoBlock: true, Connection: &nexus.HTTPClientConnection{RetryAttempts: intPtr(3), Timeout: intPtr(60)}},
+		},
+		{
+			name: "blocked without connection settings",
+			cfg:  &config.HTTPClientConfig{Blocked: true, AutoBlock: boolPtr(false)},
+			want: nexus.HTTPClientSettings{Blocked: true, Connection: &nexus.HTTPClientConnection{RetryAttempts: intPtr(3), Timeout: intPtr(60)}},
+		},
+		{
+			name: "partial connection settings",
+			cfg: &config.HTTPClientConfig{Connection: &config.HTTPConnectionConfig{Timeout: intPtr(120),
+				UserAgentSuffix: "ci", EnableCookies: true, UseTrustStore: true}},
+			want: nexus.HTTPClientSettings{AutoBlock: true, Connection: &nexus.HTTPClientConnection{RetryAttempts: intPtr(3), Timeout: intPtr(120),
+				UserAgentSuffix: "ci", EnableCookies: true, UseTrustStore: true}},
+		},
+		{
+			name: "zero retries",
+			cfg:  &config.HTTPClientConfig{Connection: &config.HTTPConnectionConfig{Retries: intPtr(0), EnableCircularRedirects: true}},
+			want: nexus.HTTPClientSettings{AutoBlock: true, Connection: &nexus.HTTPClientConnection{RetryAttempts: intPtr(0), Timeout: intPtr(60),
+				EnableCircularRedirects: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildHTTPClient(tt.cfg); !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("buildHTTPClient() = %+v (connection %+v), want %+v (connection %+v)", *got, *got.Connection, tt.want, *tt.want.Connection)
+			}
+		})
+	}
+}
+
+func TestCreateProxyRepository(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	repo := config.Repository{Name: "private-proxy", Format: "maven2", Type: "proxy", RoutingRule: "block-internal",
+		Storage: config.StorageConfig{BlobStoreName: "default"},
+		Proxy: &config.ProxyConfig{RemoteURL: "https://repo.example.com/maven2/",
+			Authentication: &config.AuthConfig{Type: "username", Username: "ci", Password: "secret"}},
+		Maven: &config.MavenConfig{VersionPolicy: "RELEASE", LayoutPolicy: "STRICT"},
+	}
+	s := NewApplyService(nexus.NewClient(server.URL, "admin", "admin123"), &config.Config{}, output.NewFormatter(output.FormatText, io.Discard))
+	if err := s.createRepository(repo); err != nil {
+		t.Fatalf("createRepository() error = %v", err)
+	}
+
+	// 上游认证属于 httpClient，未配置的负缓存和连接设置使用默认值
+	proxy, _ := body["proxy"].(map[string]interface{})
+	httpClient, _ := body["httpClient"].(map[string]interface{})
+	auth, _ := httpClient["authentication"].(map[string]interface{})
+	if _, ok := proxy["authentication"]; ok || auth["username"] != "ci" || auth["password"] != "secret" || auth["type"] != "username" {
+		t.Errorf("proxy = %v, httpClient = %v, want the authentication under httpClient", proxy, httpClient)
+	}
+	negativeCache, _ := body["negativeCache"].(map[string]interface{})
+	connection, _ := httpClient["connection"].(map[string]interface{})
+	if negativeCache["timeToLive"] != float64(1440) || connection["timeout"] != float64(60) || connection["retries"] != float64(3) {
+		t.Errorf("negativeCache = %v, connection = %v, want the defaults", negativeCache, connection)
+	}
+	if body["routingRule"] != "block-internal" {
+		t.Errorf("routingRule = %v, want block-internal", body["routingRule"])
+	}
+}
@@ -2,6 +2,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -15,6 +16,9 @@ type DeleteService struct {
 	client    *nexus.Client
 	config    *config.Config
 	formatter *output.Formatter
+
+	// configName 配置名称，与 apply 时相同，用于确定生成的权限角色
+	configName string
 }
 
 // DeleteResult 删除结果
@@ -27,6 +31,7 @@ type DeleteResult struct {
 	RepositoriesDeleted int
 	RolesDeleted        int
 	PrivilegesDeleted   int
+	BlobStoresDeleted   int
 	Errors              []string
 	Warnings            []string
 }
@@ -43,6 +48,11 @@ func NewDeleteService(client *nexus.Client, cfg *config.Config, formatter *outpu
 	}
 }
 
+// SetConfigName 设置配置名称，应与 apply 时使用的名称相同
+func (s *DeleteService) SetConfigName(name string) {
+	s.configName = name
+}
+
 // Delete 删除配置中定义的资源
 func (s *DeleteService) Delete() (*DeleteResult, error) {
 	result := &DeleteResult{
@@ -53,14 +63,28 @@ func (s *DeleteService) Delete() (*DeleteResult, error) {
 	s.formatter.Info("Starting to delete resources...")
 
 	// 删除顺序与创建相反：
+	// 0. 计划任务（任务参数引用仓库和 blob store）
 	// 1. 用户仓库权限（删除自动创建的角色）
 	// 2. 用户
 	// 3. 仓库
 	// 4. 角色
 	// 5. 权限
+	// 6. blob store（仓库删除后才能删除）
+	// 7. 清理策略
+	// 8. 内容选择器（权限删除后才能删除）
+	// 9. 路由规则（仓库删除后才能删除）
+	// 10. LDAP 服务器
+
+	// 0. 删除计划任务
+	count, err := s.deleteTasks()
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result, fmt.Errorf("failed to delete tasks: %w", err)
+	}
+	result.Success += count
 
 	// 1. 删除用户仓库权限相关的角色
-	count, err := s.deleteUserRepositoryPermissionRoles()
+	count, err = s.deleteUserRepositoryPermissionRoles()
 	if err != nil {
 		result.Errors = append(result.Errors, err.Error())
 		s.formatter.Warning(fmt.Sprintf("Failed to delete some permission roles: %v", err))
@@ -103,18 +127,64 @@ func (s *DeleteService) Delete() (*DeleteResult, error) {
 	result.PrivilegesDeleted = count
 	result.Success += count
 
+	// 6. 删除 blob store
+	count, err = s.deleteBlobStores()
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result, fmt.Errorf("failed to delete blob stores: %w", err)
+	}
+	result.BlobStoresDeleted = count
+	result.Success += count
+
+	// 7. 删除清理策略
+	count, err = s.deleteCleanupPolicies()
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result, fmt.Errorf("failed to delete cleanup policies: %w", err)
+	}
+	result.Success += count
+
+	// 8. 删除内容选择器
+	count, err = s.deleteContentSelectors()
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result, fmt.Errorf("failed to delete content selectors: %w", err)
+	}
+	result.Success += count
+
+	// 9. 删除路由规则
+	count, err = s.deleteRoutingRules()
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result, fmt.Errorf("failed to delete routing rules: %w", err)
+	}
+	result.Success += count
+
+	// 10. 删除 LDAP 服务器
+	count, err = s.deleteLDAPServers()
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result, fmt.Errorf("failed to delete LDAP servers: %w", err)
+	}
+	result.Success += count
+
 	result.Total = result.Success + result.Failed + result.Skipped
 	s.formatter.Success("Resources deleted successfully!")
 	return result, nil
 }
 
-// deleteUserRepositoryPermissionRoles 删除自动创建的用户仓库权限角色
+// deleteUserRepositoryPermissionRoles 删除自动创建的仓库权限角色（每个授予对象一个）
 func (s *DeleteService) deleteUserRepositoryPermissionRoles() (int, error) {
 	s.formatter.Info("Deleting user repository permission roles...")
 	count := 0
 
+	seen := make(map[string]bool)
 	for _, perm := range s.config.UserRepositoryPermissions {
-		roleName := fmt.Sprintf("%s-%s-role", perm.UserID, perm.Repository)
+		roleName := granteeOf(perm).roleName(s.configName)
+		if seen[roleName] {
+			continue
+		}
+		seen[roleName] = true
 
 		exists, err := s.client.RoleExists(roleName)
 		if err != nil {
@@ -145,6 +215,11 @@ func (s *DeleteService) deleteUsers() (int, error) {
 	count := 0
 
 	for _, user := range s.config.Users {
+		if user.IsExternal() {
+			s.formatter.Info(fmt.Sprintf("User %s comes from %s and cannot be deleted, skipping...", user.ID, user.Source))
+			continue
+		}
+
 		exists, err := s.client.UserExists(user.ID)
 		if err != nil {
 			return count, fmt.Errorf("failed to check user %s: %w", user.ID, err)
@@ -273,3 +348,209 @@ func (s *DeleteService) deletePrivileges() (int, error) {
 
 	return count, nil
 }
+
+// deleteBlobStores 删除 blob store，仍被仓库使用的 blob store 不会删除
+func (s *DeleteService) deleteBlobStores() (int, error) {
+	if len(s.config.BlobStores) == 0 {
+		return 0, nil
+	}
+	s.formatter.Info("Deleting blob stores...")
+	count := 0
+
+	for _, store := range s.config.BlobStores {
+		exists, err := s.client.BlobStoreExists(store.Name)
+		if err != nil {
+			return count, fmt.Errorf("failed to check blob store %s: %w", store.Name, err)
+		}
+
+		if !exists {
+			s.formatter.Info(fmt.Sprintf("Blob store %s does not exist, skipping...", store.Name))
+			continue
+		}
+
+		usedBy, err := s.client.BlobStoreUsage(store.Name)
+		if err != nil {
+			return count, fmt.Errorf("failed to check usage of blob store %s: %w", store.Name, err)
+		}
+		if len(usedBy) > 0 {
+			s.formatter.Warning(fmt.Sprintf("Cannot delete blob store %s: still in use by repositories: %s",
+				store.Name, strings.Join(usedBy, ", ")))
+			continue
+		}
+
+		if err := s.client.DeleteBlobStore(store.Name); err != nil {
+			return count, fmt.Errorf("failed to delete blob store %s: %w", store.Name, err)
+		}
+
+		s.formatter.Success(fmt.Sprintf("Deleted blob store: %s", store.Name))
+		count++
+	}
+
+	return count, nil
+}
+
+// deleteCleanupPolicies 删除清理策略
+func (s *DeleteService) deleteCleanupPolicies() (int, error) {
+	if len(s.config.CleanupPolicies) == 0 {
+		return 0, nil
+	}
+	s.formatter.Info("Deleting cleanup policies...")
+
+	existing, err := s.client.ListCleanupPolicies()
+	if err != nil {
+		return 0, err
+	}
+	existingNames := make(map[string]bool, len(existing))
+	for _, p := range existing {
+		existingNames[p.Name] = true
+	}
+
+	count := 0
+	for _, policy := range s.config.CleanupPolicies {
+		if !existingNames[policy.Name] {
+			s.formatter.Info(fmt.Sprintf("Cleanup policy %s does not exist, skipping...", policy.Name))
+			continue
+		}
+
+		if err := s.client.DeleteCleanupPolicy(policy.Name); err != nil {
+			return count, err
+		}
+
+		s.formatter.Success(fmt.Sprintf("Deleted cleanup policy: %s", policy.Name))
+		count++
+	}
+
+	return count, nil
+}
+
+// deleteContentSelectors 删除内容选择器
+func (s *DeleteService) deleteContentSelectors() (int, error) {
+	if len(s.config.ContentSelectors) == 0 {
+		return 0, nil
+	}
+	s.formatter.Info("Deleting content selectors...")
+	count := 0
+
+	for _, sel := range s.config.ContentSelectors {
+		exists, err := s.client.ContentSelectorExists(sel.Name)
+		if err != nil {
+			return count, fmt.Errorf("failed to check content selector %s: %w", sel.Name, err)
+		}
+
+		if !exists {
+			s.formatter.Info(fmt.Sprintf("Content selector %s does not exist, skipping...", sel.Name))
+			continue
+		}
+
+		if err := s.client.DeleteContentSelector(sel.Name); err != nil {
+			// 仍被权限引用的内容选择器无法删除
+			if strings.Contains(err.Error(), "in use") {
+				s.formatter.Warning(fmt.Sprintf("Cannot delete content selector %s: still in use by privileges", sel.Name))
+				continue
+			}
+			return count, err
+		}
+
+		s.formatter.Success(fmt.Sprintf("Deleted content selector: %s", sel.Name))
+		count++
+	}
+
+	return count, nil
+}
+
+// deleteRoutingRules 删除路由规则
+func (s *DeleteService) deleteRoutingRules() (int, error) {
+	if len(s.config.RoutingRules) == 0 {
+		return 0, nil
+	}
+	s.formatter.Info("Deleting routing rules...")
+	count := 0
+
+	for _, rule := range s.config.RoutingRules {
+		exists, err := s.client.RoutingRuleExists(rule.Name)
+		if err != nil {
+			return count, fmt.Errorf("failed to check routing rule %s: %w", rule.Name, err)
+		}
+
+		if !exists {
+			s.formatter.Info(fmt.Sprintf("Routing rule %s does not exist, skipping...", rule.Name))
+			continue
+		}
+
+		if err := s.client.DeleteRoutingRule(rule.Name); err != nil {
+			// 仍被仓库引用的路由规则无法删除
+			if strings.Contains(err.Error(), "in use") || strings.Contains(err.Error(), "assigned") {
+				s.formatter.Warning(fmt.Sprintf("Cannot delete routing rule %s: still assigned to repositories", rule.Name))
+				continue
+			}
+			return count, err
+		}
+
+		s.formatter.Success(fmt.Sprintf("Deleted routing rule: %s", rule.Name))
+		count++
+	}
+
+	return count, nil
+}
+
+// deleteLDAPServers 删除 LDAP 服务器
+func (s *DeleteService) deleteLDAPServers() (int, error) {
+	if s.config.LDAP == nil || len(s.config.LDAP.Servers) == 0 {
+		return 0, nil
+	}
+	s.formatter.Info("Deleting LDAP servers...")
+	count := 0
+
+	for _, server := range s.config.LDAP.Servers {
+		exists, err := s.client.LDAPServerExists(server.Name)
+		if err != nil {
+			return count, fmt.Errorf("failed to check LDAP server %s: %w", server.Name, err)
+		}
+
+		if !exists {
+			s.formatter.Info(fmt.Sprintf("LDAP server %s does not exist, skipping...", server.Name))
+			continue
+		}
+
+		if err := s.client.DeleteLDAPServer(server.Name); err != nil {
+			return count, err
+		}
+
+		s.formatter.Success(fmt.Sprintf("Deleted LDAP server: %s", server.Name))
+		count++
+	}
+
+	return count, nil
+}
+
+// deleteTasks 删除计划任务，按名称匹配服务器上的任务
+func (s *DeleteService) deleteTasks() (int, error) {
+	if len(s.config.Tasks) == 0 {
+		return 0, nil
+	}
+	s.formatter.Info("Deleting tasks...")
+
+	existing, err := s.client.Tasks(context.Background(), "").All()
+	if err != nil {
+		return 0, err
+	}
+	byName := make(map[string]nexus.Task, len(existing))
+	for _, t := range existing {
+		byName[t.Name] = t
+	}
+
+	count := 0
+	for _, task := range s.config.Tasks {
+		current, ok := byName[task.Name]
+		if !ok {
+			s.formatter.Info(fmt.Sprintf("Task %s does not exist, skipping...", task.Name))
+			continue
+		}
+		if err := s.client.DeleteTask(current.ID); err != nil {
+			return count, err
+		}
+		s.formatter.Success(fmt.Sprintf("Deleted task: %s", task.Name))
+		count++
+	}
+	return count, nil
+}
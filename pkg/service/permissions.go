@@ -0,0 +1,606 @@
+package service
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/alauda/nexus-cli/pkg/config"
+	"github.com/alauda/nexus-cli/pkg/nexus"
+)
+
+// permissionRoleDescriptionPrefix 自动生成的权限角色描述前缀，用于识别由 nexus-cli 管理的角色
+const permissionRoleDescriptionPrefix = "Auto-generated role for "
+
+// 仓库权限授予对象类型
+const (
+	granteeUser  = "user"
+	granteeRole  = "role"
+	granteeGroup = "group"
+)
+
+// grantee 仓库权限的授予对象
+type grantee struct {
+	kind string
+	id   string
+}
+
+// granteeOf 返回权限条目的授予对象
+func granteeOf(perm config.UserRepositoryPermission) grantee {
+	switch {
+	case perm.RoleID != "":
+		return grantee{kind: granteeRole, id: perm.RoleID}
+	case perm.Group != "":
+		return grantee{kind: granteeGroup, id: perm.Group}
+	default:
+		return grantee{kind: granteeUser, id: perm.UserID}
+	}
+}
+
+// label 返回授予对象在角色描述中的名称
+func (g grantee) label() string {
+	if g.kind == granteeUser {
+		return g.id
+	}
+	return g.kind + " " + g.id
+}
+
+// roleName 返回授予对象在指定配置中的仓库权限角色名称
+//
+// 每个配置文件为每个授予对象生成一个角色，多个配置文件授予同一对象时互不覆盖。
+func (g grantee) roleName(configName string) string {
+	name := g.id
+	if g.kind != granteeUser {
+		name = g.kind + "-" + g.id
+	}
+	if configName != "" {
+		name += "-" + configName
+	}
+	return name + "-repository-role"
+}
+
+// granteeFromLabel 从角色描述中的名称还原授予对象，与 label 相反
+func granteeFromLabel(label string) grantee {
+	for _, kind := range []string{granteeRole, granteeGroup} {
+		if id, ok := strings.CutPrefix(label, kind+" "); ok {
+			return grantee{kind: kind, id: id}
+		}
+	}
+	return grantee{kind: granteeUser, id: label}
+}
+
+// permissionRoleDescription 返回自动生成的权限角色的描述，记录授予对象、仓库和生成该角色的配置
+func permissionRoleDescription(g grantee, repositories []string, configName string) string {
+	description := fmt.Sprintf("%s%s to access %s", permissionRoleDescriptionPrefix, g.label(), strings.Join(repositories, ", "))
+	if configName != "" {
+		description += fmt.Sprintf(" (config %s)", configName)
+	}
+	return description
+}
+
+// permissionRole 服务器上自动生成的权限角色
+type permissionRole struct {
+	grantee      grantee
+	repositories []string
+	// configName 生成该角色的配置，旧版本生成的角色为空
+	configName string
+}
+
+// parsePermissionRole 检查角色是否为自动生成的权限角色，并从描述中还原授予对象、仓库和所属配置
+//
+// 角色 ID 必须与描述对应的角色 ID 完全一致（或为旧版本按用户和仓库生成的 <user>-<repo>-role），
+// 描述只用于还原信息并作为第二重检查，因此手动创建的角色即使描述相似也不会被当作自动生成的角色删除。
+func parsePermissionRole(role nexus.RoleResponse) (*permissionRole, bool) {
+	rest, ok := strings.CutPrefix(role.Description, permissionRoleDescriptionPrefix)
+	if !ok {
+		return nil, false
+	}
+	label, repos, ok := strings.Cut(rest, " to access ")
+	if !ok {
+		return nil, false
+	}
+	p := &permissionRole{grantee: granteeFromLabel(label)}
+	if i := strings.LastIndex(repos, " (config "); i >= 0 && strings.HasSuffix(repos, ")") {
+		p.configName = repos[i+len(" (config ") : len(repos)-1]
+		repos = repos[:i]
+	}
+	p.repositories = strings.Split(repos, ", ")
+
+	switch {
+	case role.ID == p.grantee.roleName(p.configName):
+		return p, true
+	case p.configName == "" && p.grantee.kind == granteeUser && role.ID == legacyRoleName(p.grantee.id, repos):
+		return p, true
+	default:
+		return nil, false
+	}
+}
+
+// legacyRoleName 返回旧版本按用户和仓库生成的权限角色名称
+func legacyRoleName(userID, repository string) string {
+	return fmt.Sprintf("%s-%s-role", userID, repository)
+}
+
+// repositoryGrant 合并后的授予内容
+type repositoryGrant struct {
+	grantee      grantee
+	repositories []string
+	privileges   []string
+}
+
+// repositoryPrivilegeName 返回 Nexus 内置的仓库权限名称: nx-repository-{view|admin}-{format}-{name}-{action}
+func repositoryPrivilegeName(kind, format, repo, action string) string {
+	action = strings.ToLower(action)
+	if action == "all" {
+		action = "*"
+	}
+	return fmt.Sprintf("nx-repository-%s-%s-%s-%s", kind, format, repo, action)
+}
+
+// matchRepositories 返回与权限条目匹配的仓库名称到 format 的映射；单独的 * 匹配所有仓库
+func matchRepositories(pattern string, formats map[string]string) map[string]string {
+	if pattern == "*" {
+		return map[string]string{"*": "*"}
+	}
+	matched := make(map[string]string)
+	for name, format := range formats {
+		if ok, _ := path.Match(pattern, name); ok {
+			matched[name] = format
+		}
+	}
+	return matched
+}
+
+// buildRepositoryGrants 展开通配符并按授予对象合并权限条目
+func (s *ApplyService) buildRepositoryGrants() ([]*repositoryGrant, error) {
+	repos, err := s.client.ListRepositories()
+	if err != nil {
+		return nil, err
+	}
+	formats := make(map[string]string, len(repos))
+	for _, repo := range repos {
+		formats[repo.Name] = repo.Format
+	}
+
+	var grants []*repositoryGrant
+	byGrantee := make(map[grantee]*repositoryGrant)
+	for _, perm := range s.config.UserRepositoryPermissions {
+		g := granteeOf(perm)
+		grant, ok := byGrantee[g]
+		if !ok {
+			grant = &repositoryGrant{grantee: g}
+			byGrantee[g] = grant
+			grants = append(grants, grant)
+		}
+
+		matched := matchRepositories(perm.Repository, formats)
+		if len(matched) == 0 {
+			if perm.IsWildcard() {
+				s.formatter.Warning(fmt.Sprintf("Repository pattern %s for %s matches no repositories", perm.Repository, g.label()))
+				continue
+			}
+			return nil, fmt.Errorf("repository %s granted to %s does not exist", perm.Repository, g.label())
+		}
+
+		names := make([]string, 0, len(matched))
+		for name := range matched {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			format := matched[name]
+			if format == "" {
+				return nil, fmt.Errorf("failed to determine format for repository %s", name)
+			}
+			if !containsString(grant.repositories, name) {
+				grant.repositories = append(grant.repositories, name)
+			}
+			for _, action := range perm.Privileges {
+				priv := repositoryPrivilegeName("view", format, name, action)
+				if !containsString(grant.privileges, priv) {
+					grant.privileges = append(grant.privileges, priv)
+				}
+			}
+			for _, action := range perm.AdminPrivileges {
+				priv := repositoryPrivilegeName("admin", format, name, action)
+				if !containsString(grant.privileges, priv) {
+					grant.privileges = append(grant.privileges, priv)
+				}
+			}
+		}
+	}
+	return grants, nil
+}
+
+// applyUserRepositoryPermissions 为每个授予对象创建或更新一个仓库权限角色，记录授予对象对应的角色，
+// 并找出服务器上本配置不再需要的权限角色
+func (s *ApplyService) applyUserRepositoryPermissions() (int, error) {
+	s.grantRoles = make(map[grantee]string)
+	var grants []*repositoryGrant
+	if len(s.config.UserRepositoryPermissions) > 0 {
+		s.formatter.Info("Applying user repository permissions...")
+		var err error
+		grants, err = s.buildRepositoryGrants()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	count := 0
+	for _, grant := range grants {
+		roleName := grant.grantee.roleName(s.configName)
+		if len(grant.privileges) == 0 {
+			// 通配符没有匹配到任何仓库，不创建空角色；已存在的角色会作为过期角色被清理
+			continue
+		}
+
+		roleReq := nexus.RoleRequest{
+			ID:          roleName,
+			Name:        fmt.Sprintf("%s repository access", grant.grantee.label()),
+			Description: permissionRoleDescription(grant.grantee, grant.repositories, s.configName),
+			Privileges:  grant.privileges,
+		}
+		s.grantRoles[grant.grantee] = roleName
+
+		exists, err := s.client.RoleExists(roleName)
+		if err != nil {
+			return count, fmt.Errorf("failed to check role %s: %w", roleName, err)
+		}
+
+		if exists {
+			existing, err := s.client.GetRole(roleName)
+			if err != nil {
+				return count, fmt.Errorf("failed to get role %s: %w", roleName, err)
+			}
+			if existing.Description == roleReq.Description && sameStringSet(existing.Privileges, roleReq.Privileges) {
+				s.formatter.Info(fmt.Sprintf("Permission role %s is up to date, skipping...", roleName))
+				continue
+			}
+			if err := s.client.UpdateRole(roleName, roleReq); err != nil {
+				return count, fmt.Errorf("failed to update role %s: %w", roleName, err)
+			}
+			s.formatter.Success(fmt.Sprintf("Updated permission role: %s", roleName))
+		} else {
+			if err := s.client.CreateRole(roleReq); err != nil {
+				return count, fmt.Errorf("failed to create role %s: %w", roleName, err)
+			}
+			s.formatter.Success(fmt.Sprintf("Created permission role: %s", roleName))
+		}
+		count++
+	}
+
+	if err := s.classifyPermissionRoles(grants); err != nil {
+		return count, err
+	}
+	return count, nil
+}
+
+// classifyPermissionRoles 将服务器上自动生成的权限角色分为本配置的过期角色和其他配置的角色
+//
+// 本配置生成但不再需要的角色为过期角色。旧版本生成、没有记录配置的角色，在其所有仓库都已由本配置授予
+// 同一授予对象时视为已被取代，同样作为过期角色。其他配置生成的角色保持不变。
+func (s *ApplyService) classifyPermissionRoles(grants []*repositoryGrant) error {
+	s.stalePermissionRoles = make(map[string]bool)
+	s.otherPermissionRoles = make(map[string]bool)
+
+	wanted := make(map[string]bool)
+	for _, role := range s.grantRoles {
+		wanted[role] = true
+	}
+	granted := make(map[grantee][]string)
+	for _, grant := range grants {
+		if len(grant.privileges) > 0 {
+			granted[grant.grantee] = grant.repositories
+		}
+	}
+
+	roles, err := s.client.ListRoles()
+	if err != nil {
+		return fmt.Errorf("failed to list roles: %w", err)
+	}
+	for _, role := range roles {
+		if role.ReadOnly || wanted[role.ID] {
+			continue
+		}
+		p, ok := parsePermissionRole(role)
+		if !ok {
+			continue
+		}
+		switch {
+		case p.configName == s.configName:
+			s.stalePermissionRoles[role.ID] = true
+		case p.configName == "" && supersededBy(p.repositories, granted[p.grantee]):
+			s.stalePermissionRoles[role.ID] = true
+		default:
+			s.otherPermissionRoles[role.ID] = true
+		}
+	}
+	return nil
+}
+
+// supersededBy 检查旧角色的仓库是否都已包含在新的授予中
+func supersededBy(repositories, granted []string) bool {
+	if len(granted) == 0 {
+		return false
+	}
+	if containsString(granted, "*") {
+		return true
+	}
+	for _, repo := range repositories {
+		if !containsString(granted, repo) {
+			return false
+		}
+	}
+	return true
+}
+
+// keepOtherPermissionRoles 在期望的角色列表中保留当前持有的、由其他配置生成的权限角色
+func (s *ApplyService) keepOtherPermissionRoles(desired, current []string) []string {
+	for _, role := range current {
+		if s.otherPermissionRoles[role] && !containsString(desired, role) {
+			desired = append(desired, role)
+		}
+	}
+	return desired
+}
+
+// desiredRoleMembers 返回角色最终应包含的子角色：声明的子角色加上授予该角色（或同名 LDAP 组）的仓库权限角色
+func (s *ApplyService) desiredRoleMembers(role config.Role) []string {
+	members := append([]string{}, role.Roles...)
+	for _, g := range []grantee{{kind: granteeRole, id: role.ID}, {kind: granteeGroup, id: role.ID}} {
+		if name, ok := s.grantRoles[g]; ok && !containsString(members, name) {
+			members = append(members, name)
+		}
+	}
+	return members
+}
+
+// DesiredRoleMembers 返回 apply 后配置中每个角色的子角色，包括为角色和 LDAP 组生成的仓库权限角色
+//
+// 生成的仓库权限角色也作为没有子角色的条目返回。offline 时不查询服务器上的仓库，
+// 假设每个仓库权限条目都能匹配到仓库。
+func (s *ApplyService) DesiredRoleMembers(offline bool) (map[string][]string, error) {
+	s.grantRoles = make(map[grantee]string)
+	if offline {
+		for _, perm := range s.config.UserRepositoryPermissions {
+			g := granteeOf(perm)
+			s.grantRoles[g] = g.roleName(s.configName)
+		}
+	} else if len(s.config.UserRepositoryPermissions) > 0 {
+		grants, err := s.buildRepositoryGrants()
+		if err != nil {
+			return nil, err
+		}
+		for _, grant := range grants {
+			if len(grant.privileges) > 0 {
+				s.grantRoles[grant.grantee] = grant.grantee.roleName(s.configName)
+			}
+		}
+	}
+
+	members := make(map[string][]string, len(s.config.Roles))
+	for _, role := range s.config.Roles {
+		members[role.ID] = s.desiredRoleMembers(role)
+	}
+	for g, name := range s.grantRoles {
+		if _, ok := members[name]; !ok && g.kind != granteeUser {
+			members[name] = nil
+		}
+	}
+	return members, nil
+}
+
+// syncPermissionRoles 为未在配置中声明的用户、角色和 LDAP 组挂载仓库权限角色，并解除和删除本配置不再需要的权限角色
+//
+// 过期角色由 classifyPermissionRoles 从服务器上查找，即使对应的授予对象已经不在配置中
+// （例如删除了某个 LDAP 用户的最后一个权限条目）也会被删除；其他配置文件生成的权限角色不受影响。
+func (s *ApplyService) syncPermissionRoles() (int, error) {
+	declared := make(map[grantee]bool)
+	for _, user := range s.config.Users {
+		declared[grantee{kind: granteeUser, id: user.ID}] = true
+	}
+	for _, role := range s.config.Roles {
+		declared[grantee{kind: granteeRole, id: role.ID}] = true
+		if role.Source == config.SourceLDAP {
+			declared[grantee{kind: granteeGroup, id: role.ID}] = true
+		}
+	}
+
+	allRoles, err := s.client.ListRoles()
+	if err != nil {
+		return 0, err
+	}
+	stale := s.stalePermissionRoles
+	// 需要同步的授予对象：未声明的授予对象，以及持有过期角色的未声明用户和角色
+	targets := make(map[grantee]bool)
+	for _, role := range allRoles {
+		if !stale[role.ID] {
+			continue
+		}
+		if p, ok := parsePermissionRole(role); ok && p.grantee.kind == granteeUser && !declared[p.grantee] {
+			targets[p.grantee] = true
+		}
+	}
+	for _, perm := range s.config.UserRepositoryPermissions {
+		if g := granteeOf(perm); !declared[g] {
+			targets[g] = true
+		}
+	}
+
+	// 声明的用户和角色在 applyUsers/applyRoles 中已设置了完整的角色列表，这里只处理未声明的持有者
+	if len(stale) > 0 {
+		for _, role := range allRoles {
+			g := grantee{kind: granteeRole, id: role.ID}
+			if !role.ReadOnly && !stale[role.ID] && !declared[g] && holdsAny(role.Roles, stale) {
+				targets[g] = true
+			}
+		}
+		users, err := s.client.ListUsers(nexus.UserFilter{})
+		if err != nil {
+			return 0, err
+		}
+		for _, user := range users {
+			g := grantee{kind: granteeUser, id: user.UserID}
+			if !declared[g] && holdsAny(user.Roles, stale) {
+				targets[g] = true
+			}
+		}
+	}
+
+	sorted := make([]grantee, 0, len(targets))
+	for g := range targets {
+		sorted = append(sorted, g)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].kind != sorted[j].kind {
+			return sorted[i].kind < sorted[j].kind
+		}
+		return sorted[i].id < sorted[j].id
+	})
+
+	count := 0
+	for _, g := range sorted {
+		var changed bool
+		var err error
+		if g.kind == granteeUser {
+			changed, err = s.syncUserGrant(g, stale)
+		} else {
+			changed, err = s.syncRoleGrant(g, stale)
+		}
+		if err != nil {
+			return count, err
+		}
+		if changed {
+			count++
+		}
+	}
+
+	staleRoles := make([]string, 0, len(stale))
+	for role := range stale {
+		staleRoles = append(staleRoles, role)
+	}
+	sort.Strings(staleRoles)
+
+	for _, role := range staleRoles {
+		if err := s.client.DeleteRole(role); err != nil {
+			return count, fmt.Errorf("failed to delete stale permission role %s: %w", role, err)
+		}
+		s.formatter.Success(fmt.Sprintf("Deleted stale permission role: %s", role))
+		count++
+	}
+
+	return count, nil
+}
+
+// holdsAny 检查角色列表中是否包含任一指定角色
+func holdsAny(roles []string, set map[string]bool) bool {
+	for _, role := range roles {
+		if set[role] {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeGrantRole 从角色列表中移除过期的权限角色并加入当前的权限角色
+func mergeGrantRole(current []string, grantRole string, stale map[string]bool) []string {
+	var roles []string
+	for _, role := range current {
+		if !stale[role] {
+			roles = append(roles, role)
+		}
+	}
+	if grantRole != "" && !containsString(roles, grantRole) {
+		roles = append(roles, grantRole)
+	}
+	return roles
+}
+
+// syncUserGrant 为未声明的用户挂载权限角色并解除过期的权限角色
+func (s *ApplyService) syncUserGrant(g grantee, stale map[string]bool) (bool, error) {
+	user, err := s.client.GetUser(g.id)
+	if err != nil {
+		// 用户已被删除时只需删除其过期的权限角色
+		if s.grantRoles[g] == "" && strings.Contains(err.Error(), "not found") {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get user %s: %w", g.id, err)
+	}
+
+	roles := mergeGrantRole(user.Roles, s.grantRoles[g], stale)
+	if sameStringSet(roles, user.Roles) {
+		return false, nil
+	}
+
+	req := nexus.UserRequest{
+		UserID:       user.UserID,
+		FirstName:    user.FirstName,
+		LastName:     user.LastName,
+		EmailAddress: user.EmailAddress,
+		Status:       user.Status,
+		Source:       user.Source,
+		Roles:        roles,
+	}
+	if err := s.client.UpdateUser(g.id, req); err != nil {
+		return false, fmt.Errorf("failed to update roles of user %s: %w", g.id, err)
+	}
+	s.reportUserChanges(user, req)
+	return true, nil
+}
+
+// syncRoleGrant 将权限角色加入未声明的角色或 LDAP 组映射，LDAP 组映射不存在时创建
+func (s *ApplyService) syncRoleGrant(g grantee, stale map[string]bool) (bool, error) {
+	grantRole := s.grantRoles[g]
+
+	exists, err := s.client.RoleExists(g.id)
+	if err != nil {
+		return false, fmt.Errorf("failed to check role %s: %w", g.id, err)
+	}
+	if !exists {
+		if g.kind == granteeRole {
+			return false, fmt.Errorf("role %s granted repository permissions does not exist", g.id)
+		}
+		if grantRole == "" {
+			return false, nil
+		}
+		req := nexus.RoleRequest{
+			ID:          g.id,
+			Name:        g.id,
+			Description: fmt.Sprintf("External role mapping for LDAP group %s", g.id),
+			Roles:       []string{grantRole},
+		}
+		if err := s.client.CreateRole(req); err != nil {
+			return false, fmt.Errorf("failed to create role mapping for LDAP group %s: %w", g.id, err)
+		}
+		s.formatter.Success(fmt.Sprintf("Created role mapping for LDAP group %s with %s", g.id, grantRole))
+		return true, nil
+	}
+
+	role, err := s.client.GetRole(g.id)
+	if err != nil {
+		return false, fmt.Errorf("failed to get role %s: %w", g.id, err)
+	}
+	if role.ReadOnly {
+		return false, fmt.Errorf("role %s is read-only (built-in) and cannot be granted repository permissions", g.id)
+	}
+
+	members := mergeGrantRole(role.Roles, grantRole, stale)
+	if sameStringSet(members, role.Roles) {
+		return false, nil
+	}
+
+	req := nexus.RoleRequest{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		Privileges:  role.Privileges,
+		Roles:       members,
+	}
+	if err := s.client.UpdateRole(role.ID, req); err != nil {
+		return false, fmt.Errorf("failed to update role %s: %w", role.ID, err)
+	}
+	s.formatter.Success(fmt.Sprintf("Updated repository permissions of %s", g.label()))
+	return true, nil
+}
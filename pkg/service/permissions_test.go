@@ -0,0 +1,341 @@
+package service
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/alauda/nexus-cli/pkg/config"
+	"github.com/alauda/nexus-cli/pkg/nexus"
+	"github.com/alauda/nexus-cli/pkg/output"
+)
+
+// fakeSecurity 模拟 Nexus 的角色、用户和仓库接口
+type fakeSecurity struct {
+	mu           sync.Mutex
+	roles        map[string]nexus.RoleResponse
+	users        map[string]nexus.UserResponse
+	repositories []nexus.RepositorySummary
+	deleted      []string
+}
+
+func newFakeSecurity(t *testing.T, roles []nexus.RoleResponse, users []nexus.UserResponse) (*fakeSecurity, *nexus.Client) {
+	f := &fakeSecurity{roles: make(map[string]nexus.RoleResponse), users: make(map[string]nexus.UserResponse)}
+	for _, r := range roles {
+		f.roles[r.ID] = r
+	}
+	for _, u := range users {
+		f.users[u.UserID] = u
+	}
+	server := httptest.NewServer(http.HandlerFunc(f.handle))
+	t.Cleanup(server.Close)
+	return f, nexus.NewClient(server.URL, "admin", "admin123")
+}
+
+func (f *fakeSecurity) handle(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	write := func(v interface{}) {
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	body, _ := io.ReadAll(r.Body)
+	const rolesPath, usersPath = "/service/rest/v1/security/roles", "/service/rest/v1/security/users"
+
+	switch {
+	case r.URL.Path == "/service/rest/v1/repositories":
+		write(f.repositories)
+	case r.URL.Path == rolesPath && r.Method == http.MethodGet:
+		roles := make([]nexus.RoleResponse, 0, len(f.roles))
+		for _, role := range f.roles {
+			roles = append(roles, role)
+		}
+		write(roles)
+	case r.URL.Path == rolesPath && r.Method == http.MethodPost:
+		var role nexus.RoleResponse
+		_ = json.Unmarshal(body, &role)
+		f.roles[role.ID] = role
+	case strings.HasPrefix(r.URL.Path, rolesPath+"/"):
+		id := strings.TrimPrefix(r.URL.Path, rolesPath+"/")
+		role, ok := f.roles[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			write(role)
+		case http.MethodPut:
+			// PUT 替换整个角色，未提交的成员列表为空
+			var updated nexus.RoleResponse
+			_ = json.Unmarshal(body, &updated)
+			f.roles[id] = updated
+		case http.MethodDelete:
+			delete(f.roles, id)
+			f.deleted = append(f.deleted, id)
+		}
+	case r.URL.Path == usersPath:
+		// 不带 userId 时只列出本地用户，外部用户需要按 ID 查询
+		prefix := r.URL.Query().Get("userId")
+		var users []nexus.UserResponse
+		for _, u := range f.users {
+			if strings.HasPrefix(u.UserID, prefix) && (prefix != "" || u.Source != nexus.UserSourceLDAP) {
+				users = append(users, u)
+			}
+		}
+		write(users)
+	case strings.HasPrefix(r.URL.Path, usersPath+"/") && r.Method == http.MethodPut:
+		var user nexus.UserResponse
+		_ = json.Unmarshal(body, &user)
+		f.users[strings.TrimPrefix(r.URL.Path, usersPath+"/")] = user
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// applyPermissions 以指定的配置名称执行仓库权限角色的创建、用户的更新和权限角色的同步
+func applyPermissions(t *testing.T, client *nexus.Client, cfg *config.Config, name string) *ApplyService {
+	t.Helper()
+	s := NewApplyService(client, cfg, output.NewFormatter(output.FormatText, io.Discard))
+	s.SetConfigName(name)
+	if _, err := s.applyUserRepositoryPermissions(); err != nil {
+		t.Fatalf("applyUserRepositoryPermissions() error = %v", err)
+	}
+	if _, err := s.applyUsers(); err != nil {
+		t.Fatalf("applyUsers() error = %v", err)
+	}
+	if _, err := s.syncPermissionRoles(); err != nil {
+		t.Fatalf("syncPermissionRoles() error = %v", err)
+	}
+	return s
+}
+
+func TestSyncPermissionRolesRemovesLastEntry(t *testing.T) {
+	stale := nexus.RoleResponse{
+		ID:          "ldap-dev-team-repository-role",
+		Description: "Auto-generated role for ldap-dev to access maven-releases (config team)",
+		Privileges:  []string{"nx-repository-view-maven2-maven-releases-read"},
+	}
+	// 其他配置文件生成的角色和旧版本生成的角色不属于本配置，不能被清理
+	other := nexus.RoleResponse{
+		ID:          "ldap-dev-other-repository-role",
+		Description: "Auto-generated role for ldap-dev to access npm-hosted (config other)",
+	}
+	legacy := nexus.RoleResponse{
+		ID:          "ldap-dev-npm-hosted-role",
+		Description: "Auto-generated role for ldap-dev to access npm-hosted",
+	}
+	team := nexus.RoleResponse{ID: "team", Roles: []string{"ldap-dev-team-repository-role", "ldap-dev-other-repository-role"}}
+	users := []nexus.UserResponse{
+		{UserID: "ldap-dev", Source: "LDAP", Status: "active", Roles: []string{"nx-anonymous", "ldap-dev-team-repository-role", "ldap-dev-other-repository-role", "ldap-dev-npm-hosted-role"}},
+	}
+	fake, client := newFakeSecurity(t, []nexus.RoleResponse{stale, other, legacy, team}, users)
+
+	// 配置 team 中已没有任何用户、角色和权限条目
+	applyPermissions(t, client, &config.Config{}, "team")
+
+	if want := []string{"ldap-dev-team-repository-role"}; !reflect.DeepEqual(fake.deleted, want) {
+		t.Errorf("deleted roles = %v, want %v", fake.deleted, want)
+	}
+	if roles, want := fake.users["ldap-dev"].Roles, []string{"nx-anonymous", "ldap-dev-other-repository-role", "ldap-dev-npm-hosted-role"}; !reflect.DeepEqual(roles, want) {
+		t.Errorf("roles of ldap-dev = %v, want %v", roles, want)
+	}
+	if roles := fake.roles["team"].Roles; !reflect.DeepEqual(roles, []string{"ldap-dev-other-repository-role"}) {
+		t.Errorf("members of team = %v, want only the stale permission role detached", roles)
+	}
+}
+
+func TestApplyPermissionsFromMultipleConfigs(t *testing.T) {
+	users := []nexus.UserResponse{{UserID: "dev", Source: "default", Status: "active", Roles: []string{"nx-anonymous"}}}
+	fake, client := newFakeSecurity(t, nil, users)
+	fake.repositories = []nexus.RepositorySummary{
+		{Name: "team1-maven", Format: "maven2"},
+		{Name: "team2-npm", Format: "npm"},
+	}
+
+	// team1 声明了用户 dev，team2 只授予 dev 仓库权限
+	team1 := &config.Config{
+		Users: []config.User{{ID: "dev", Status: "active", Roles: []string{"nx-anonymous"}}},
+		UserRepositoryPermissions: []config.UserRepositoryPermission{
+			{UserID: "dev", Repository: "team1-maven", Privileges: []string{"READ"}},
+		},
+	}
+	team2 := &config.Config{UserRepositoryPermissions: []config.UserRepositoryPermission{
+		{UserID: "dev", Repository: "team2-npm", Privileges: []string{"READ"}},
+	}}
+	applyPermissions(t, client, team1, "team1")
+	applyPermissions(t, client, team2, "team2")
+	applyPermissions(t, client, team1, "team1")
+
+	if len(fake.deleted) != 0 {
+		t.Errorf("deleted roles = %v, want none", fake.deleted)
+	}
+	want := map[string][]string{
+		"dev-team1-repository-role": {"nx-repository-view-maven2-team1-maven-read"},
+		"dev-team2-repository-role": {"nx-repository-view-npm-team2-npm-read"},
+	}
+	for id, privileges := range want {
+		if got := fake.roles[id].Privileges; !reflect.DeepEqual(got, privileges) {
+			t.Errorf("privileges of %s = %v, want %v", id, got, privileges)
+		}
+	}
+	roles := append([]string{}, fake.users["dev"].Roles...)
+	sort.Strings(roles)
+	if want := []string{"dev-team1-repository-role", "dev-team2-repository-role", "nx-anonymous"}; !reflect.DeepEqual(roles, want) {
+		t.Errorf("roles of dev = %v, want the permission roles of both configs", roles)
+	}
+}
+
+func TestParsePermissionRole(t *testing.T) {
+	tests := []struct {
+		id, description string
+		want            *permissionRole
+	}{
+		{"dev-repository-role", "Auto-generated role for dev to access maven-releases",
+			&permissionRole{grantee: grantee{granteeUser, "dev"}, repositories: []string{"maven-releases"}}},
+		{"dev-team1-repository-role", "Auto-generated role for dev to access a, b (config team1)",
+			&permissionRole{grantee: grantee{granteeUser, "dev"}, repositories: []string{"a", "b"}, configName: "team1"}},
+		{"role-team-repository-role", "Auto-generated role for role team to access a, b",
+			&permissionRole{grantee: grantee{granteeRole, "team"}, repositories: []string{"a", "b"}}},
+		{"group-ops-infra-repository-role", "Auto-generated role for group ops to access a (config infra)",
+			&permissionRole{grantee: grantee{granteeGroup, "ops"}, repositories: []string{"a"}, configName: "infra"}},
+		{"dev-maven-releases-role", "Auto-generated role for dev to access maven-releases",
+			&permissionRole{grantee: grantee{granteeUser, "dev"}, repositories: []string{"maven-releases"}}},
+		// 手动创建的角色：描述相似但 ID 不是对应的角色 ID
+		{"dev-custom-role", "Auto-generated role for dev to access maven-releases", nil},
+		{"dev-repository-role", "Auto-generated role for dev to access a (config team1)", nil},
+		{"dev-repository-role", "Repository access for dev", nil},
+	}
+	for _, tt := range tests {
+		got, ok := parsePermissionRole(nexus.RoleResponse{ID: tt.id, Description: tt.description})
+		if ok != (tt.want != nil) || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parsePermissionRole(%s, %q) = %+v, %v, want %+v", tt.id, tt.description, got, ok, tt.want)
+		}
+	}
+}
+
+func TestDesiredRoleMembers(t *testing.T) {
+	s := &ApplyService{grantRoles: map[grantee]string{
+		{kind: granteeRole, id: "team"}:  "role-team-repository-role",
+		{kind: granteeGroup, id: "ops"}:  "group-ops-repository-role",
+		{kind: granteeUser, id: "team"}:  "team-repository-role",
+		{kind: granteeRole, id: "other"}: "role-other-repository-role",
+	}}
+	tests := []struct {
+		role config.Role
+		want []string
+	}{
+		{config.Role{ID: "team", Roles: []string{"nx-anonymous"}}, []string{"nx-anonymous", "role-team-repository-role"}},
+		{config.Role{ID: "ops", Source: config.SourceLDAP}, []string{"group-ops-repository-role"}},
+		{config.Role{ID: "other", Roles: []string{"role-other-repository-role"}}, []string{"role-other-repository-role"}},
+		{config.Role{ID: "plain"}, []string{}},
+	}
+	for _, tt := range tests {
+		if got := s.desiredRoleMembers(tt.role); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("desiredRoleMembers(%s) = %v, want %v", tt.role.ID, got, tt.want)
+		}
+	}
+}
+
+func TestDesiredRoleMembersForGraph(t *testing.T) {
+	fake, client := newFakeSecurity(t, nil, nil)
+	fake.repositories = []nexus.RepositorySummary{{Name: "team-releases", Format: "maven2"}}
+	cfg := &config.Config{
+		Roles: []config.Role{
+			{ID: "team", Roles: []string{"nx-anonymous"}},
+			{ID: "ops", Source: config.SourceLDAP},
+		},
+		UserRepositoryPermissions: []config.UserRepositoryPermission{
+			{RoleID: "team", Repository: "team-*", Privileges: []string{"READ"}},
+			{Group: "ops", Repository: "ops-*", Privileges: []string{"READ"}},
+			{UserID: "dev", Repository: "team-releases", Privileges: []string{"READ"}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		offline bool
+		want    map[string][]string
+	}{
+		{
+			// ops-* 没有匹配到仓库，apply 不会为 ops 创建权限角色
+			name: "online",
+			want: map[string][]string{
+				"team":                           {"nx-anonymous", "role-team-prod-repository-role"},
+				"ops":                            {},
+				"role-team-prod-repository-role": nil,
+			},
+		},
+		{
+			name:    "offline",
+			offline: true,
+			want: map[string][]string{
+				"team":                           {"nx-anonymous", "role-team-prod-repository-role"},
+				"ops":                            {"group-ops-prod-repository-role"},
+				"role-team-prod-repository-role": nil,
+				"group-ops-prod-repository-role": nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewApplyService(client, cfg, output.NewFormatter(output.FormatText, io.Discard))
+			s.SetConfigName("prod")
+			got, err := s.DesiredRoleMembers(tt.offline)
+			if err != nil {
+				t.Fatalf("DesiredRoleMembers() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DesiredRoleMembers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyRepositoryPermissionRoles(t *testing.T) {
+	roles := []nexus.RoleResponse{
+		{ID: "team"},
+		// 手动维护的角色，描述与自动生成的相似，不能被删除
+		{ID: "dev-custom-role", Description: "Auto-generated role for dev to access maven-releases"},
+		{ID: "dev-maven-old-role", Description: "Auto-generated role for dev to access maven-old"},
+	}
+	users := []nexus.UserResponse{{UserID: "dev", Source: "default", Status: "active", Roles: []string{"dev-maven-old-role"}}}
+	fake, client := newFakeSecurity(t, roles, users)
+	fake.repositories = []nexus.RepositorySummary{
+		{Name: "maven-releases", Format: "maven2"},
+		{Name: "maven-snapshots", Format: "maven2"},
+		{Name: "npm-hosted", Format: "npm"},
+	}
+
+	cfg := &config.Config{UserRepositoryPermissions: []config.UserRepositoryPermission{
+		{UserID: "dev", Repository: "maven-*", Privileges: []string{"READ"}},
+		{UserID: "dev", Repository: "npm-hosted", Privileges: []string{"ALL"}},
+		{RoleID: "team", Repository: "npm-hosted", AdminPrivileges: []string{"READ"}},
+	}}
+	applyPermissions(t, client, cfg, "")
+
+	devRole := fake.roles["dev-repository-role"]
+	wantPrivileges := []string{
+		"nx-repository-view-maven2-maven-releases-read",
+		"nx-repository-view-maven2-maven-snapshots-read",
+		"nx-repository-view-npm-npm-hosted-*",
+	}
+	if !reflect.DeepEqual(devRole.Privileges, wantPrivileges) {
+		t.Errorf("privileges of dev-repository-role = %v, want %v", devRole.Privileges, wantPrivileges)
+	}
+	if roles := fake.users["dev"].Roles; !reflect.DeepEqual(roles, []string{"dev-repository-role"}) {
+		t.Errorf("roles of dev = %v, want the consolidated role replacing the legacy one", roles)
+	}
+	if roles := fake.roles["team"].Roles; !reflect.DeepEqual(roles, []string{"role-team-repository-role"}) {
+		t.Errorf("members of team = %v, want role-team-repository-role", roles)
+	}
+	if !reflect.DeepEqual(fake.deleted, []string{"dev-maven-old-role"}) {
+		t.Errorf("deleted roles = %v, want only the legacy role", fake.deleted)
+	}
+}
@@ -0,0 +1,354 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alauda/nexus-cli/pkg/config"
+	"github.com/alauda/nexus-cli/pkg/nexus"
+)
+
+// applyLDAP 应用 LDAP 服务器配置并按配置顺序排列，已存在且有变化的服务器会被更新
+func (s *ApplyService) applyLDAP() (int, error) {
+	if s.config.LDAP == nil || len(s.config.LDAP.Servers) == 0 {
+		return 0, nil
+	}
+	s.formatter.Info("Applying LDAP servers...")
+	count := 0
+	for _, server := range s.config.LDAP.Servers {
+		req, err := buildLDAPServerRequest(server)
+		if err != nil {
+			return count, err
+		}
+
+		exists, err := s.client.LDAPServerExists(server.Name)
+		if err != nil {
+			return count, fmt.Errorf("failed to check LDAP server %s: %w", server.Name, err)
+		}
+
+		if exists {
+			existing, err := s.client.GetLDAPServer(server.Name)
+			if err != nil {
+				return count, err
+			}
+			if ldapServerUpToDate(*existing, req) {
+				s.formatter.Info(fmt.Sprintf("LDAP server %s is up to date, skipping...", server.Name))
+				continue
+			}
+			req.ID = existing.ID
+			if err := s.client.UpdateLDAPServer(server.Name, req); err != nil {
+				return count, err
+			}
+			s.formatter.Success(fmt.Sprintf("Updated LDAP server: %s", server.Name))
+		} else {
+			if err := s.client.CreateLDAPServer(req); err != nil {
+				return count, err
+			}
+			s.formatter.Success(fmt.Sprintf("Created LDAP server: %s", server.Name))
+		}
+		count++
+	}
+
+	if err := s.applyLDAPOrder(); err != nil {
+		return count, err
+	}
+	return count, nil
+}
+
+// ldapServerUpToDate 检查服务器上的 LDAP 配置是否与期望的一致
+//
+// Nexus 不返回绑定密码，只比较其余字段；只修改绑定密码时不会触发更新。
+func ldapServerUpToDate(existing, req nexus.LDAPServerRequest) bool {
+	existing.ID, existing.Order, existing.AuthPassword = "", 0, ""
+	req.ID, req.Order, req.AuthPassword = "", 0, ""
+	return existing == req
+}
+
+// applyLDAPOrder 将配置中的 LDAP 服务器排在最前，其余服务器保持原有顺序
+func (s *ApplyService) applyLDAPOrder() error {
+	servers, err := s.client.ListLDAPServers()
+	if err != nil {
+		return err
+	}
+
+	current := make([]string, 0, len(servers))
+	for _, server := range servers {
+		current = append(current, server.Name)
+	}
+
+	desired := make([]string, 0, len(servers))
+	configured := make(map[string]bool)
+	for _, server := range s.config.LDAP.Servers {
+		desired = append(desired, server.Name)
+		configured[server.Name] = true
+	}
+	for _, name := range current {
+		if !configured[name] {
+			desired = append(desired, name)
+		}
+	}
+
+	if strings.Join(current, "\x00") == strings.Join(desired, "\x00") {
+		return nil
+	}
+	if err := s.client.ChangeLDAPServerOrder(desired); err != nil {
+		return err
+	}
+	s.formatter.Success(fmt.Sprintf("Changed LDAP server order: %s", strings.Join(desired, ", ")))
+	return nil
+}
+
+// buildLDAPServerRequest 将 LDAP 配置转换为 API 请求，并解析绑定密码引用
+func buildLDAPServerRequest(server config.LDAPServer) (nexus.LDAPServerRequest, error) {
+	conn := server.Connection
+	password, err := conn.AuthPassword.Resolve()
+	if err != nil {
+		return nexus.LDAPServerRequest{}, fmt.Errorf("failed to resolve bind password for LDAP server %s: %w", server.Name, err)
+	}
+
+	req := nexus.LDAPServerRequest{
+		Name:                        server.Name,
+		Protocol:                    conn.Protocol,
+		UseTrustStore:               conn.UseTrustStore,
+		Host:                        conn.Host,
+		Port:                        conn.Port,
+		SearchBase:                  conn.SearchBase,
+		AuthScheme:                  conn.AuthScheme,
+		AuthRealm:                   conn.AuthRealm,
+		AuthUsername:                conn.AuthUsername,
+		AuthPassword:                password,
+		ConnectionTimeoutSeconds:    conn.ConnectionTimeoutSeconds,
+		ConnectionRetryDelaySeconds: conn.ConnectionRetryDelaySeconds,
+		MaxIncidentsCount:           conn.MaxIncidentsCount,
+		UserBaseDn:                  server.UserMapping.BaseDn,
+		UserSubtree:                 server.UserMapping.Subtree,
+		UserObjectClass:             server.UserMapping.ObjectClass,
+		UserLdapFilter:              server.UserMapping.LdapFilter,
+		UserIDAttribute:             server.UserMapping.IDAttribute,
+		UserRealNameAttribute:       server.UserMapping.RealNameAttribute,
+		UserEmailAddressAttribute:   server.UserMapping.EmailAddressAttribute,
+		UserPasswordAttribute:       server.UserMapping.PasswordAttribute,
+		LdapGroupsAsRoles:           server.GroupMapping.Enabled,
+	}
+	if req.ConnectionTimeoutSeconds == 0 {
+		req.ConnectionTimeoutSeconds = config.DefaultLDAPConnectionTimeout
+	}
+	if req.ConnectionRetryDelaySeconds == 0 {
+		req.ConnectionRetryDelaySeconds = config.DefaultLDAPRetryDelay
+	}
+	if req.MaxIncidentsCount == 0 {
+		req.MaxIncidentsCount = config.DefaultLDAPMaxIncidents
+	}
+
+	if gm := server.GroupMapping; gm.Enabled {
+		req.GroupType = gm.Type
+		req.GroupBaseDn = gm.BaseDn
+		req.GroupSubtree = gm.Subtree
+		req.GroupObjectClass = gm.ObjectClass
+		req.GroupIDAttribute = gm.IDAttribute
+		req.GroupMemberAttribute = gm.MemberAttribute
+		req.GroupMemberFormat = gm.MemberFormat
+		req.UserMemberOfAttribute = gm.MemberOfAttribute
+	}
+	return req, nil
+}
+
+// checkExternalRoleMapping 检查外部角色映射的 LDAP 组是否存在
+func (s *ApplyService) checkExternalRoleMapping(role config.Role) error {
+	if s.ldapGroups == nil {
+		groups, err := s.client.ListRolesBySource(config.SourceLDAP)
+		if err != nil {
+			return err
+		}
+		s.ldapGroups = make(map[string]bool, len(groups))
+		for _, g := range groups {
+			s.ldapGroups[g.ID] = true
+		}
+	}
+	if !s.ldapGroups[role.ID] {
+		s.formatter.Warning(fmt.Sprintf("Role %s maps LDAP group %s, but no such group was found in LDAP", role.ID, role.ID))
+	}
+	return nil
+}
+
+// applyExternalUser 更新外部来源用户（如 LDAP）的角色，不修改密码和基本信息，返回是否有变更
+func (s *ApplyService) applyExternalUser(user config.User) (bool, error) {
+	existing, err := s.client.GetUserFromSource(user.ID, user.Source)
+	if err != nil {
+		return false, fmt.Errorf("%s user %s must exist in the external directory: %w", user.Source, user.ID, err)
+	}
+
+	req := nexus.UserRequest{
+		UserID:       existing.UserID,
+		FirstName:    existing.FirstName,
+		LastName:     existing.LastName,
+		EmailAddress: existing.EmailAddress,
+		Status:       existing.Status,
+		Source:       existing.Source,
+		Roles:        s.keepOtherPermissionRoles(s.desiredUserRoles(user), existing.Roles),
+	}
+	if sameStringSet(existing.Roles, req.Roles) {
+		s.formatter.Info(fmt.Sprintf("Roles of %s user %s are up to date, skipping...", user.Source, user.ID))
+		return false, nil
+	}
+	if err := s.client.UpdateUser(user.ID, req); err != nil {
+		return false, err
+	}
+	s.formatter.Success(fmt.Sprintf("Updated roles of %s user: %s", user.Source, user.ID))
+	s.reportUserChanges(existing, req)
+	return true, nil
+}
+
+// applySecurity 应用安全域、匿名访问和用户令牌设置
+func (s *ApplyService) applySecurity() (int, error) {
+	sec := s.config.Security
+	if sec == nil {
+		return 0, nil
+	}
+	s.formatter.Info("Applying security settings...")
+	count := 0
+
+	if len(sec.Realms) > 0 {
+		changed, err := s.applyRealms(sec.Realms)
+		if err != nil {
+			return count, err
+		}
+		if changed {
+			count++
+		}
+	}
+
+	if sec.Anonymous != nil {
+		changed, err := s.applyAnonymous(*sec.Anonymous)
+		if err != nil {
+			return count, err
+		}
+		if changed {
+			count++
+		}
+	}
+
+	if sec.UserTokens != nil {
+		changed, err := s.applyUserTokens(*sec.UserTokens)
+		if err != nil {
+			return count, err
+		}
+		if changed {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// applyRealms 设置启用的安全域及顺序
+func (s *ApplyService) applyRealms(realms []string) (bool, error) {
+	available, err := s.client.ListAvailableRealms()
+	if err != nil {
+		return false, err
+	}
+	known := make(map[string]bool, len(available))
+	for _, r := range available {
+		known[r.ID] = true
+	}
+	for _, realm := range realms {
+		if !known[realm] {
+			return false, fmt.Errorf("realm %s is not available on this Nexus instance", realm)
+		}
+	}
+
+	active, err := s.client.GetActiveRealms()
+	if err != nil {
+		return false, err
+	}
+	if strings.Join(active, ",") == strings.Join(realms, ",") {
+		s.formatter.Info("Security realms are up to date, skipping...")
+		return false, nil
+	}
+
+	hasLocal := false
+	for _, realm := range realms {
+		if realm == "NexusAuthenticatingRealm" {
+			hasLocal = true
+		}
+	}
+	if !hasLocal {
+		if !s.allowLocalRealmRemoval {
+			return false, fmt.Errorf("NexusAuthenticatingRealm is not in security.realms; local users (including admin and possibly the user nexus-cli is connected as) would no longer be able to log in, use --force-realms to apply anyway")
+		}
+		s.formatter.Warning("NexusAuthenticatingRealm is not in security.realms; local users (including admin) will no longer be able to log in")
+	}
+
+	if err := s.client.SetActiveRealms(realms); err != nil {
+		return false, err
+	}
+	s.formatter.Success(fmt.Sprintf("Updated active realms: %s", strings.Join(realms, ", ")))
+	return true, nil
+}
+
+// applyAnonymous 设置匿名访问
+func (s *ApplyService) applyAnonymous(cfg config.AnonymousConfig) (bool, error) {
+	desired := nexus.AnonymousSettings{
+		Enabled:   cfg.Enabled,
+		UserID:    cfg.UserID,
+		RealmName: cfg.RealmName,
+	}
+	if desired.UserID == "" {
+		desired.UserID = config.DefaultAnonymousUserID
+	}
+	if desired.RealmName == "" {
+		desired.RealmName = config.DefaultAnonymousRealm
+	}
+
+	current, err := s.client.GetAnonymousSettings()
+	if err != nil {
+		return false, err
+	}
+	if *current == desired {
+		s.formatter.Info("Anonymous access settings are up to date, skipping...")
+		return false, nil
+	}
+
+	if err := s.client.UpdateAnonymousSettings(desired); err != nil {
+		return false, err
+	}
+	state := "disabled"
+	if desired.Enabled {
+		state = fmt.Sprintf("enabled (user %s, realm %s)", desired.UserID, desired.RealmName)
+	}
+	s.formatter.Success(fmt.Sprintf("Updated anonymous access: %s", state))
+	return true, nil
+}
+
+// applyUserTokens 设置用户令牌（仅 Nexus Pro 提供该 API）
+func (s *ApplyService) applyUserTokens(cfg config.UserTokenConfig) (bool, error) {
+	desired := nexus.UserTokenSettings{
+		Enabled:           cfg.Enabled,
+		ProtectContent:    cfg.ProtectContent,
+		ExpirationEnabled: cfg.ExpirationEnabled,
+		ExpirationDays:    cfg.ExpirationDays,
+	}
+
+	current, err := s.client.GetUserTokenSettings()
+	if err != nil {
+		if strings.Contains(err.Error(), "status 404") || strings.Contains(err.Error(), "status 402") {
+			s.formatter.Warning("User token settings are not available on this Nexus edition, skipping...")
+			return false, nil
+		}
+		return false, err
+	}
+	if !desired.ExpirationEnabled {
+		// 未启用过期时不比较过期天数
+		desired.ExpirationDays = current.ExpirationDays
+	}
+	if *current == desired {
+		s.formatter.Info("User token settings are up to date, skipping...")
+		return false, nil
+	}
+
+	if err := s.client.UpdateUserTokenSettings(desired); err != nil {
+		return false, err
+	}
+	s.formatter.Success("Updated user token settings")
+	return true, nil
+}
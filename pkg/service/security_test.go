@@ -0,0 +1,200 @@
+package service
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/alauda/nexus-cli/pkg/config"
+	"github.com/alauda/nexus-cli/pkg/nexus"
+	"github.com/alauda/nexus-cli/pkg/output"
+)
+
+func TestApplyRealms(t *testing.T) {
+	active := []string{"NexusAuthenticatingRealm", "LdapRealm"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/service/rest/v1/security/realms/available":
+			_, _ = w.Write([]byte(`[{"id":"NexusAuthenticatingRealm"},{"id":"LdapRealm"},{"id":"DockerToken"}]`))
+		case "/service/rest/v1/security/realms/active":
+			if r.Method == http.MethodPut {
+				_ = json.NewDecoder(r.Body).Decode(&active)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(active)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	s := NewApplyService(nexus.NewClient(server.URL, "admin", "admin123"), nil, output.NewFormatter(output.FormatText, io.Discard))
+
+	if changed, err := s.applyRealms([]string{"NexusAuthenticatingRealm", "LdapRealm"}); err != nil || changed {
+		t.Errorf("applyRealms() unchanged = %v, %v, want no change", changed, err)
+	}
+	if _, err := s.applyRealms([]string{"UnknownRealm"}); err == nil {
+		t.Error("applyRealms() with an unavailable realm expected error")
+	}
+
+	// 去掉本地安全域会锁定 admin，需要显式允许
+	if _, err := s.applyRealms([]string{"LdapRealm"}); err == nil {
+		t.Error("applyRealms() without NexusAuthenticatingRealm expected error")
+	}
+	if want := []string{"NexusAuthenticatingRealm", "LdapRealm"}; !reflect.DeepEqual(active, want) {
+		t.Errorf("active realms = %v, want unchanged %v", active, want)
+	}
+	s.SetAllowLocalRealmRemoval(true)
+	if changed, err := s.applyRealms([]string{"LdapRealm"}); err != nil || !changed {
+		t.Errorf("applyRealms() with --force-realms = %v, %v", changed, err)
+	}
+	if want := []string{"LdapRealm"}; !reflect.DeepEqual(active, want) {
+		t.Errorf("active realms = %v, want %v", active, want)
+	}
+}
+
+func TestBuildLDAPServerRequest(t *testing.T) {
+	t.Setenv("LDAP_BIND_PASSWORD", "s3cret")
+	connection := config.LDAPConnection{
+		Protocol: "ldaps", Host: "ldap.example.com", Port: 636, SearchBase: "dc=example,dc=com",
+		AuthScheme: "SIMPLE", AuthUsername: "cn=nexus,dc=example,dc=com", AuthPassword: config.SecretRef{Env: "LDAP_BIND_PASSWORD"},
+	}
+	userMapping := config.LDAPUserMapping{BaseDn: "ou=people", ObjectClass: "inetOrgPerson", IDAttribute: "uid",
+		RealNameAttribute: "cn", EmailAddressAttribute: "mail"}
+	groupMapping := config.LDAPGroupMapping{Enabled: true, Type: "static", BaseDn: "ou=groups", ObjectClass: "groupOfNames",
+		IDAttribute: "cn", MemberAttribute: "member", MemberFormat: "uid=${username},ou=people,dc=example,dc=com"}
+
+	base := nexus.LDAPServerRequest{
+		Name: "corp-ldap", Protocol: "ldaps", Host: "ldap.example.com", Port: 636, SearchBase: "dc=example,dc=com",
+		AuthScheme: "SIMPLE", AuthUsername: "cn=nexus,dc=example,dc=com", AuthPassword: "s3cret",
+		ConnectionTimeoutSeconds:    config.DefaultLDAPConnectionTimeout,
+		ConnectionRetryDelaySeconds: config.DefaultLDAPRetryDelay,
+		MaxIncidentsCount:           config.DefaultLDAPMaxIncidents,
+		UserBaseDn:                  "ou=people", UserObjectClass: "inetOrgPerson", UserIDAttribute: "uid",
+		UserRealNameAttribute: "cn", UserEmailAddressAttribute: "mail",
+	}
+	withGroups := base
+	withGroups.LdapGroupsAsRoles = true
+	withGroups.GroupType, withGroups.GroupBaseDn, withGroups.GroupObjectClass = "static", "ou=groups", "groupOfNames"
+	withGroups.GroupIDAttribute, withGroups.GroupMemberAttribute = "cn", "member"
+	withGroups.GroupMemberFormat = "uid=${username},ou=people,dc=example,dc=com"
+	timeouts := base
+	timeouts.ConnectionTimeoutSeconds, timeouts.ConnectionRetryDelaySeconds, timeouts.MaxIncidentsCount = 10, 60, 5
+
+	tests := []struct {
+		name   string
+		server config.LDAPServer
+		want   nexus.LDAPServerRequest
+	}{
+		{
+			name:   "connection defaults and no group mapping",
+			server: config.LDAPServer{Name: "corp-ldap", Connection: connection, UserMapping: userMapping},
+			want:   base,
+		},
+		{
+			name: "explicit timeouts",
+			server: config.LDAPServer{Name: "corp-ldap", Connection: func() config.LDAPConnection {
+				c := connection
+				c.ConnectionTimeoutSeconds, c.ConnectionRetryDelaySeconds, c.MaxIncidentsCount = 10, 60, 5
+				return c
+			}(), UserMapping: userMapping},
+			want: timeouts,
+		},
+		{
+			name:   "group mapping",
+			server: config.LDAPServer{Name: "corp-ldap", Connection: connection, UserMapping: userMapping, GroupMapping: groupMapping},
+			want:   withGroups,
+		},
+		{
+			name: "disabled group mapping is not sent",
+			server: config.LDAPServer{Name: "corp-ldap", Connection: connection, UserMapping: userMapping,
+				GroupMapping: config.LDAPGroupMapping{Type: "static", BaseDn: "ou=groups"}},
+			want: base,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildLDAPServerRequest(tt.server)
+			if err != nil {
+				t.Fatalf("buildLDAPServerRequest() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("buildLDAPServerRequest() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Setenv("LDAP_BIND_PASSWORD", "")
+	if _, err := buildLDAPServerRequest(config.LDAPServer{Name: "corp-ldap", Connection: connection}); err == nil {
+		t.Error("buildLDAPServerRequest() with an unset bind password expected error")
+	}
+}
+
+func TestApplyLDAP(t *testing.T) {
+	servers := []nexus.LDAPServerRequest{
+		{ID: "1", Order: 1, Name: "corp-ldap", Protocol: "ldap", Host: "ldap.example.com", Port: 389, SearchBase: "dc=example,dc=com", AuthScheme: "NONE",
+			ConnectionTimeoutSeconds: 30, ConnectionRetryDelaySeconds: 300, MaxIncidentsCount: 3, UserObjectClass: "inetOrgPerson", UserIDAttribute: "uid"},
+		{ID: "2", Order: 2, Name: "legacy-ldap", Protocol: "ldap", Host: "old.example.com", Port: 389, AuthScheme: "NONE"},
+		{ID: "3", Order: 3, Name: "backup-ldap", Protocol: "ldap", Host: "ldap2.example.com", Port: 389, SearchBase: "dc=example,dc=com", AuthScheme: "NONE",
+			ConnectionTimeoutSeconds: 30, ConnectionRetryDelaySeconds: 300, MaxIncidentsCount: 3, UserObjectClass: "inetOrgPerson", UserIDAttribute: "uid"},
+	}
+	var updated []string
+	var orders [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/service/rest/v1/security/ldap":
+			_ = json.NewEncoder(w).Encode(servers)
+		case r.URL.Path == "/service/rest/v1/security/ldap/change-order":
+			var order []string
+			_ = json.NewDecoder(r.Body).Decode(&order)
+			orders = append(orders, order)
+		case r.Method == http.MethodPut:
+			updated = append(updated, r.URL.Path)
+		default:
+			for _, s := range servers {
+				if r.URL.Path == "/service/rest/v1/security/ldap/"+s.Name {
+					_ = json.NewEncoder(w).Encode(s)
+					return
+				}
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	connection := func(host string) config.LDAPConnection {
+		return config.LDAPConnection{Protocol: "ldap", Host: host, Port: 389, SearchBase: "dc=example,dc=com", AuthScheme: "NONE"}
+	}
+	userMapping := config.LDAPUserMapping{ObjectClass: "inetOrgPerson", IDAttribute: "uid"}
+	cfg := &config.Config{LDAP: &config.LDAPConfig{Servers: []config.LDAPServer{
+		{Name: "backup-ldap", Connection: connection("ldap3.example.com"), UserMapping: userMapping},
+		{Name: "corp-ldap", Connection: connection("ldap.example.com"), UserMapping: userMapping},
+	}}}
+	s := NewApplyService(nexus.NewClient(server.URL, "admin", "admin123"), cfg, output.NewFormatter(output.FormatText, io.Discard))
+	count, err := s.applyLDAP()
+	if err != nil {
+		t.Fatalf("applyLDAP() error = %v", err)
+	}
+
+	// 未变化的 corp-ldap 不会更新；配置中的服务器排在最前，其余服务器保持原有顺序
+	if count != 1 || !reflect.DeepEqual(updated, []string{"/service/rest/v1/security/ldap/backup-ldap"}) {
+		t.Errorf("applyLDAP() = %d, updated = %v, want only backup-ldap updated", count, updated)
+	}
+	if want := [][]string{{"backup-ldap", "corp-ldap", "legacy-ldap"}}; !reflect.DeepEqual(orders, want) {
+		t.Errorf("order changes = %v, want %v", orders, want)
+	}
+
+	// 配置和顺序都已一致时不再更新
+	servers[0], servers[1], servers[2] = servers[2], servers[0], servers[1]
+	servers[0].Host = "ldap3.example.com"
+	orders = nil
+	if _, err := s.applyLDAP(); err != nil {
+		t.Fatalf("applyLDAP() error = %v", err)
+	}
+	if orders != nil || len(updated) != 1 {
+		t.Errorf("order changes = %v, updated = %v, want no further changes", orders, updated)
+	}
+}
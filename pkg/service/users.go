@@ -0,0 +1,197 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alauda/nexus-cli/pkg/config"
+	"github.com/alauda/nexus-cli/pkg/nexus"
+	"github.com/alauda/nexus-cli/pkg/password"
+)
+
+// desiredUserRoles 返回用户最终应拥有的角色：声明的角色加上仓库权限角色
+func (s *ApplyService) desiredUserRoles(user config.User) []string {
+	roles := append([]string{}, user.Roles...)
+	if role, ok := s.grantRoles[grantee{kind: granteeUser, id: user.ID}]; ok && !containsString(roles, role) {
+		roles = append(roles, role)
+	}
+	return roles
+}
+
+// userStatus 返回用户的期望状态，未设置时为 active
+func userStatus(user config.User) string {
+	if user.Status == "" {
+		return config.UserStatusActive
+	}
+	return user.Status
+}
+
+// applyUsers 应用用户配置，用户的角色会被设置为声明的角色加上权限角色（保留其他配置文件生成的权限角色），只有存在差异时才更新
+func (s *ApplyService) applyUsers() (int, error) {
+	s.formatter.Info("Applying users...")
+	count := 0
+	for _, user := range s.config.Users {
+		var changed bool
+		var err error
+		// 外部用户只管理角色
+		if user.IsExternal() {
+			changed, err = s.applyExternalUser(user)
+		} else {
+			changed, err = s.applyLocalUser(user)
+		}
+		if err != nil {
+			return count, err
+		}
+		if changed {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// applyLocalUser 创建或更新本地用户，返回是否有变更
+func (s *ApplyService) applyLocalUser(user config.User) (bool, error) {
+	exists, err := s.client.UserExists(user.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check user %s: %w", user.ID, err)
+	}
+
+	req := nexus.UserRequest{
+		UserID:       user.ID,
+		FirstName:    user.FirstName,
+		LastName:     user.LastName,
+		EmailAddress: user.EmailAddress,
+		Status:       userStatus(user),
+		Roles:        s.desiredUserRoles(user),
+	}
+
+	if !exists {
+		pass, err := s.initialPassword(user)
+		if err != nil {
+			return false, err
+		}
+		req.Password = pass
+		if err := s.client.CreateUser(req); err != nil {
+			return false, fmt.Errorf("failed to create user %s: %w", user.ID, err)
+		}
+		if user.Password.Generate != nil {
+			s.generatedPasswords[user.ID] = pass
+		}
+		s.formatter.Success(fmt.Sprintf("Created user: %s (%s)", user.ID, req.Status))
+		return true, nil
+	}
+
+	// 获取现有用户信息以保留 Source 字段
+	existingUser, err := s.client.GetUser(user.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get existing user %s: %w", user.ID, err)
+	}
+	req.Source = existingUser.Source
+	req.Roles = s.keepOtherPermissionRoles(req.Roles, existingUser.Roles)
+
+	changed := false
+	if userUpToDate(existingUser, req) {
+		s.formatter.Info(fmt.Sprintf("User %s is up to date, skipping...", user.ID))
+	} else {
+		if err := s.client.UpdateUser(user.ID, req); err != nil {
+			return false, fmt.Errorf("failed to update user %s: %w", user.ID, err)
+		}
+		s.formatter.Success(fmt.Sprintf("Updated user: %s", user.ID))
+		s.reportUserChanges(existingUser, req)
+		changed = true
+	}
+
+	// 更新密码（如果提供）；生成的密码只在创建时设置，之后通过 users rotate-password 轮换
+	if user.Password.SecretRef.IsSet() {
+		passwordChanged, err := s.syncPassword(user, req.Status)
+		if err != nil {
+			return changed, err
+		}
+		changed = changed || passwordChanged
+	}
+	return changed, nil
+}
+
+// userUpToDate 检查现有用户是否已与期望的配置一致
+func userUpToDate(existing *nexus.UserResponse, req nexus.UserRequest) bool {
+	return existing.FirstName == req.FirstName &&
+		existing.LastName == req.LastName &&
+		existing.EmailAddress == req.EmailAddress &&
+		strings.EqualFold(existing.Status, req.Status) &&
+		sameStringSet(existing.Roles, req.Roles)
+}
+
+// syncPassword 以用户身份登录验证配置的密码，只有密码不再可用时才修改
+//
+// 修改密码会更新密码时间戳并写入审计日志，因此不能每次 apply 都无条件修改。
+// 非 active 状态的用户无法登录，其密码无法验证，保持不变。
+func (s *ApplyService) syncPassword(user config.User, status string) (bool, error) {
+	pass, err := user.Password.Resolve()
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve password for user %s: %w", user.ID, err)
+	}
+
+	if status != config.UserStatusActive {
+		s.formatter.Info(fmt.Sprintf("User %s is %s, password cannot be verified and is left unchanged", user.ID, status))
+		return false, nil
+	}
+
+	ok, err := s.client.WithCredentials(user.ID, pass).CheckAuthentication()
+	if err != nil {
+		s.formatter.Warning(fmt.Sprintf("Failed to verify password for user %s, leaving it unchanged: %v", user.ID, err))
+		return false, nil
+	}
+	if ok {
+		return false, nil
+	}
+
+	if err := s.client.ChangePassword(user.ID, pass); err != nil {
+		s.formatter.Warning(fmt.Sprintf("Failed to change password for user %s: %v", user.ID, err))
+		return false, nil
+	}
+	s.formatter.Success(fmt.Sprintf("Changed password for user: %s", user.ID))
+	return true, nil
+}
+
+// initialPassword 返回创建用户时使用的密码，配置了 generate 时生成随机密码
+func (s *ApplyService) initialPassword(user config.User) (string, error) {
+	if user.Password.Generate != nil {
+		pass, err := password.Generate(user.Password.Generate.Length)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate password for user %s: %w", user.ID, err)
+		}
+		return pass, nil
+	}
+	pass, err := user.Password.Resolve()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve password for user %s: %w", user.ID, err)
+	}
+	return pass, nil
+}
+
+// reportUserChanges 输出用户状态变化和角色的增删
+func (s *ApplyService) reportUserChanges(existing *nexus.UserResponse, req nexus.UserRequest) {
+	if !strings.EqualFold(existing.Status, req.Status) {
+		s.formatter.Success(fmt.Sprintf("User %s status changed: %s -> %s", req.UserID, existing.Status, req.Status))
+	}
+	for _, role := range req.Roles {
+		if !containsString(existing.Roles, role) {
+			s.formatter.Success(fmt.Sprintf("Granted role %s to user %s", role, req.UserID))
+		}
+	}
+	for _, role := range existing.Roles {
+		if !containsString(req.Roles, role) {
+			s.formatter.Success(fmt.Sprintf("Revoked role %s from user %s", role, req.UserID))
+		}
+	}
+}
+
+// containsString 检查字符串是否在列表中
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
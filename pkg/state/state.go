@@ -0,0 +1,104 @@
+// Package state stores local bookkeeping such as password rotation times.
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvStateFile 覆盖默认状态文件路径的环境变量
+const EnvStateFile = "NEXUS_CLI_STATE"
+
+// State 本地状态，按 Nexus 实例（NEXUS_URL）区分
+type State struct {
+	Instances map[string]*Instance `yaml:"instances"`
+
+	path string
+}
+
+// Instance 单个 Nexus 实例的状态
+type Instance struct {
+	Users map[string]*UserState `yaml:"users,omitempty"`
+}
+
+// UserState 用户状态
+type UserState struct {
+	PasswordRotatedAt time.Time `yaml:"passwordRotatedAt"`
+}
+
+// DefaultPath 返回默认状态文件路径：$NEXUS_CLI_STATE 或 ~/.nexus-cli/state.yaml
+func DefaultPath() string {
+	if path := os.Getenv(EnvStateFile); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".nexus-cli", "state.yaml")
+	}
+	return filepath.Join(home, ".nexus-cli", "state.yaml")
+}
+
+// Load 加载状态文件，文件不存在时返回空状态
+func Load(path string) (*State, error) {
+	s := &State{Instances: make(map[string]*Instance), path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", path, err)
+	}
+	if s.Instances == nil {
+		s.Instances = make(map[string]*Instance)
+	}
+	return s, nil
+}
+
+// PasswordRotatedAt 返回用户上次轮换密码的时间
+func (s *State) PasswordRotatedAt(instance, userID string) (time.Time, bool) {
+	inst, ok := s.Instances[instance]
+	if !ok {
+		return time.Time{}, false
+	}
+	user, ok := inst.Users[userID]
+	if !ok || user.PasswordRotatedAt.IsZero() {
+		return time.Time{}, false
+	}
+	return user.PasswordRotatedAt, true
+}
+
+// RecordPasswordRotation 记录用户密码轮换时间
+func (s *State) RecordPasswordRotation(instance, userID string, at time.Time) {
+	inst, ok := s.Instances[instance]
+	if !ok {
+		inst = &Instance{}
+		s.Instances[instance] = inst
+	}
+	if inst.Users == nil {
+		inst.Users = make(map[string]*UserState)
+	}
+	inst.Users[userID] = &UserState{PasswordRotatedAt: at.UTC()}
+}
+
+// Save 保存状态文件，权限为 0600
+func (s *State) Save() error {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	return nil
+}
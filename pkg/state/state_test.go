@@ -0,0 +1,37 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPasswordRotationRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "state.yaml")
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() missing file error = %v", err)
+	}
+	if _, ok := s.PasswordRotatedAt("http://nexus", "alice"); ok {
+		t.Fatal("PasswordRotatedAt() found rotation in empty state")
+	}
+
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.RecordPasswordRotation("http://nexus", "alice", at)
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	got, ok := loaded.PasswordRotatedAt("http://nexus", "alice")
+	if !ok || !got.Equal(at) {
+		t.Errorf("PasswordRotatedAt() = %v, %v; want %v, true", got, ok, at)
+	}
+	if _, ok := loaded.PasswordRotatedAt("http://other", "alice"); ok {
+		t.Error("PasswordRotatedAt() leaked rotation across instances")
+	}
+}
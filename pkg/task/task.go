@@ -0,0 +1,96 @@
+// Package task waits for Nexus tasks to finish and maps their results to exit codes.
+package task
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/alauda/nexus-cli/pkg/nexus"
+)
+
+// 退出码，1 保留给其他错误（连接失败、任务不存在等）
+const (
+	ExitOK       = 0
+	ExitError    = 1
+	ExitFailed   = 2
+	ExitCanceled = 3
+	ExitTimeout  = 4
+)
+
+// ExitCode 将任务最后一次运行的结果映射为退出码
+//
+// 部分 Nexus 版本会在结果后附加耗时（例如 "OK [2s]"），因此只比较开头的单词。
+func ExitCode(result string) int {
+	word := strings.ToUpper(strings.TrimSpace(result))
+	if i := strings.IndexAny(word, " ["); i >= 0 {
+		word = word[:i]
+	}
+	switch word {
+	case nexus.TaskResultOK:
+		return ExitOK
+	case nexus.TaskResultFailed:
+		return ExitFailed
+	case nexus.TaskResultCanceled, nexus.TaskResultInterrupted:
+		return ExitCanceled
+	default:
+		return ExitError
+	}
+}
+
+// Getter 获取任务的当前状态
+type Getter func(ctx context.Context, id string) (*nexus.Task, error)
+
+// WaitOptions 等待任务的选项
+type WaitOptions struct {
+	// Interval 轮询间隔，默认 5 秒
+	Interval time.Duration
+	// Previous 触发运行前的任务状态；设置后等待 lastRun 变化，避免任务尚未开始时把上一次的结果当作本次结果
+	Previous *nexus.Task
+	// Progress 每次轮询后调用
+	Progress func(*nexus.Task)
+}
+
+// Wait 轮询任务直到当前运行结束，返回结束时的任务状态
+//
+// ctx 取消或超时时返回 ctx 的错误和最后一次获取到的状态。
+func Wait(ctx context.Context, get Getter, id string, opts WaitOptions) (*nexus.Task, error) {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last *nexus.Task
+	for {
+		t, err := get(ctx, id)
+		if err != nil {
+			if ctx.Err() != nil {
+				return last, ctx.Err()
+			}
+			return last, err
+		}
+		last = t
+		if opts.Progress != nil {
+			opts.Progress(t)
+		}
+		if finished(t, opts.Previous) {
+			return t, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// finished 判断任务是否已结束运行
+func finished(t, previous *nexus.Task) bool {
+	if strings.EqualFold(t.CurrentState, nexus.TaskStateRunning) {
+		return false
+	}
+	return previous == nil || t.LastRun != previous.LastRun
+}
@@ -0,0 +1,68 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alauda/nexus-cli/pkg/nexus"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		result string
+		want   int
+	}{
+		{"OK", ExitOK},
+		{"OK [2s]", ExitOK},
+		{"Failed", ExitFailed},
+		{"CANCELED", ExitCanceled},
+		{"INTERRUPTED", ExitCanceled},
+		{"", ExitError},
+	}
+	for _, tt := range tests {
+		if got := ExitCode(tt.result); got != tt.want {
+			t.Errorf("ExitCode(%q) = %d, want %d", tt.result, got, tt.want)
+		}
+	}
+}
+
+func TestWait(t *testing.T) {
+	previous := &nexus.Task{ID: "t1", CurrentState: nexus.TaskStateWaiting, LastRun: "2024-01-01T00:00:00Z", LastRunResult: "OK"}
+	states := []nexus.Task{
+		// 触发后任务尚未开始，上一次的结果不能被当作本次结果
+		*previous,
+		{ID: "t1", CurrentState: nexus.TaskStateRunning, LastRun: "2024-01-02T00:00:00Z"},
+		{ID: "t1", CurrentState: nexus.TaskStateWaiting, LastRun: "2024-01-02T00:00:00Z", LastRunResult: "FAILED"},
+	}
+	calls := 0
+	get := func(context.Context, string) (*nexus.Task, error) {
+		t := states[min(calls, len(states)-1)]
+		calls++
+		return &t, nil
+	}
+
+	got, err := Wait(context.Background(), get, "t1", WaitOptions{Interval: time.Millisecond, Previous: previous})
+	if err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if calls != 3 || ExitCode(got.LastRunResult) != ExitFailed {
+		t.Errorf("Wait() = %+v after %d calls, want the failed run after 3 calls", got, calls)
+	}
+
+	// 没有 Previous 时任务不在运行即结束
+	calls = 0
+	if _, err := Wait(context.Background(), get, "t1", WaitOptions{Interval: time.Millisecond}); err != nil || calls != 1 {
+		t.Errorf("Wait() without previous = %v after %d calls, want 1 call", err, calls)
+	}
+
+	running := func(context.Context, string) (*nexus.Task, error) {
+		return &nexus.Task{ID: "t1", CurrentState: nexus.TaskStateRunning}, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := Wait(ctx, running, "t1", WaitOptions{Interval: time.Millisecond}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() on a running task error = %v, want deadline exceeded", err)
+	}
+}
@@ -0,0 +1,121 @@
+// Package upload plans and runs component uploads to Nexus hosted repositories.
+package upload
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// File 要上传的本地文件
+type File struct {
+	// Path 本地路径
+	Path string
+	// Rel 相对于参数目录的路径（以 / 分隔），参数为文件时为文件名
+	Rel string
+}
+
+// Collect 收集要上传的文件
+//
+// 参数为目录时递归查找其中的文件，include 和 exclude 匹配相对于该目录的路径；
+// 参数为文件时总是上传。未指定 include 时目录中的所有文件都会上传。
+func Collect(args, include, exclude []string) ([]File, error) {
+	for _, pattern := range append(append([]string{}, include...), exclude...) {
+		if _, err := path.Match(strings.ReplaceAll(pattern, "**", "*"), ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+
+	var files []File
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, File{Path: arg, Rel: filepath.Base(arg)})
+			continue
+		}
+
+		err = filepath.WalkDir(arg, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(arg, p)
+			if err != nil {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+			if (len(include) == 0 || matchAny(include, rel)) && !matchAny(exclude, rel) {
+				files = append(files, File{Path: p, Rel: rel})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if Match(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match 检查以 / 分隔的路径是否匹配 glob 模式
+//
+// ** 匹配任意层目录；不包含 / 的模式匹配文件名，例如 *.tgz 匹配任意目录中的 tgz 文件。
+func Match(pattern, name string) bool {
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(name))
+		return ok
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// SHA256 计算文件的 SHA-256 校验和
+func SHA256(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
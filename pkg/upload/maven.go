@@ -0,0 +1,217 @@
+package upload
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MavenOptions Maven 坐标，GroupID 为空时从文件中的 pom 读取
+type MavenOptions struct {
+	GroupID     string
+	ArtifactID  string
+	Version     string
+	Packaging   string
+	Classifier  string
+	GeneratePom bool
+}
+
+// mavenChecksumSuffixes Nexus 会自动生成的校验和文件，不上传
+var mavenChecksumSuffixes = []string{".md5", ".sha1", ".sha256", ".sha512"}
+
+// pomFile pom 中用于确定坐标的字段
+type pomFile struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Packaging  string `xml:"packaging"`
+	Parent     struct {
+		GroupID string `xml:"groupId"`
+		Version string `xml:"version"`
+	} `xml:"parent"`
+}
+
+// readPom 读取 pom 的坐标，groupId 和 version 未声明时继承 parent
+func readPom(file string) (MavenOptions, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return MavenOptions{}, err
+	}
+	var pom pomFile
+	if err := xml.Unmarshal(data, &pom); err != nil {
+		return MavenOptions{}, fmt.Errorf("failed to parse pom %s: %w", file, err)
+	}
+	gav := MavenOptions{GroupID: pom.GroupID, ArtifactID: pom.ArtifactID, Version: pom.Version, Packaging: pom.Packaging}
+	if gav.GroupID == "" {
+		gav.GroupID = pom.Parent.GroupID
+	}
+	if gav.Version == "" {
+		gav.Version = pom.Parent.Version
+	}
+	if gav.GroupID == "" || gav.ArtifactID == "" || gav.Version == "" {
+		return MavenOptions{}, fmt.Errorf("pom %s does not declare groupId, artifactId and version", file)
+	}
+	if strings.Contains(gav.Version, "${") {
+		return MavenOptions{}, fmt.Errorf("pom %s uses a property in its version (%s), use --maven.version", file, gav.Version)
+	}
+	return gav, nil
+}
+
+// mavenAsset Maven 组件中的一个文件
+type mavenAsset struct {
+	path       string
+	extension  string
+	classifier string
+}
+
+// splitMavenFileName 根据 artifactId-version 前缀解析文件的 classifier 和扩展名，
+// 例如 lib-1.0-sources.jar 解析为 sources 和 jar；文件名不符合约定时返回 false
+func splitMavenFileName(name, artifactID, version string) (classifier, extension string, ok bool) {
+	rest, found := strings.CutPrefix(name, artifactID+"-"+version)
+	if !found {
+		return "", "", false
+	}
+	if strings.HasPrefix(rest, "-") {
+		dot := strings.Index(rest, ".")
+		if dot < 0 {
+			return "", "", false
+		}
+		classifier, rest = rest[1:dot], rest[dot:]
+	}
+	if !strings.HasPrefix(rest, ".") || len(rest) == 1 {
+		return "", "", false
+	}
+	return classifier, rest[1:], true
+}
+
+// planMaven 生成 Maven 上传请求
+//
+// 指定了 GroupID 时所有文件属于同一个组件；否则每个 pom 确定一个组件，
+// 同一目录中以 artifactId-version 开头的文件属于该组件。
+func planMaven(files []File, opts MavenOptions) ([]Item, error) {
+	var uploadFiles []File
+	for _, f := range files {
+		if !IsChecksumFile(f.Rel) {
+			uploadFiles = append(uploadFiles, f)
+		}
+	}
+
+	if opts.GroupID != "" {
+		if opts.ArtifactID == "" || opts.Version == "" {
+			return nil, fmt.Errorf("--maven.groupId requires --maven.artifactId and --maven.version")
+		}
+		var assets []mavenAsset
+		for _, f := range uploadFiles {
+			name := path.Base(f.Rel)
+			classifier, extension, ok := splitMavenFileName(name, opts.ArtifactID, opts.Version)
+			if !ok {
+				classifier, extension = opts.Classifier, strings.TrimPrefix(path.Ext(name), ".")
+			}
+			if extension == "" {
+				return nil, fmt.Errorf("cannot determine the extension of %s", f.Path)
+			}
+			assets = append(assets, mavenAsset{path: f.Path, extension: extension, classifier: classifier})
+		}
+		return mavenItems(opts, assets), nil
+	}
+
+	// 按 pom 分组
+	type component struct {
+		gav    MavenOptions
+		dir    string
+		assets []mavenAsset
+	}
+	var components []*component
+	for _, f := range uploadFiles {
+		if !strings.HasSuffix(f.Rel, ".pom") {
+			continue
+		}
+		gav, err := readPom(f.Path)
+		if err != nil {
+			return nil, err
+		}
+		gav.GeneratePom = false
+		components = append(components, &component{gav: gav, dir: path.Dir(f.Rel),
+			assets: []mavenAsset{{path: f.Path, extension: "pom"}}})
+	}
+
+	for _, f := range uploadFiles {
+		if strings.HasSuffix(f.Rel, ".pom") {
+			continue
+		}
+		var owner *component
+		var classifier, extension string
+		for _, c := range components {
+			if c.dir != path.Dir(f.Rel) {
+				continue
+			}
+			if cl, ext, ok := splitMavenFileName(path.Base(f.Rel), c.gav.ArtifactID, c.gav.Version); ok {
+				owner, classifier, extension = c, cl, ext
+				break
+			}
+		}
+		if owner == nil {
+			return nil, fmt.Errorf("cannot determine the maven coordinates of %s: no matching pom in the same directory (use --maven.groupId, --maven.artifactId and --maven.version)", f.Path)
+		}
+		owner.assets = append(owner.assets, mavenAsset{path: f.Path, extension: extension, classifier: classifier})
+	}
+
+	var items []Item
+	for _, c := range components {
+		items = append(items, mavenItems(c.gav, c.assets)...)
+	}
+	return items, nil
+}
+
+// mavenItems 生成一个组件的上传请求，文件较多时拆分为多个请求，pom 总在第一个请求中
+func mavenItems(gav MavenOptions, assets []mavenAsset) []Item {
+	sort.SliceStable(assets, func(i, j int) bool {
+		return assets[i].extension == "pom" && assets[j].extension != "pom"
+	})
+	hasPom := len(assets) > 0 && assets[0].extension == "pom"
+
+	label := fmt.Sprintf("%s:%s:%s", gav.GroupID, gav.ArtifactID, gav.Version)
+	var items []Item
+	for start := 0; start < len(assets); start += maxAssets {
+		end := min(start+maxAssets, len(assets))
+		item := Item{
+			Label: label,
+			Fields: map[string]string{
+				"maven2.groupId":    gav.GroupID,
+				"maven2.artifactId": gav.ArtifactID,
+				"maven2.version":    gav.Version,
+			},
+			Files: map[string]string{},
+		}
+		if start == 0 && !hasPom {
+			item.Fields["maven2.generate-pom"] = strconv.FormatBool(gav.GeneratePom)
+			if gav.Packaging != "" {
+				item.Fields["maven2.packaging"] = gav.Packaging
+			}
+		}
+		for i, asset := range assets[start:end] {
+			field := fmt.Sprintf("maven2.asset%d", i+1)
+			item.Files[field] = asset.path
+			item.Fields[field+".extension"] = asset.extension
+			if asset.classifier != "" {
+				item.Fields[field+".classifier"] = asset.classifier
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// IsChecksumFile 判断文件是否为 Nexus 自动生成的 maven 校验和文件
+func IsChecksumFile(name string) bool {
+	for _, suffix := range mavenChecksumSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
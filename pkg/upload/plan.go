@@ -0,0 +1,125 @@
+package upload
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+)
+
+// Item 一次上传请求（一个组件）
+type Item struct {
+	// Label 输出中显示的描述，例如 com.example:lib:1.0 或文件路径
+	Label string
+	// Fields 表单字段
+	Fields map[string]string
+	// Files 文件字段到文件路径的映射，通常为本地路径，迁移时为源仓库中的资产路径
+	Files map[string]string
+}
+
+// Options 格式相关的上传选项
+type Options struct {
+	// Directory raw 和 yum 仓库中的目标目录，文件相对于参数目录的子目录会保留
+	Directory string
+	Maven     MavenOptions
+}
+
+// maxAssets 单个请求中的最大文件数
+const maxAssets = 3
+
+// singleAssetFormats 每个组件只有一个文件、且不需要其他字段的格式
+var singleAssetFormats = map[string]bool{
+	"npm":      true,
+	"pypi":     true,
+	"helm":     true,
+	"nuget":    true,
+	"rubygems": true,
+	"apt":      true,
+}
+
+// Formats 返回支持上传的格式
+func Formats() []string {
+	return []string{"maven2", "raw", "yum", "npm", "pypi", "helm", "nuget", "rubygems", "apt"}
+}
+
+// Plan 将文件转换为上传请求
+func Plan(format string, files []File, opts Options) ([]Item, error) {
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files to upload")
+	}
+	switch {
+	case format == "maven2":
+		return planMaven(files, opts.Maven)
+	case format == "raw":
+		return planDirectory("raw", "raw.asset1", files, opts.Directory), nil
+	case format == "yum":
+		return planDirectory("yum", "yum.asset", files, opts.Directory), nil
+	case singleAssetFormats[format]:
+		var items []Item
+		for _, f := range files {
+			items = append(items, Item{
+				Label: f.Path,
+				Files: map[string]string{format + ".asset": f.Path},
+			})
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("uploading %s components is not supported (supported formats: %s)", format, strings.Join(Formats(), ", "))
+	}
+}
+
+// planDirectory raw 和 yum 按目录上传，每个文件一个请求，文件的子目录保留在目标目录下
+func planDirectory(format, assetField string, files []File, directory string) []Item {
+	var items []Item
+	for _, f := range files {
+		dir := path.Join("/", directory, path.Dir(f.Rel))
+		name := path.Base(f.Rel)
+		items = append(items, Item{
+			Label: path.Join(dir, name),
+			Fields: map[string]string{
+				format + ".directory":    dir,
+				assetField + ".filename": name,
+			},
+			Files: map[string]string{assetField: f.Path},
+		})
+	}
+	return items
+}
+
+// Result 上传结果
+type Result struct {
+	Item Item
+	Err  error
+}
+
+// Run 使用最多 workers 个并发执行上传，返回与 items 顺序一致的结果
+//
+// 单个上传失败不会中止其他上传；ctx 取消后尚未开始的上传返回 ctx 的错误。
+func Run(ctx context.Context, items []Item, workers int, upload func(context.Context, Item) error) []Result {
+	if workers < 1 {
+		workers = 1
+	}
+	results := make([]Result, len(items))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				err := ctx.Err()
+				if err == nil {
+					err = upload(ctx, items[i])
+				}
+				results[i] = Result{Item: items[i], Err: err}
+			}
+		}()
+	}
+	for i := range items {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
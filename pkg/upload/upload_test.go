@@ -0,0 +1,143 @@
+package upload
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*.tgz", "a.tgz", true},
+		{"*.tgz", "sub/dir/a.tgz", true},
+		{"*.tgz", "a.tar", false},
+		{"docs/*.md", "docs/a.md", true},
+		{"docs/*.md", "docs/sub/a.md", false},
+		{"docs/**/*.md", "docs/a.md", true},
+		{"docs/**/*.md", "docs/sub/deep/a.md", true},
+		{"**/target/**", "a/target/x.jar", true},
+		{"**/target/**", "a/src/x.jar", false},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPlan(t *testing.T) {
+	dir := t.TempDir()
+	pom := `<project>
+  <parent><groupId>com.example</groupId><version>1.0</version></parent>
+  <artifactId>lib</artifactId>
+  <packaging>jar</packaging>
+</project>`
+	for name, content := range map[string]string{
+		"lib-1.0.pom":         pom,
+		"lib-1.0.jar":         "jar",
+		"lib-1.0-sources.jar": "sources",
+		"lib-1.0.jar.sha1":    "sha1",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	file := func(name string) File { return File{Path: filepath.Join(dir, name), Rel: name} }
+
+	t.Run("maven from pom", func(t *testing.T) {
+		files := []File{file("lib-1.0-sources.jar"), file("lib-1.0.jar"), file("lib-1.0.jar.sha1"), file("lib-1.0.pom")}
+		items, err := Plan("maven2", files, Options{})
+		if err != nil {
+			t.Fatalf("Plan() error = %v", err)
+		}
+		if len(items) != 1 {
+			t.Fatalf("Plan() returned %d items, want 1", len(items))
+		}
+		wantFields := map[string]string{
+			"maven2.groupId":           "com.example",
+			"maven2.artifactId":        "lib",
+			"maven2.version":           "1.0",
+			"maven2.asset1.extension":  "pom",
+			"maven2.asset2.extension":  "jar",
+			"maven2.asset2.classifier": "sources",
+			"maven2.asset3.extension":  "jar",
+		}
+		if !reflect.DeepEqual(items[0].Fields, wantFields) {
+			t.Errorf("Fields = %v, want %v", items[0].Fields, wantFields)
+		}
+		if items[0].Files["maven2.asset1"] != filepath.Join(dir, "lib-1.0.pom") {
+			t.Errorf("asset1 = %s, want the pom", items[0].Files["maven2.asset1"])
+		}
+	})
+
+	t.Run("maven without pom", func(t *testing.T) {
+		_, err := Plan("maven2", []File{file("lib-1.0.jar")}, Options{})
+		if err == nil {
+			t.Fatal("Plan() expected an error for a jar without pom")
+		}
+
+		opts := Options{Maven: MavenOptions{GroupID: "com.example", ArtifactID: "other", Version: "2.0", GeneratePom: true}}
+		items, err := Plan("maven2", []File{file("lib-1.0.jar")}, opts)
+		if err != nil {
+			t.Fatalf("Plan() error = %v", err)
+		}
+		if got := items[0].Fields["maven2.generate-pom"]; got != "true" {
+			t.Errorf("generate-pom = %q, want true", got)
+		}
+		if got := items[0].Fields["maven2.asset1.extension"]; got != "jar" {
+			t.Errorf("extension = %q, want jar", got)
+		}
+	})
+
+	t.Run("raw keeps subdirectories", func(t *testing.T) {
+		files := []File{{Path: "/tmp/site/index.html", Rel: "index.html"}, {Path: "/tmp/site/css/a.css", Rel: "css/a.css"}}
+		items, err := Plan("raw", files, Options{Directory: "docs"})
+		if err != nil {
+			t.Fatalf("Plan() error = %v", err)
+		}
+		var got []string
+		for _, item := range items {
+			got = append(got, item.Fields["raw.directory"]+" "+item.Fields["raw.asset1.filename"])
+		}
+		want := []string{"/docs index.html", "/docs/css a.css"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("raw plan = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		if _, err := Plan("docker", []File{file("lib-1.0.jar")}, Options{}); err == nil {
+			t.Error("Plan() expected an error for docker")
+		}
+	})
+}
+
+func TestRun(t *testing.T) {
+	var items []Item
+	for i := 0; i < 10; i++ {
+		items = append(items, Item{Label: fmt.Sprint(i)})
+	}
+	results := Run(context.Background(), items, 3, func(_ context.Context, item Item) error {
+		time.Sleep(time.Millisecond)
+		if item.Label == "4" {
+			return fmt.Errorf("failed")
+		}
+		return nil
+	})
+	for i, result := range results {
+		if result.Item.Label != fmt.Sprint(i) {
+			t.Errorf("results[%d] = %s, want %d", i, result.Item.Label, i)
+		}
+		if (result.Err != nil) != (i == 4) {
+			t.Errorf("results[%d].Err = %v", i, result.Err)
+		}
+	}
+}